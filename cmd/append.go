@@ -5,13 +5,15 @@ import (
 )
 
 func newAppendCmd(ctx *Context) *cobra.Command {
+	var staged bool
 	command := &cobra.Command{
 		Use:     "append",
 		Aliases: []string{"ap"},
 		Short:   "Append all local changes to the last commit",
 		Run: func(cmd *cobra.Command, args []string) {
-			ctx.Assistant.Append()
+			ctx.Assistant.Append(staged)
 		},
 	}
+	command.Flags().BoolVar(&staged, "staged", false, "append only the staged changes")
 	return command
 }
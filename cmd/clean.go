@@ -5,13 +5,21 @@ import (
 )
 
 func newCleanCmd(ctx *Context) *cobra.Command {
+	var responses bool
+	var summaries bool
+	var expired bool
+	var model string
 	command := &cobra.Command{
 		Use:     "clean",
 		Aliases: []string{"cl"},
 		Short:   "Clean the aidy cache",
-		Run: func(cmd *cobra.Command, args []string) {
-			ctx.Assistant.Clean()
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ctx.Assistant.Clean(responses, summaries, expired, model)
 		},
 	}
+	command.Flags().BoolVar(&responses, "responses", false, "remove only the cached AI answers")
+	command.Flags().BoolVar(&summaries, "summaries", false, "remove only the cached summaries of large diffs")
+	command.Flags().BoolVar(&expired, "expired", false, "remove only the cached AI answers older than 'cache-ttl'")
+	command.Flags().StringVar(&model, "model", "", "remove only the cached AI answers of the model, e.g. 'openai/gpt-4o'")
 	return command
 }
@@ -6,14 +6,21 @@ import (
 
 func newCommitCmd(ctx *Context) *cobra.Command {
 	var issue bool
+	var review bool
+	var staged bool
+	var paths []string
 	command := &cobra.Command{
 		Use:     "commit",
 		Aliases: []string{"ci"},
 		Short:   "Make a commit with AI-generated message",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return ctx.Assistant.Commit(issue)
+			return ctx.Assistant.Commit(issue, review, staged, paths)
 		},
 	}
 	command.Flags().BoolVarP(&issue, "issue", "i", false, "use issue description to genearate commit message")
+	command.Flags().BoolVarP(&review, "review", "r", false, "review the commit message before committing")
+	command.Flags().BoolVar(&staged, "staged", false, "commit only the staged changes")
+	command.Flags().StringSliceVar(&paths, "paths", nil, "commit only the changes in the given paths")
+	command.MarkFlagsMutuallyExclusive("staged", "paths")
 	return command
 }
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -31,3 +32,36 @@ func TestCommit_Execution(t *testing.T) {
 	require.NoError(t, err, "no error expected")
 	assert.Contains(t, mock.Logs(), "Commit called")
 }
+
+func TestCommit_Review(t *testing.T) {
+	mock := aidy.NewMock()
+	command := newCommitCmd(&Context{Assistant: mock})
+	command.SetArgs([]string{"--review"})
+
+	err := command.Execute()
+
+	require.NoError(t, err, "no error expected")
+	assert.Contains(t, mock.Logs(), "Commit review requested")
+}
+
+func TestCommit_Paths(t *testing.T) {
+	mock := aidy.NewMock()
+	command := newCommitCmd(&Context{Assistant: mock})
+	command.SetArgs([]string{"--paths", "cmd,README.md"})
+
+	err := command.Execute()
+
+	require.NoError(t, err, "no error expected")
+	assert.Contains(t, mock.Logs(), "Commit of paths requested: cmd, README.md")
+}
+
+func TestCommit_StagedWithPaths(t *testing.T) {
+	command := newCommitCmd(&Context{Assistant: aidy.NewMock()})
+	command.SetArgs([]string{"--staged", "--paths", "cmd"})
+	command.SetOut(io.Discard)
+	command.SetErr(io.Discard)
+
+	err := command.Execute()
+
+	require.Error(t, err, "expected --staged and --paths to be mutually exclusive")
+}
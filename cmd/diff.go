@@ -5,13 +5,29 @@ import (
 )
 
 func newDiffCmd(ctx *Context) *cobra.Command {
+	var committed bool
+	var staged bool
+	var worktree bool
 	command := &cobra.Command{
 		Use:     "diff",
 		Aliases: []string{"df"},
 		Short:   "Print the current diff that will be used to generate the commit message",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return ctx.Assistant.Diff()
+			var mode string
+			switch {
+			case committed:
+				mode = "committed"
+			case staged:
+				mode = "staged"
+			case worktree:
+				mode = "worktree"
+			}
+			return ctx.Assistant.Diff(mode)
 		},
 	}
+	command.Flags().BoolVar(&committed, "committed", false, "show only the commits of the branch")
+	command.Flags().BoolVar(&staged, "staged", false, "show the commits and the staged changes")
+	command.Flags().BoolVar(&worktree, "worktree", false, "show the commits and all changes of the working tree")
+	command.MarkFlagsMutuallyExclusive("committed", "staged", "worktree")
 	return command
 }
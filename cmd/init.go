@@ -17,9 +17,15 @@ var defaultModelIDs = map[string]string{
 	"deepseek":  "deepseek-chat",
 	"openai":    "gpt-4o",
 	"anthropic": "claude-sonnet-4-6",
+	"ollama":    "llama3.2",
 }
 
-var providers = []string{"deepseek", "openai", "anthropic"}
+var providers = []string{"deepseek", "openai", "anthropic", "ollama"}
+
+// local providers run on the user's machine and need a server address instead of an API key.
+var local = map[string]string{
+	"ollama": "http://localhost:11434",
+}
 
 func newInitCmd() *cobra.Command {
 	command := &cobra.Command{
@@ -59,27 +65,33 @@ func runInit(in io.Reader, out io.Writer, path string) error {
 	if err != nil {
 		return err
 	}
-	key, err := askRequired(reader, out, fmt.Sprintf("%s API key", provider))
-	if err != nil {
-		return err
+	settings := map[string]string{
+		"provider": provider,
+		"model-id": model,
+	}
+	apiKeys := map[string]string{}
+	if url, ok := local[provider]; ok {
+		if settings["base-url"], err = ask(reader, out, fmt.Sprintf("%s base URL", provider), url); err != nil {
+			return err
+		}
+	} else {
+		key, err := askRequired(reader, out, fmt.Sprintf("%s API key", provider))
+		if err != nil {
+			return err
+		}
+		apiKeys[provider] = key
 	}
 	github, err := ask(reader, out, "GitHub API key (optional, press enter to skip)", "")
 	if err != nil {
 		return err
 	}
-	apiKeys := map[string]string{provider: key}
 	if github != "" {
 		apiKeys["github"] = github
 	}
 	conf := &config.YamlConfig{
 		DefaultModel: provider,
 		APIKeys:      apiKeys,
-		Models: map[string]map[string]string{
-			provider: {
-				"provider": provider,
-				"model-id": model,
-			},
-		},
+		Models:       map[string]map[string]string{provider: settings},
 	}
 	if err := config.WriteYaml(path, conf); err != nil {
 		return fmt.Errorf("error writing configuration file: %v", err)
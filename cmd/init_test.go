@@ -82,6 +82,28 @@ func TestRunInit_CreatesConfigFileByProviderName(t *testing.T) {
 	assert.Equal(t, "sk-test-anthropic-key", token)
 }
 
+func TestRunInit_AsksForBaseUrlOfLocalProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".aidy.conf.yml")
+	in := strings.NewReader("ollama\n\nhttp://gpu-box:11434\n\n")
+	var out bytes.Buffer
+
+	err := runInit(in, &out, path)
+
+	require.NoError(t, err, "no error expected")
+	assert.NotContains(t, out.String(), "API key:", "no API key should be asked for a local provider")
+	conf, err := config.YamlConf(path)
+	require.NoError(t, err, "config file should be readable")
+	model, err := conf.Model()
+	require.NoError(t, err)
+	assert.Equal(t, "llama3.2", model, "should fall back to the default model id")
+	url, err := conf.BaseUrl()
+	require.NoError(t, err)
+	assert.Equal(t, "http://gpu-box:11434", url)
+	token, err := conf.Token()
+	require.NoError(t, err)
+	assert.Empty(t, token, "no token should be stored for a local provider")
+}
+
 func TestRunInit_GithubKeyIsOptional(t *testing.T) {
 	path := filepath.Join(t.TempDir(), ".aidy.conf.yml")
 	in := strings.NewReader("2\n\nsk-test-openai-key\n")
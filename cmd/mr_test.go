@@ -67,3 +67,15 @@ func TestMr_ExecutionWithDuplicateSource(t *testing.T) {
 	require.NoError(t, err, "no error expected")
 	assert.Contains(t, mock.Logs(), "MergeRequest called")
 }
+
+func TestMr_ExecutionWithTemplate(t *testing.T) {
+	mock := aidy.NewMock()
+	ctx := &Context{Assistant: mock}
+	command := newMrCmd(ctx)
+	command.SetArgs([]string{"--template", "bug"})
+
+	err := command.Execute()
+
+	require.NoError(t, err, "no error expected")
+	assert.Contains(t, mock.Logs(), "MergeRequest called")
+}
@@ -2,24 +2,27 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/volodya-lombrozo/aidy/internal/aidy"
 )
 
 func newPrCmd(ctx *Context) *cobra.Command {
-	fixes := false
-	target := ""
-	duplicate := false
-	source := ""
+	var opts aidy.PullRequestOptions
 	command := &cobra.Command{
 		Use:     "pull-request",
 		Aliases: []string{"pr"},
 		Short:   "Create a PR based on changes in the current branch",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return ctx.Assistant.PullRequest(fixes, target, duplicate, source)
+			return ctx.Assistant.PullRequest(opts)
 		},
 	}
-	command.Flags().BoolVarP(&fixes, "fixes", "f", false, "Create a PR with 'fixes' keyword")
-	command.Flags().StringVarP(&target, "target", "t", "", "Target branch for the PR")
-	command.Flags().BoolVar(&duplicate, "duplicate", false, "Reuse an existing PR's title and body against --target")
-	command.Flags().StringVar(&source, "source", "", "Branch whose existing PR to duplicate (defaults to the current branch)")
+	command.Flags().BoolVarP(&opts.Fixes, "fixes", "f", false, "Create a PR with 'fixes' keyword")
+	command.Flags().StringVarP(&opts.Target, "target", "t", "", "Target branch for the PR")
+	command.Flags().BoolVar(&opts.Duplicate, "duplicate", false, "Reuse an existing PR's title and body against --target")
+	command.Flags().StringVar(&opts.Source, "source", "", "Branch whose existing PR to duplicate (defaults to the current branch)")
+	command.Flags().StringVar(&opts.Template, "template", "", "Name of the repository PR template to fill in, e.g. 'bug' for 'Bug.md'")
+	command.Flags().BoolVar(&opts.Draft, "draft", false, "Create a draft PR")
+	command.Flags().StringSliceVar(&opts.Reviewers, "reviewer", nil, "Request a review from people or teams by their handles, besides the code owners of the changes")
+	command.Flags().StringSliceVar(&opts.Assignees, "assignee", nil, "Assign people by their logins")
+	command.Flags().StringVar(&opts.Milestone, "milestone", "", "Add the PR to a milestone by its title or number")
 	return command
 }
@@ -67,3 +67,27 @@ func TestPr_ExecutionWithDuplicateSource(t *testing.T) {
 	require.NoError(t, err, "no error expected")
 	assert.Contains(t, mock.Logs(), "PullRequest called")
 }
+
+func TestPr_ExecutionWithTemplate(t *testing.T) {
+	mock := aidy.NewMock()
+	ctx := &Context{Assistant: mock}
+	command := newPrCmd(ctx)
+	command.SetArgs([]string{"--template", "bug"})
+
+	err := command.Execute()
+
+	require.NoError(t, err, "no error expected")
+	assert.Contains(t, mock.Logs(), "PullRequest called")
+}
+
+func TestPr_ExecutionWithDraftAndReviewers(t *testing.T) {
+	mock := aidy.NewMock()
+	ctx := &Context{Assistant: mock}
+	command := newPrCmd(ctx)
+	command.SetArgs([]string{"--draft", "--reviewer", "alice,bob", "--assignee", "carol", "--milestone", "v1.0"})
+
+	err := command.Execute()
+
+	require.NoError(t, err, "no error expected")
+	assert.Contains(t, mock.Logs(), "PullRequest called")
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/volodya-lombrozo/aidy/internal/ai"
+	"github.com/volodya-lombrozo/aidy/internal/executor"
+	"github.com/volodya-lombrozo/aidy/internal/git"
+)
+
+func newPromptsCmd() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "prompts",
+		Short: "Manage the prompt templates sent to AI",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+	command.AddCommand(newPromptsDumpCmd())
+	return command
+}
+
+func newPromptsDumpCmd() *cobra.Command {
+	var dir string
+	var force bool
+	command := &cobra.Command{
+		Use:   "dump",
+		Short: "Write the built-in prompt templates to a folder as a starting point",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if dir == "" {
+				repo, err := git.NewGit(executor.NewReal())
+				if err != nil {
+					return err
+				}
+				if dir, err = promptsDir(repo); err != nil {
+					return err
+				}
+			}
+			return dumpPrompts(cmd.OutOrStdout(), dir, force)
+		},
+	}
+	command.Flags().StringVar(&dir, "dir", "", "folder to write the templates to (default '.aidy/prompts' of the repository)")
+	command.Flags().BoolVarP(&force, "force", "f", false, "overwrite existing templates")
+	return command
+}
+
+// promptsDir returns the '.aidy/prompts' folder of the repository, where aidy looks for templates.
+func promptsDir(repo git.Git) (string, error) {
+	root, err := repo.Root()
+	if err != nil {
+		return "", fmt.Errorf("error getting repository root: %v", err)
+	}
+	return filepath.Join(root, ".aidy", "prompts"), nil
+}
+
+func dumpPrompts(out io.Writer, dir string, force bool) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error creating folder '%s': %v", dir, err)
+	}
+	defaults := ai.Defaults()
+	for _, name := range ai.Names() {
+		path := filepath.Join(dir, name+".tmpl")
+		if _, err := os.Stat(path); err == nil && !force {
+			if err := printf(out, "skipped: %s already exists\n", path); err != nil {
+				return err
+			}
+			continue
+		} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("error checking '%s': %v", path, err)
+		}
+		if err := os.WriteFile(path, []byte(defaults[name]), 0644); err != nil {
+			return fmt.Errorf("error writing '%s': %v", path, err)
+		}
+		if err := printf(out, "written: %s\n", path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
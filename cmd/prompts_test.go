@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volodya-lombrozo/aidy/internal/ai"
+	"github.com/volodya-lombrozo/aidy/internal/git"
+)
+
+func TestPrompts_Help(t *testing.T) {
+	var out bytes.Buffer
+	command := newPromptsCmd()
+	command.SetOut(&out)
+	command.SetArgs([]string{"dump", "--help"})
+
+	err := command.Execute()
+
+	require.NoError(t, err, "no error expected")
+	assert.Contains(t, out.String(), "Write the built-in prompt templates")
+}
+
+func TestPrompts_DumpsDefaults(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "prompts")
+	var out bytes.Buffer
+	command := newPromptsCmd()
+	command.SetOut(&out)
+	command.SetArgs([]string{"dump", "--dir", dir})
+
+	err := command.Execute()
+
+	require.NoError(t, err, "no error expected")
+	for name, text := range ai.Defaults() {
+		content, err := os.ReadFile(filepath.Join(dir, name+".tmpl"))
+		require.NoError(t, err, "template '%s' should be written", name)
+		assert.Equal(t, text, string(content))
+	}
+	assert.Contains(t, out.String(), "written: "+filepath.Join(dir, "commit.tmpl"))
+}
+
+func TestPrompts_KeepsExistingTemplates(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "commit.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte("mine"), 0644))
+	var out bytes.Buffer
+
+	err := dumpPrompts(&out, dir, false)
+
+	require.NoError(t, err, "no error expected")
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "mine", string(content), "existing template should be kept")
+	assert.Contains(t, out.String(), "skipped: "+path+" already exists")
+}
+
+func TestPrompts_OverwritesWithForce(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "commit.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte("mine"), 0644))
+
+	err := dumpPrompts(&bytes.Buffer{}, dir, true)
+
+	require.NoError(t, err, "no error expected")
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, ai.CommitMsg, string(content), "existing template should be overwritten")
+}
+
+func TestPrompts_DirIsInRepositoryRoot(t *testing.T) {
+	root := t.TempDir()
+
+	dir, err := promptsDir(git.NewMockWithDir(root))
+
+	require.NoError(t, err, "no error expected")
+	assert.Equal(t, filepath.Join(root, ".aidy", "prompts"), dir)
+}
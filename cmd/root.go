@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"context"
+	"os"
+	"os/signal"
+
 	"github.com/spf13/cobra"
 	"github.com/volodya-lombrozo/aidy/internal/aidy"
 )
@@ -10,14 +14,16 @@ type Context struct {
 }
 
 func Execute() error {
-	return NewRootCmd(Real).Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return NewRootCmd(Real).ExecuteContext(ctx)
 }
 
-func Real(summary, aider, ailess, silent, debug bool, language string) aidy.Aidy {
-	return aidy.NewAidy(summary, aider, ailess, silent, debug, language)
+func Real(ctx context.Context, summary, aider, ailess, silent, debug bool, language string, stream, fresh bool, base string) aidy.Aidy {
+	return aidy.NewAidy(ctx, summary, aider, ailess, silent, debug, language, stream, fresh, base)
 }
 
-func NewRootCmd(create func(bool, bool, bool, bool, bool, string) aidy.Aidy) *cobra.Command {
+func NewRootCmd(create func(context.Context, bool, bool, bool, bool, bool, string, bool, bool, string) aidy.Aidy) *cobra.Command {
 	var ctx Context
 	var ailess bool
 	var aider bool
@@ -25,6 +31,9 @@ func NewRootCmd(create func(bool, bool, bool, bool, bool, string) aidy.Aidy) *co
 	var silent bool
 	var debug bool
 	var language string
+	var stream bool
+	var fresh bool
+	var base string
 	root := &cobra.Command{
 		Use:     "aidy",
 		Short:   "aidy - ai-powered github cli helper",
@@ -32,7 +41,7 @@ func NewRootCmd(create func(bool, bool, bool, bool, bool, string) aidy.Aidy) *co
 		Version: Version,
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
 			cmd.Root().SilenceUsage = true
-			ctx.Assistant = create(summary, aider, ailess, silent, debug, language)
+			ctx.Assistant = create(cmd.Context(), summary, aider, ailess, silent, debug, language, stream, fresh, base)
 		},
 	}
 	root.PersistentFlags().BoolVarP(&ailess, "no-ai", "n", false, "don't use AI")
@@ -41,8 +50,12 @@ func NewRootCmd(create func(bool, bool, bool, bool, bool, string) aidy.Aidy) *co
 	root.PersistentFlags().BoolVarP(&silent, "quiet", "q", false, "be silent, don't print logs")
 	root.PersistentFlags().BoolVarP(&debug, "debug", "d", false, "print debug logs")
 	root.PersistentFlags().StringVarP(&language, "language", "l", "en", "language for AI-generated text (e.g. fr, de, ja)")
+	root.PersistentFlags().BoolVar(&stream, "stream", false, "print AI answers as they arrive")
+	root.PersistentFlags().BoolVar(&fresh, "fresh", false, "ask AI again instead of using its cached answers")
+	root.PersistentFlags().StringVar(&base, "base", "", "branch to compare changes with (e.g. develop), found automatically if empty")
 	root.AddCommand(
 		newInitCmd(),
+		newPromptsCmd(),
 		newCommitCmd(&ctx),
 		newIssueCmd(&ctx),
 		newReleaseCmd(&ctx),
@@ -50,6 +63,7 @@ func NewRootCmd(create func(bool, bool, bool, bool, bool, string) aidy.Aidy) *co
 		newMrCmd(&ctx),
 		newHealCmd(&ctx),
 		newSquashCmd(&ctx),
+		newSplitCmd(&ctx),
 		newAppendCmd(&ctx),
 		newConfigCmd(&ctx),
 		newCleanCmd(&ctx),
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"testing"
 
 	"github.com/volodya-lombrozo/aidy/internal/aidy"
@@ -40,7 +41,7 @@ func TestRootCmd_PrintsUsageOnBadInput(t *testing.T) {
 }
 
 func TestRootCmd_SilencesUsageOnRuntimeError(t *testing.T) {
-	failing := func(summary, aider, ailess, silent, debug bool, language string) aidy.Aidy {
+	failing := func(ctx context.Context, summary, aider, ailess, silent, debug bool, language string, stream, fresh bool, base string) aidy.Aidy {
 		return aidy.NewFailingMock()
 	}
 	var out bytes.Buffer
@@ -53,6 +54,38 @@ func TestRootCmd_SilencesUsageOnRuntimeError(t *testing.T) {
 	assert.NotContains(t, out.String(), "Usage:", "usage should not be printed on runtime errors")
 }
 
-func mock(summary, aider, ailess, silent, debug bool, language string) aidy.Aidy {
+func TestRootCmd_PassesStreamFlag(t *testing.T) {
+	var streamed bool
+	create := func(ctx context.Context, summary, aider, ailess, silent, debug bool, language string, stream, fresh bool, base string) aidy.Aidy {
+		streamed = stream
+		return aidy.NewMock()
+	}
+	command := NewRootCmd(create)
+	command.SetOut(&bytes.Buffer{})
+	command.SetArgs([]string{"--stream", "commit"})
+
+	err := command.Execute()
+
+	require.NoError(t, err, "no error expected")
+	assert.True(t, streamed, "expected --stream to be passed to the assistant")
+}
+
+func TestRootCmd_PassesFreshFlag(t *testing.T) {
+	var refreshed bool
+	create := func(ctx context.Context, summary, aider, ailess, silent, debug bool, language string, stream, fresh bool, base string) aidy.Aidy {
+		refreshed = fresh
+		return aidy.NewMock()
+	}
+	command := NewRootCmd(create)
+	command.SetOut(&bytes.Buffer{})
+	command.SetArgs([]string{"--fresh", "commit"})
+
+	err := command.Execute()
+
+	require.NoError(t, err, "no error expected")
+	assert.True(t, refreshed, "expected --fresh to be passed to the assistant")
+}
+
+func mock(ctx context.Context, summary, aider, ailess, silent, debug bool, language string, stream, fresh bool, base string) aidy.Aidy {
 	return aidy.NewMock()
 }
@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newSplitCmd(ctx *Context) *cobra.Command {
+	var issue bool
+	command := &cobra.Command{
+		Use:     "split",
+		Aliases: []string{"sp"},
+		Short:   "Split all local changes into several commits planned by AI",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ctx.Assistant.Split(issue)
+		},
+	}
+	command.Flags().BoolVarP(&issue, "issue", "i", false, "use issue description to generate commit messages")
+	return command
+}
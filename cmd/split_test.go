@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volodya-lombrozo/aidy/internal/aidy"
+)
+
+func TestSplit_Help(t *testing.T) {
+	var out bytes.Buffer
+	command := newSplitCmd(&Context{})
+	command.SetOut(&out)
+	command.SetArgs([]string{"--help"})
+
+	err := command.Execute()
+
+	require.NoError(t, err, "no error expected")
+	assert.Contains(t, out.String(), "Split all local changes into several commits planned by AI")
+}
+
+func TestSplit_Execution(t *testing.T) {
+	mock := aidy.NewMock()
+	command := newSplitCmd(&Context{Assistant: mock})
+
+	err := command.Execute()
+
+	require.NoError(t, err, "no error expected")
+	assert.Contains(t, mock.Logs(), "Split called")
+}
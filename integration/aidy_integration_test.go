@@ -19,6 +19,9 @@ api-keys:
   openai: xxx
   github: yyy
   deepseek: zzz
+
+convention:
+  lint: false
 `
 
 func TestAidyCommit_WithoutRemote(t *testing.T) {
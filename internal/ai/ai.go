@@ -3,24 +3,27 @@ package ai
 import "fmt"
 
 type AI interface {
-	PrTitle(number, diff, issue, summary string) (string, error)
-	PrBody(diff, issue, summary string) (string, error)
+	PrTitle(number, diff, issue, summary, commits string) (string, error)
+	PrBody(diff, issue, summary, commits, form string) (string, error)
 	IssueTitle(input, summary string) (string, error)
 	IssueBody(input, summary string) (string, error)
 	IssueLabels(issue string, available []string) ([]string, error)
-	CommitMessage(number, diff, descr string) (string, error)
+	CommitMessage(number, diff, descr, hint string) (string, error)
+	CommitPlan(number, changes, descr string) (string, error)
+	DiffSummary(diff string) (string, error)
 	Summary(readme string) (string, error)
 	SuggestBranch(descr string) (string, error)
 	ReleaseNotes(changes string) (string, error)
 }
 
-func trimPrompt(prompt string) string {
-	limit := 120 * 400
-	runes := []rune(prompt)
-	if len(runes) > limit {
-		return string(runes[:limit])
-	}
-	return prompt
+// StatusError is returned when an AI provider answers with an unsuccessful HTTP status.
+type StatusError struct {
+	Code int
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("API error: %s", e.Body)
 }
 
 func appendSummary(prompt, summary string) string {
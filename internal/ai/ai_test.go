@@ -1,7 +1,6 @@
 package ai
 
 import (
-	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -48,26 +47,6 @@ func TestAppendSummary(t *testing.T) {
 	}
 }
 
-func TestTrimPrompt(t *testing.T) {
-	prompt := largePrompt()
-
-	trimmed := trimPrompt(prompt)
-
-	assert.Equal(t, 48_000, len(trimmed))
-}
-
-func TestDoesNotTrimPrompt(t *testing.T) {
-	prompt := "Simple Prompt"
-
-	trimmed := trimPrompt(prompt)
-
-	assert.Equal(t, "Simple Prompt", trimmed)
-}
-
-func largePrompt() string {
-	return strings.Repeat(string('a'), 100*500)
-}
-
 func TestAppendIssue_WhenDescriptionIsEmpty_ReturnsPromptUnchanged(t *testing.T) {
 	prompt := "Do something important"
 	desc := ""
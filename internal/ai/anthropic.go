@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/volodya-lombrozo/aidy/internal/log"
+	"github.com/volodya-lombrozo/aidy/internal/tokens"
 )
 
 const anthropicDefaultModel = "claude-sonnet-4-6"
@@ -21,6 +22,10 @@ type Anthropic struct {
 	model    string
 	summary  bool
 	language string
+	stream   io.Writer
+	client   *Client
+	limit    tokens.Limit
+	prompts  *Prompts
 	log      log.Logger
 }
 
@@ -29,6 +34,7 @@ type anthropicRequest struct {
 	MaxTokens int           `json:"max_tokens"`
 	System    string        `json:"system"`
 	Messages  []chatMessage `json:"messages"`
+	Stream    bool          `json:"stream,omitempty"`
 }
 
 type anthropicContent struct {
@@ -40,48 +46,89 @@ type anthropicResponse struct {
 	Content []anthropicContent `json:"content"`
 }
 
-func NewAnthropic(token, model string, summary bool, language string) AI {
+type anthropicEvent struct {
+	Type  string           `json:"type"`
+	Delta anthropicContent `json:"delta"`
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewAnthropic creates an Anthropic client.
+// If stream is not nil, answers are requested as a stream and rendered to it as they arrive.
+// A positive window overrides the known context window of the model.
+// If client is nil, every request is sent once without a timeout.
+// If prompts is nil, the built-in templates are used.
+func NewAnthropic(token, model string, window int, summary bool, language string, stream io.Writer, client *Client, prompts *Prompts) AI {
 	if model == "" {
 		model = anthropicDefaultModel
 	}
+	if client == nil {
+		client = once()
+	}
+	if prompts == nil {
+		prompts = NewPrompts()
+	}
 	return &Anthropic{
 		token:    token,
 		url:      "https://api.anthropic.com/v1/messages",
 		model:    model,
 		summary:  summary,
 		language: language,
+		stream:   stream,
+		client:   client,
+		limit:    tokens.For("anthropic", model, window),
+		prompts:  prompts,
 		log:      log.Default(),
 	}
 }
 
 func (a *Anthropic) ReleaseNotes(changes string) (string, error) {
-	prompt := fmt.Sprintf(ReleaseNotes, changes)
+	prompt, err := a.prompts.Render("release-notes", Prompt{Commits: changes, Language: a.language})
+	if err != nil {
+		return "", err
+	}
 	return a.send("You are a helpful assistant generating GitHub release notes.", prompt, "")
 }
 
-func (a *Anthropic) PrTitle(number, diff, issue, summary string) (string, error) {
-	prompt := fmt.Sprintf(PrTitle, diff, issue, number, number)
+func (a *Anthropic) PrTitle(number, diff, issue, summary, commits string) (string, error) {
+	prompt, err := a.prompts.Render("pr-title", Prompt{Number: number, Diff: diff, Issue: issue, Summary: summary, Commits: commits, Language: a.language})
+	if err != nil {
+		return "", err
+	}
 	return a.send("You are a helpful assistant generating Git commit titles.", prompt, summary)
 }
 
-func (a *Anthropic) PrBody(diff string, issue string, summary string) (string, error) {
-	prompt := fmt.Sprintf(PrBody, diff, issue)
+func (a *Anthropic) PrBody(diff, issue, summary, commits, form string) (string, error) {
+	prompt, err := a.prompts.Render("pr-body", Prompt{Diff: diff, Issue: issue, Summary: summary, Commits: commits, Template: form, Language: a.language})
+	if err != nil {
+		return "", err
+	}
 	return a.send("You are a helpful assistant generating Git commit messages.", prompt, summary)
 }
 
 func (a *Anthropic) IssueTitle(userInput string, summary string) (string, error) {
-	prompt := fmt.Sprintf(IssueTitle, userInput)
+	prompt, err := a.prompts.Render("issue-title", Prompt{Input: userInput, Summary: summary, Language: a.language})
+	if err != nil {
+		return "", err
+	}
 	return a.send("You are a helpful assistant creating GitHub issue titles.", prompt, summary)
 }
 
 func (a *Anthropic) IssueBody(input string, summary string) (string, error) {
-	prompt := fmt.Sprintf(IssueBody, input)
+	prompt, err := a.prompts.Render("issue-body", Prompt{Input: input, Summary: summary, Language: a.language})
+	if err != nil {
+		return "", err
+	}
 	return a.send("You are a helpful assistant writing GitHub issue descriptions.", prompt, summary)
 }
 
 func (a *Anthropic) IssueLabels(issue string, available []string) ([]string, error) {
 	alllabels := strings.Join(available, ", ")
-	prompt := fmt.Sprintf(Labels, issue, alllabels)
+	prompt, err := a.prompts.Render("labels", Prompt{Issue: issue, Labels: alllabels, Language: a.language})
+	if err != nil {
+		return nil, err
+	}
 	resp, err := a.send("You are a helpful assistant assigning GitHub issue labels.", prompt, "")
 	if err != nil {
 		return nil, err
@@ -95,19 +142,48 @@ func (a *Anthropic) IssueLabels(issue string, available []string) ([]string, err
 	return res, nil
 }
 
-func (a *Anthropic) CommitMessage(number, diff, descr string) (string, error) {
-	prompt := appendIssue(fmt.Sprintf(CommitMsg, diff, number, number), descr)
+func (a *Anthropic) CommitMessage(number, diff, descr, hint string) (string, error) {
+	prompt, err := a.prompts.Render("commit", Prompt{Number: number, Diff: diff, Issue: descr, Hint: hint, Language: a.language})
+	if err != nil {
+		return "", err
+	}
+	prompt = appendIssue(prompt, descr)
 	a.log.Debug("anthropic prompt: %q", prompt)
 	return a.send("You are a helpful assistant writing commit messages.", prompt, "")
 }
 
+func (a *Anthropic) CommitPlan(number, changes, descr string) (string, error) {
+	prompt, err := a.prompts.Render("split", Prompt{Number: number, Diff: changes, Issue: descr, Language: a.language})
+	if err != nil {
+		return "", err
+	}
+	prompt = appendIssue(prompt, descr)
+	a.log.Debug("anthropic prompt: %q", prompt)
+	return a.send("You are a helpful assistant planning commits.", prompt, "")
+}
+
+func (a *Anthropic) DiffSummary(diff string) (string, error) {
+	prompt, err := a.prompts.Render("diff-summary", Prompt{Diff: diff, Language: a.language})
+	if err != nil {
+		return "", err
+	}
+	a.log.Debug("anthropic prompt: %q", prompt)
+	return a.send("You are a helpful assistant summarizing code changes.", prompt, "")
+}
+
 func (a *Anthropic) Summary(readme string) (string, error) {
-	prompt := fmt.Sprintf(Summary, readme)
+	prompt, err := a.prompts.Render("summary", Prompt{Readme: readme, Language: a.language})
+	if err != nil {
+		return "", err
+	}
 	return a.send("You are a helpful assistant writing project summaries.", prompt, "")
 }
 
 func (a *Anthropic) SuggestBranch(descr string) (string, error) {
-	prompt := fmt.Sprintf(BranchName, descr)
+	prompt, err := a.prompts.Render("branch", Prompt{Input: descr, Language: a.language})
+	if err != nil {
+		return "", err
+	}
 	return a.send("You are a helpful assistant suggesting branch names.", prompt, "")
 }
 
@@ -117,12 +193,13 @@ func (a *Anthropic) send(system, user, summary string) (string, error) {
 		content = appendSummary(content, summary)
 	}
 	content = appendLanguage(content, a.language)
-	content = trimPrompt(content)
+	content = a.limit.Fit(content)
 	body := anthropicRequest{
 		Model:     a.model,
 		MaxTokens: 1024,
 		System:    system,
 		Messages:  []chatMessage{{Role: "user", Content: content}},
+		Stream:    a.stream != nil,
 	}
 	data, err := json.Marshal(body)
 	if err != nil {
@@ -135,7 +212,7 @@ func (a *Anthropic) send(system, user, summary string) (string, error) {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("x-api-key", a.token)
 	req.Header.Set("anthropic-version", anthropicVersion)
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := a.client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("error making request to anthropic api: %w", err)
 	}
@@ -146,7 +223,10 @@ func (a *Anthropic) send(system, user, summary string) (string, error) {
 	}()
 	if resp.StatusCode != 200 {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API error: %s", body)
+		return "", &StatusError{Code: resp.StatusCode, Body: string(body)}
+	}
+	if a.stream != nil {
+		return a.receive(resp.Body)
 	}
 	var parsed anthropicResponse
 	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
@@ -157,3 +237,33 @@ func (a *Anthropic) send(system, user, summary string) (string, error) {
 	}
 	return strings.TrimSpace(parsed.Content[0].Text), nil
 }
+
+// receive collects the text deltas of an Anthropic messages stream,
+// rendering them as they arrive.
+func (a *Anthropic) receive(body io.Reader) (string, error) {
+	var full strings.Builder
+	err := events(body, func(data string) error {
+		var event anthropicEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return fmt.Errorf("error decoding stream event: %w", err)
+		}
+		switch event.Type {
+		case "content_block_delta":
+			return render(a.stream, &full, event.Delta.Text)
+		case "error":
+			return fmt.Errorf("API error: %s", event.Error.Message)
+		default:
+			return nil
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := finish(a.stream); err != nil {
+		return "", err
+	}
+	if full.Len() == 0 {
+		return "", errors.New("no content in response")
+	}
+	return strings.TrimSpace(full.String()), nil
+}
@@ -1,6 +1,7 @@
 package ai
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -15,7 +16,7 @@ import (
 func TestAnthropicAI_Summary(t *testing.T) {
 	server := anthropicEchoServer(t)
 	defer server.Close()
-	ai := NewAnthropic("test-token", "", true, "en").(*Anthropic)
+	ai := NewAnthropic("test-token", "", 0, true, "en", nil, nil, nil).(*Anthropic)
 	ai.url = server.URL
 	expected := "Test README content"
 
@@ -29,7 +30,7 @@ func TestAnthropicAI_Summary(t *testing.T) {
 func TestAnthropicAI_ReleaseNotes(t *testing.T) {
 	server := anthropicEchoServer(t)
 	defer server.Close()
-	ai := NewAnthropic("test-token", "", false, "en").(*Anthropic)
+	ai := NewAnthropic("test-token", "", 0, false, "en", nil, nil, nil).(*Anthropic)
 	ai.url = server.URL
 	expected := "Test changes"
 
@@ -43,13 +44,13 @@ func TestAnthropicAI_ReleaseNotes(t *testing.T) {
 func TestAnthropicAI_PrTitle(t *testing.T) {
 	server := anthropicEchoServer(t)
 	defer server.Close()
-	ai := NewAnthropic("test-token", "", true, "en").(*Anthropic)
+	ai := NewAnthropic("test-token", "", 0, true, "en", nil, nil, nil).(*Anthropic)
 	ai.url = server.URL
 	expectedDiff := "Test diff"
 	expectedIssue := "Test issue"
 	expectedNumber := "42"
 
-	result, err := ai.PrTitle(expectedNumber, expectedDiff, expectedIssue, "")
+	result, err := ai.PrTitle(expectedNumber, expectedDiff, expectedIssue, "", "")
 
 	require.NoError(t, err, "Expected no error when generating PR title")
 	assert.Contains(t, result, "generate a one-line PR title", "Echo server should return a command")
@@ -60,12 +61,12 @@ func TestAnthropicAI_PrTitle(t *testing.T) {
 func TestAnthropicAI_PrBody(t *testing.T) {
 	server := anthropicEchoServer(t)
 	defer server.Close()
-	ai := NewAnthropic("test-token", "", true, "en").(*Anthropic)
+	ai := NewAnthropic("test-token", "", 0, true, "en", nil, nil, nil).(*Anthropic)
 	ai.url = server.URL
 	expectedDiff := "Test diff"
 	expectedIssue := "Test issue"
 
-	result, err := ai.PrBody(expectedDiff, expectedIssue, "")
+	result, err := ai.PrBody(expectedDiff, expectedIssue, "", "", "")
 
 	require.NoError(t, err, "Expected no error when generating PR body")
 	assert.Contains(t, result, "generate a well-structured pull request body", "Echo server should return a command")
@@ -76,7 +77,7 @@ func TestAnthropicAI_PrBody(t *testing.T) {
 func TestAnthropicAI_IssueTitle(t *testing.T) {
 	server := anthropicEchoServer(t)
 	defer server.Close()
-	ai := NewAnthropic("test-token", "", true, "en").(*Anthropic)
+	ai := NewAnthropic("test-token", "", 0, true, "en", nil, nil, nil).(*Anthropic)
 	ai.url = server.URL
 	expectedInput := "Test input"
 
@@ -90,7 +91,7 @@ func TestAnthropicAI_IssueTitle(t *testing.T) {
 func TestAnthropicAI_IssueBody(t *testing.T) {
 	server := anthropicEchoServer(t)
 	defer server.Close()
-	ai := NewAnthropic("test-token", "", true, "en").(*Anthropic)
+	ai := NewAnthropic("test-token", "", 0, true, "en", nil, nil, nil).(*Anthropic)
 	ai.url = server.URL
 	expectedInput := "Test input"
 
@@ -104,7 +105,7 @@ func TestAnthropicAI_IssueBody(t *testing.T) {
 func TestAnthropicAI_IssueLabels(t *testing.T) {
 	server := anthropicEchoServer(t)
 	defer server.Close()
-	ai := NewAnthropic("test-token", "", true, "en").(*Anthropic)
+	ai := NewAnthropic("test-token", "", 0, true, "en", nil, nil, nil).(*Anthropic)
 	ai.url = server.URL
 	expectedIssue := "Test issue"
 	availableLabels := []string{"bug", "feature", "enhancement"}
@@ -120,12 +121,12 @@ func TestAnthropicAI_IssueLabels(t *testing.T) {
 func TestAnthropicAI_CommitMessage(t *testing.T) {
 	server := anthropicEchoServer(t)
 	defer server.Close()
-	ai := NewAnthropic("test-token", "", true, "en").(*Anthropic)
+	ai := NewAnthropic("test-token", "", 0, true, "en", nil, nil, nil).(*Anthropic)
 	ai.url = server.URL
 	expectedDiff := "Test diff"
 	expectedNumber := "42"
 
-	result, err := ai.CommitMessage(expectedNumber, expectedDiff, "")
+	result, err := ai.CommitMessage(expectedNumber, expectedDiff, "", "")
 
 	require.NoError(t, err, "Expected no error when generating commit message")
 	assert.Contains(t, result, "generate a single-line commit message", "Echo server should return a command")
@@ -135,7 +136,7 @@ func TestAnthropicAI_CommitMessage(t *testing.T) {
 func TestAnthropicAI_SuggestBranch(t *testing.T) {
 	server := anthropicEchoServer(t)
 	defer server.Close()
-	ai := NewAnthropic("test-token", "", true, "en").(*Anthropic)
+	ai := NewAnthropic("test-token", "", 0, true, "en", nil, nil, nil).(*Anthropic)
 	ai.url = server.URL
 	expectedDescr := "Test description"
 
@@ -151,7 +152,7 @@ func TestAnthropicAI_Handle404Response(t *testing.T) {
 		http.Error(w, "Not Found", http.StatusNotFound)
 	}))
 	defer server.Close()
-	ai := NewAnthropic("test-token", "", true, "en").(*Anthropic)
+	ai := NewAnthropic("test-token", "", 0, true, "en", nil, nil, nil).(*Anthropic)
 	ai.url = server.URL
 
 	_, err := ai.Summary("Test README content")
@@ -161,12 +162,12 @@ func TestAnthropicAI_Handle404Response(t *testing.T) {
 }
 
 func TestAnthropicAI_DefaultModel(t *testing.T) {
-	ai := NewAnthropic("test-token", "", false, "en").(*Anthropic)
+	ai := NewAnthropic("test-token", "", 0, false, "en", nil, nil, nil).(*Anthropic)
 	assert.Equal(t, anthropicDefaultModel, ai.model, "Expected default model to be set")
 }
 
 func TestAnthropicAI_CustomModel(t *testing.T) {
-	ai := NewAnthropic("test-token", "claude-opus-4-7", false, "en").(*Anthropic)
+	ai := NewAnthropic("test-token", "claude-opus-4-7", 0, false, "en", nil, nil, nil).(*Anthropic)
 	assert.Equal(t, "claude-opus-4-7", ai.model, "Expected custom model to be set")
 }
 
@@ -204,3 +205,45 @@ func replaceChars(s string) string {
 	}
 	return string(result)
 }
+
+func TestAnthropicAI_Streams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request anthropicRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request), "Failed to read request body")
+		assert.True(t, request.Stream, "Expected a streaming request")
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, err := fmt.Fprint(w, "event: message_start\ndata: {\"type\":\"message_start\"}\n\n")
+		require.NoError(t, err, "Failed to write response")
+		for _, chunk := range []string{"docs(#3): ", "describe ", "streaming"} {
+			_, err = fmt.Fprintf(w, "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"%s\"}}\n\n", chunk)
+			require.NoError(t, err, "Failed to write response")
+		}
+		_, err = fmt.Fprint(w, "event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n")
+		require.NoError(t, err, "Failed to write response")
+	}))
+	defer server.Close()
+	var out bytes.Buffer
+	ai := NewAnthropic("test-token", "", 0, false, "en", &out, nil, nil).(*Anthropic)
+	ai.url = server.URL
+
+	msg, err := ai.CommitMessage("#3", "diff", "", "")
+
+	require.NoError(t, err, "Expected no error when streaming a commit message")
+	assert.Equal(t, "docs(#3): describe streaming", msg)
+	assert.Equal(t, "docs(#3): describe streaming\n", out.String(), "Expected the answer to be rendered as it arrives")
+}
+
+func TestAnthropicAI_StreamsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprint(w, "event: error\ndata: {\"type\":\"error\",\"error\":{\"message\":\"Overloaded\"}}\n\n")
+		require.NoError(t, err, "Failed to write response")
+	}))
+	defer server.Close()
+	ai := NewAnthropic("test-token", "", 0, false, "en", &bytes.Buffer{}, nil, nil).(*Anthropic)
+	ai.url = server.URL
+
+	_, err := ai.CommitMessage("#3", "diff", "", "")
+
+	require.Error(t, err, "Expected an error event to fail the request")
+	assert.Contains(t, err.Error(), "Overloaded")
+}
@@ -0,0 +1,200 @@
+package ai
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/volodya-lombrozo/aidy/internal/cache"
+	"github.com/volodya-lombrozo/aidy/internal/convention"
+	"github.com/volodya-lombrozo/aidy/internal/log"
+)
+
+// Cached gives the answers a model gave before to the same prompts instead of asking it again.
+// Answers are kept in the store by the model, the language and the hash of the prompt,
+// and they expire after the TTL.
+// A fresh Cached always asks the model and only stores its answers.
+type Cached struct {
+	origin   AI
+	store    cache.Cache
+	prompts  *Prompts
+	model    string
+	language string
+	ttl      time.Duration
+	fresh    bool
+	now      func() time.Time
+	log      log.Logger
+}
+
+// answer is an entry of the store.
+type answer struct {
+	Text string    `json:"answer"`
+	At   time.Time `json:"at"`
+}
+
+// NewCached caches the answers of the model named like 'openai/gpt-4o'.
+// The prompts are the ones the model renders, so a changed template makes a new key.
+// If prompts is nil, the built-in templates are used.
+func NewCached(origin AI, store cache.Cache, prompts *Prompts, model, language string, ttl time.Duration, fresh bool) AI {
+	if prompts == nil {
+		prompts = NewPrompts()
+	}
+	return &Cached{origin: origin, store: store, prompts: prompts, model: model, language: language, ttl: ttl, fresh: fresh, now: time.Now, log: log.Default()}
+}
+
+// Fresh returns the model that asks again instead of giving the cached answers,
+// e.g. to regenerate an answer the user rejected. The new answers are still stored.
+// Models that aren't cached are returned as they are.
+func Fresh(brain AI) AI {
+	switch b := brain.(type) {
+	case *Cached:
+		fresh := *b
+		fresh.fresh = true
+		return &fresh
+	case *Chain:
+		links := make([]Link, len(b.links))
+		for i, link := range b.links {
+			links[i] = Link{Name: link.Name, AI: Fresh(link.AI)}
+		}
+		return &Chain{links: links, log: b.log}
+	default:
+		return brain
+	}
+}
+
+// Expired tells whether the entry of the store is older than the TTL.
+// Entries that can't be read are expired too.
+func Expired(entry string, ttl time.Duration, now time.Time) bool {
+	var ans answer
+	if err := json.Unmarshal([]byte(entry), &ans); err != nil {
+		return true
+	}
+	return now.Sub(ans.At) > ttl
+}
+
+func (c *Cached) PrTitle(number, diff, issue, summary, commits string) (string, error) {
+	return c.ask("pr-title", Prompt{Number: number, Diff: diff, Issue: issue, Summary: summary, Commits: commits}, func() (string, error) {
+		return c.origin.PrTitle(number, diff, issue, summary, commits)
+	})
+}
+
+func (c *Cached) PrBody(diff, issue, summary, commits, form string) (string, error) {
+	return c.ask("pr-body", Prompt{Diff: diff, Issue: issue, Summary: summary, Commits: commits, Template: form}, func() (string, error) {
+		return c.origin.PrBody(diff, issue, summary, commits, form)
+	})
+}
+
+func (c *Cached) IssueTitle(input, summary string) (string, error) {
+	return c.ask("issue-title", Prompt{Input: input, Summary: summary}, func() (string, error) {
+		return c.origin.IssueTitle(input, summary)
+	})
+}
+
+func (c *Cached) IssueBody(input, summary string) (string, error) {
+	return c.ask("issue-body", Prompt{Input: input, Summary: summary}, func() (string, error) {
+		return c.origin.IssueBody(input, summary)
+	})
+}
+
+func (c *Cached) IssueLabels(issue string, available []string) ([]string, error) {
+	labels, err := c.ask("labels", Prompt{Issue: issue, Labels: strings.Join(available, ", ")}, func() (string, error) {
+		labels, err := c.origin.IssueLabels(issue, available)
+		return strings.Join(labels, "\n"), err
+	})
+	if err != nil || labels == "" {
+		return nil, err
+	}
+	return strings.Split(labels, "\n"), nil
+}
+
+// CommitMessage doesn't store messages that break the convention,
+// they would be given again every time the user asks to regenerate them.
+func (c *Cached) CommitMessage(number, diff, descr, hint string) (string, error) {
+	rules := c.prompts.Convention()
+	valid := func(text string) bool {
+		msg := convention.Parse(rules.Heal(strings.TrimSpace(text), number), rules.Trailer).Wrap().String()
+		return len(rules.Lint(msg)) == 0
+	}
+	return c.check("commit", Prompt{Number: number, Diff: diff, Issue: descr, Hint: hint}, valid, func() (string, error) {
+		return c.origin.CommitMessage(number, diff, descr, hint)
+	})
+}
+
+func (c *Cached) CommitPlan(number, changes, descr string) (string, error) {
+	return c.ask("split", Prompt{Number: number, Diff: changes, Issue: descr}, func() (string, error) {
+		return c.origin.CommitPlan(number, changes, descr)
+	})
+}
+
+func (c *Cached) DiffSummary(diff string) (string, error) {
+	return c.ask("diff-summary", Prompt{Diff: diff}, func() (string, error) {
+		return c.origin.DiffSummary(diff)
+	})
+}
+
+func (c *Cached) Summary(readme string) (string, error) {
+	return c.ask("summary", Prompt{Readme: readme}, func() (string, error) {
+		return c.origin.Summary(readme)
+	})
+}
+
+func (c *Cached) SuggestBranch(descr string) (string, error) {
+	return c.ask("branch", Prompt{Input: descr}, func() (string, error) {
+		return c.origin.SuggestBranch(descr)
+	})
+}
+
+func (c *Cached) ReleaseNotes(changes string) (string, error) {
+	return c.ask("release-notes", Prompt{Commits: changes}, func() (string, error) {
+		return c.origin.ReleaseNotes(changes)
+	})
+}
+
+// ask returns the stored answer to the prompt or asks the model and stores its answer.
+func (c *Cached) ask(name string, data Prompt, question func() (string, error)) (string, error) {
+	return c.check(name, data, nil, question)
+}
+
+// check is ask that stores only the answers that are valid, all of them if valid is nil.
+func (c *Cached) check(name string, data Prompt, valid func(text string) bool, question func() (string, error)) (string, error) {
+	key := c.key(name, data)
+	if !c.fresh {
+		if entry, ok := c.store.Get(key); ok && !Expired(entry, c.ttl, c.now()) {
+			var ans answer
+			if err := json.Unmarshal([]byte(entry), &ans); err == nil {
+				c.log.Debug("answer to the '%s' prompt is taken from the cache", name)
+				return ans.Text, nil
+			}
+		}
+	}
+	text, err := question()
+	if err != nil || strings.TrimSpace(text) == "" {
+		return text, err
+	}
+	if valid != nil && !valid(text) {
+		c.log.Debug("answer to the '%s' prompt isn't cached, it's invalid", name)
+		return text, nil
+	}
+	entry, err := json.Marshal(answer{Text: text, At: c.now()})
+	if err == nil {
+		err = c.store.Set(key, string(entry))
+	}
+	if err != nil {
+		c.log.Warn("failed to cache the answer to the '%s' prompt: %v", name, err)
+	}
+	return text, nil
+}
+
+// key identifies the prompt, so a changed template, convention or argument makes a new key.
+func (c *Cached) key(name string, data Prompt) string {
+	data.Language = c.language
+	prompt, err := c.prompts.Render(name, data)
+	if err != nil {
+		prompt = err.Error()
+	}
+	hash := sha256.New()
+	_, _ = fmt.Fprintf(hash, "%s\x00%s\x00%#v", name, prompt, data)
+	return fmt.Sprintf("%s/%s/%x", c.model, c.language, hash.Sum(nil))
+}
@@ -0,0 +1,138 @@
+package ai
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volodya-lombrozo/aidy/internal/cache"
+)
+
+func TestCached_AnswersSamePromptFromStore(t *testing.T) {
+	model := &scripted{answer: "feat: cache answers"}
+	brain := NewCached(model, store(t), nil, "openai/gpt-4o", "en", time.Hour, false)
+
+	first, err := brain.CommitMessage("#8", "diff", "", "")
+	require.NoError(t, err, "Expected the model to answer")
+	second, err := brain.CommitMessage("#8", "diff", "", "")
+	require.NoError(t, err, "Expected the cache to answer")
+
+	assert.Equal(t, "feat: cache answers", first)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, model.calls, "Expected the model to be asked once")
+}
+
+func TestCached_AsksAgainForAnotherPrompt(t *testing.T) {
+	model := &scripted{answer: "feat: cache answers"}
+	answers := store(t)
+	brain := NewCached(model, answers, nil, "openai/gpt-4o", "en", time.Hour, false)
+	other := NewCached(model, answers, nil, "openai/gpt-4o", "fr", time.Hour, false)
+
+	_, _ = brain.CommitMessage("#8", "diff", "", "")
+	_, _ = brain.CommitMessage("#8", "another diff", "", "")
+	_, _ = brain.PrTitle("#8", "diff", "", "", "")
+	_, _ = other.CommitMessage("#8", "diff", "", "")
+
+	assert.Equal(t, 4, model.calls, "Expected each prompt, prompt kind and language to be asked")
+	assert.Len(t, answers.Keys(), 4)
+}
+
+func TestCached_AsksAgainWhenExpired(t *testing.T) {
+	model := &scripted{answer: "notes"}
+	brain := NewCached(model, store(t), nil, "deepseek", "en", time.Hour, false).(*Cached)
+	now := time.Now()
+	brain.now = func() time.Time { return now }
+
+	_, _ = brain.ReleaseNotes("changes")
+	brain.now = func() time.Time { return now.Add(2 * time.Hour) }
+	_, _ = brain.ReleaseNotes("changes")
+
+	assert.Equal(t, 2, model.calls, "Expected an expired answer not to be used")
+}
+
+func TestCached_FreshAsksModelAndStoresAnswer(t *testing.T) {
+	model := &scripted{answer: "fix/branch"}
+	answers := store(t)
+	fresh := NewCached(model, answers, nil, "deepseek", "en", time.Hour, true)
+	cached := NewCached(model, answers, nil, "deepseek", "en", time.Hour, false)
+
+	_, _ = fresh.SuggestBranch("task")
+	_, _ = fresh.SuggestBranch("task")
+	_, _ = cached.SuggestBranch("task")
+
+	assert.Equal(t, 2, model.calls, "Expected fresh answers to be stored for later runs")
+}
+
+func TestCached_DoesNotStoreFailures(t *testing.T) {
+	model := &scripted{err: errors.New("boom")}
+	answers := store(t)
+	brain := NewCached(model, answers, nil, "deepseek", "en", time.Hour, false)
+
+	_, err := brain.PrBody("diff", "", "", "", "")
+
+	assert.EqualError(t, err, "boom")
+	assert.Empty(t, answers.Keys(), "Expected no answer to be stored")
+}
+
+func TestCached_DoesNotStoreMessagesBreakingConvention(t *testing.T) {
+	model := &scripted{answer: "feat: cached"}
+	answers := store(t)
+	brain := NewCached(model, answers, nil, "deepseek", "en", time.Hour, false)
+
+	_, _ = brain.CommitMessage("#8", "diff", "", "")
+	_, _ = brain.CommitMessage("#8", "diff", "", "")
+
+	assert.Equal(t, 2, model.calls, "Expected the model to be asked again")
+	assert.Empty(t, answers.Keys(), "Expected no answer to be stored")
+}
+
+func TestFresh_AsksAgainThroughChain(t *testing.T) {
+	model := &scripted{answer: "fix/branch"}
+	answers := store(t)
+	chain := NewChain(Link{Name: "deepseek", AI: NewCached(model, answers, nil, "deepseek", "en", time.Hour, false)})
+
+	_, _ = chain.SuggestBranch("task")
+	_, _ = Fresh(chain).SuggestBranch("task")
+	_, _ = chain.SuggestBranch("task")
+
+	assert.Equal(t, 2, model.calls, "Expected only the fresh chain to ask the model again")
+}
+
+func TestCached_StoresLabels(t *testing.T) {
+	model := &scripted{labels: []string{"bug", "docs"}}
+	brain := NewCached(model, store(t), nil, "deepseek", "en", time.Hour, false)
+
+	_, _ = brain.IssueLabels("issue", []string{"bug", "docs", "feature"})
+	labels, err := brain.IssueLabels("issue", []string{"bug", "docs", "feature"})
+
+	require.NoError(t, err, "Expected labels to be cached")
+	assert.Equal(t, []string{"bug", "docs"}, labels)
+	assert.Equal(t, 1, model.calls)
+}
+
+func TestCached_KeysAnswersBySha256OfPrompt(t *testing.T) {
+	answers := store(t)
+	brain := NewCached(&scripted{answer: "fix/branch"}, answers, nil, "deepseek", "en", time.Hour, false)
+
+	_, _ = brain.SuggestBranch("task")
+
+	require.Len(t, answers.Keys(), 1)
+	assert.Regexp(t, `^deepseek/en/[0-9a-f]{64}$`, answers.Keys()[0])
+}
+
+func TestExpired(t *testing.T) {
+	now := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	assert.False(t, Expired(`{"answer":"a","at":"2025-01-01T12:00:00Z"}`, 24*time.Hour, now))
+	assert.True(t, Expired(`{"answer":"a","at":"2024-12-31T12:00:00Z"}`, 24*time.Hour, now))
+	assert.True(t, Expired("not json", 24*time.Hour, now), "Expected unreadable entries to be expired")
+}
+
+func store(t *testing.T) cache.Cache {
+	answers, err := cache.NewFileCache(filepath.Join(t.TempDir(), "responses.js"))
+	require.NoError(t, err, "Failed to create cache")
+	return answers
+}
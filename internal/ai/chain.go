@@ -0,0 +1,143 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/volodya-lombrozo/aidy/internal/log"
+)
+
+// Link is a named model in a Chain.
+type Link struct {
+	Name string
+	AI   AI
+}
+
+// Chain asks its models one by one until one of them answers.
+// The next model is tried when the previous one can't be reached,
+// is rate limited, fails on the server side or returns nothing.
+type Chain struct {
+	links []Link
+	log   log.Logger
+}
+
+// NewChain creates a chain of models tried in the given order.
+func NewChain(links ...Link) AI {
+	return &Chain{links: links, log: log.Default()}
+}
+
+func (c *Chain) PrTitle(number, diff, issue, summary, commits string) (string, error) {
+	return ask(c, func(brain AI) (string, error) { return brain.PrTitle(number, diff, issue, summary, commits) })
+}
+
+func (c *Chain) PrBody(diff, issue, summary, commits, form string) (string, error) {
+	return ask(c, func(brain AI) (string, error) { return brain.PrBody(diff, issue, summary, commits, form) })
+}
+
+func (c *Chain) IssueTitle(input, summary string) (string, error) {
+	return ask(c, func(brain AI) (string, error) { return brain.IssueTitle(input, summary) })
+}
+
+func (c *Chain) IssueBody(input, summary string) (string, error) {
+	return ask(c, func(brain AI) (string, error) { return brain.IssueBody(input, summary) })
+}
+
+func (c *Chain) IssueLabels(issue string, available []string) ([]string, error) {
+	return first(c, func(brain AI) ([]string, error) {
+		return brain.IssueLabels(issue, available)
+	}, func([]string) bool {
+		return false
+	})
+}
+
+func (c *Chain) CommitMessage(number, diff, descr, hint string) (string, error) {
+	return ask(c, func(brain AI) (string, error) { return brain.CommitMessage(number, diff, descr, hint) })
+}
+
+func (c *Chain) CommitPlan(number, changes, descr string) (string, error) {
+	return ask(c, func(brain AI) (string, error) { return brain.CommitPlan(number, changes, descr) })
+}
+
+func (c *Chain) DiffSummary(diff string) (string, error) {
+	return ask(c, func(brain AI) (string, error) { return brain.DiffSummary(diff) })
+}
+
+func (c *Chain) Summary(readme string) (string, error) {
+	return ask(c, func(brain AI) (string, error) { return brain.Summary(readme) })
+}
+
+func (c *Chain) SuggestBranch(descr string) (string, error) {
+	return ask(c, func(brain AI) (string, error) { return brain.SuggestBranch(descr) })
+}
+
+func (c *Chain) ReleaseNotes(changes string) (string, error) {
+	return ask(c, func(brain AI) (string, error) { return brain.ReleaseNotes(changes) })
+}
+
+// ask passes the question along the chain and returns the first non-blank answer.
+func ask(c *Chain, question func(brain AI) (string, error)) (string, error) {
+	return first(c, question, func(answer string) bool {
+		return strings.TrimSpace(answer) == ""
+	})
+}
+
+// first passes the question along the chain and returns the first answer that isn't empty.
+// An error that another model won't fix, like a rejected API key, stops the chain.
+func first[T any](c *Chain, question func(brain AI) (T, error), empty func(T) bool) (T, error) {
+	var none T
+	var failures []error
+	for i, link := range c.links {
+		answer, err := question(link.AI)
+		if err == nil && empty(answer) {
+			err = errors.New("empty response")
+		}
+		if err == nil {
+			if i == 0 {
+				c.log.Debug("answer generated by %s", link.Name)
+			} else {
+				c.log.Info("answer generated by fallback model %s", link.Name)
+			}
+			return answer, nil
+		}
+		failures = append(failures, fmt.Errorf("%s: %w", link.Name, err))
+		if !recoverable(err) {
+			break
+		}
+		if i < len(c.links)-1 {
+			c.log.Warn("model %s failed: %v, trying %s", link.Name, err, c.links[i+1].Name)
+		}
+	}
+	if len(failures) == 1 {
+		return none, errors.Unwrap(failures[0])
+	}
+	return none, fmt.Errorf("all models failed: %w", errors.Join(failures...))
+}
+
+// recoverable tells whether another model might succeed after the error.
+func recoverable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var status *StatusError
+	if errors.As(err, &status) {
+		return unavailable(status.Code)
+	}
+	var api *openai.APIError
+	if errors.As(err, &api) {
+		return unavailable(api.HTTPStatusCode)
+	}
+	var request *openai.RequestError
+	if errors.As(err, &request) {
+		return unavailable(request.HTTPStatusCode)
+	}
+	return true
+}
+
+// unavailable tells whether the status means the model can't answer right now.
+func unavailable(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
@@ -0,0 +1,174 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain_UsesFirstModel(t *testing.T) {
+	primary := &scripted{answer: "feat: primary"}
+	backup := &scripted{answer: "feat: backup"}
+	chain := NewChain(Link{Name: "deepseek", AI: primary}, Link{Name: "4o", AI: backup})
+
+	msg, err := chain.CommitMessage("#8", "diff", "", "")
+
+	require.NoError(t, err, "Expected the first model to answer")
+	assert.Equal(t, "feat: primary", msg)
+	assert.Equal(t, 0, backup.calls, "Expected the backup model not to be asked")
+}
+
+func TestChain_FallsBackOnRateLimit(t *testing.T) {
+	primary := &scripted{err: &StatusError{Code: http.StatusTooManyRequests, Body: "slow down"}}
+	backup := &scripted{answer: "feat: backup"}
+	chain := NewChain(Link{Name: "deepseek", AI: primary}, Link{Name: "4o", AI: backup})
+
+	title, err := chain.PrTitle("8", "diff", "issue", "", "")
+
+	require.NoError(t, err, "Expected the backup model to answer")
+	assert.Equal(t, "feat: backup", title)
+}
+
+func TestChain_FallsBackOnTransportError(t *testing.T) {
+	primary := &scripted{err: errors.New("error making request to deepseek api: connection refused")}
+	backup := &scripted{answer: "notes"}
+	chain := NewChain(Link{Name: "deepseek", AI: primary}, Link{Name: "4o", AI: backup})
+
+	notes, err := chain.ReleaseNotes("changes")
+
+	require.NoError(t, err, "Expected the backup model to answer")
+	assert.Equal(t, "notes", notes)
+}
+
+func TestChain_FallsBackOnEmptyAnswer(t *testing.T) {
+	primary := &scripted{answer: "  \n"}
+	backup := &scripted{answer: "fix-build"}
+	chain := NewChain(Link{Name: "local", AI: primary}, Link{Name: "4o", AI: backup})
+
+	branch, err := chain.SuggestBranch("fix the build")
+
+	require.NoError(t, err, "Expected the backup model to answer")
+	assert.Equal(t, "fix-build", branch)
+}
+
+func TestChain_FallsBackOnOpenAiServerError(t *testing.T) {
+	primary := &scripted{err: &openai.APIError{HTTPStatusCode: http.StatusBadGateway, Message: "bad gateway"}}
+	backup := &scripted{answer: "summary"}
+	chain := NewChain(Link{Name: "4o", AI: primary}, Link{Name: "claude", AI: backup})
+
+	summary, err := chain.Summary("readme")
+
+	require.NoError(t, err, "Expected the backup model to answer")
+	assert.Equal(t, "summary", summary)
+}
+
+func TestChain_StopsOnRejectedRequest(t *testing.T) {
+	primary := &scripted{err: &StatusError{Code: http.StatusUnauthorized, Body: "invalid api key"}}
+	backup := &scripted{answer: "title"}
+	chain := NewChain(Link{Name: "deepseek", AI: primary}, Link{Name: "4o", AI: backup})
+
+	_, err := chain.IssueTitle("input", "")
+
+	require.Error(t, err, "Expected a rejected request to stop the chain")
+	assert.Equal(t, "API error: invalid api key", err.Error(), "Expected the original error")
+	assert.Equal(t, 0, backup.calls, "Expected the backup model not to be asked")
+}
+
+func TestChain_StopsOnCancel(t *testing.T) {
+	primary := &scripted{err: context.Canceled}
+	backup := &scripted{answer: "body"}
+	chain := NewChain(Link{Name: "deepseek", AI: primary}, Link{Name: "4o", AI: backup})
+
+	_, err := chain.IssueBody("input", "")
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, backup.calls, "Expected the backup model not to be asked")
+}
+
+func TestChain_ReportsAllFailures(t *testing.T) {
+	primary := &scripted{err: &StatusError{Code: http.StatusServiceUnavailable, Body: "down"}}
+	backup := &scripted{err: errors.New("timeout")}
+	chain := NewChain(Link{Name: "deepseek", AI: primary}, Link{Name: "4o", AI: backup})
+
+	_, err := chain.PrBody("diff", "issue", "", "", "")
+
+	require.Error(t, err, "Expected an error when all models fail")
+	assert.Contains(t, err.Error(), "all models failed")
+	assert.Contains(t, err.Error(), "deepseek: API error: down")
+	assert.Contains(t, err.Error(), "4o: timeout")
+}
+
+func TestChain_AcceptsNoLabels(t *testing.T) {
+	primary := &scripted{}
+	backup := &scripted{labels: []string{"bug"}}
+	chain := NewChain(Link{Name: "deepseek", AI: primary}, Link{Name: "4o", AI: backup})
+
+	labels, err := chain.IssueLabels("issue", []string{"bug"})
+
+	require.NoError(t, err, "Expected no error")
+	assert.Empty(t, labels, "Expected no labels to be a valid answer")
+	assert.Equal(t, 0, backup.calls, "Expected the backup model not to be asked")
+}
+
+// scripted is a model that gives the same answer to any question.
+type scripted struct {
+	answer string
+	labels []string
+	err    error
+	calls  int
+}
+
+func (s *scripted) reply() (string, error) {
+	s.calls++
+	return s.answer, s.err
+}
+
+func (s *scripted) PrTitle(number, diff, issue, summary, commits string) (string, error) {
+	return s.reply()
+}
+
+func (s *scripted) PrBody(diff, issue, summary, commits, form string) (string, error) {
+	return s.reply()
+}
+
+func (s *scripted) IssueTitle(input, summary string) (string, error) {
+	return s.reply()
+}
+
+func (s *scripted) IssueBody(input, summary string) (string, error) {
+	return s.reply()
+}
+
+func (s *scripted) IssueLabels(issue string, available []string) ([]string, error) {
+	s.calls++
+	return s.labels, s.err
+}
+
+func (s *scripted) CommitMessage(number, diff, descr, hint string) (string, error) {
+	return s.reply()
+}
+
+func (s *scripted) CommitPlan(number, changes, descr string) (string, error) {
+	return s.reply()
+}
+
+func (s *scripted) DiffSummary(diff string) (string, error) {
+	return s.reply()
+}
+
+func (s *scripted) Summary(readme string) (string, error) {
+	return s.reply()
+}
+
+func (s *scripted) SuggestBranch(descr string) (string, error) {
+	return s.reply()
+}
+
+func (s *scripted) ReleaseNotes(changes string) (string, error) {
+	return s.reply()
+}
@@ -7,17 +7,25 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/volodya-lombrozo/aidy/internal/log"
+	"github.com/volodya-lombrozo/aidy/internal/tokens"
 )
 
+const deepseekDefaultModel = "deepseek-chat"
+
 type DeepSeek struct {
 	token    string
 	url      string
 	model    string
 	summary  bool
 	language string
+	stream   io.Writer
+	client   *Client
+	limit    tokens.Limit
+	prompts  *Prompts
 	log      log.Logger
 }
 
@@ -40,45 +48,92 @@ type chatResponse struct {
 	Choices []chatChoice `json:"choices"`
 }
 
-func NewDeepSeek(apiKey string, summary bool, language string) AI {
+type chatDelta struct {
+	Delta chatMessage `json:"delta"`
+}
+
+type chatChunk struct {
+	Choices []chatDelta `json:"choices"`
+}
+
+// NewDeepSeek creates a DeepSeek client.
+// If stream is not nil, answers are requested as a stream and rendered to it as they arrive.
+// A positive window overrides the known context window of the model.
+// If client is nil, every request is sent once without a timeout.
+// A non-empty endpoint sends requests to another OpenAI-compatible API instead of DeepSeek,
+// and then model names the model it serves.
+// Empty model falls back to 'deepseek-chat'.
+// If prompts is nil, the built-in templates are used.
+func NewDeepSeek(apiKey, model string, window int, summary bool, language string, stream io.Writer, client *Client, endpoint Endpoint, prompts *Prompts) AI {
+	if model == "" {
+		model = deepseekDefaultModel
+	}
+	if client == nil {
+		client = once()
+	}
+	if prompts == nil {
+		prompts = NewPrompts()
+	}
 	return &DeepSeek{
 		token:    apiKey,
-		url:      "https://api.deepseek.com/chat/completions",
-		model:    "deepseek-chat",
+		url:      completions("https://api.deepseek.com", endpoint),
+		model:    model,
 		summary:  summary,
 		language: language,
+		stream:   stream,
+		client:   client,
+		limit:    tokens.For("deepseek", model, window),
+		prompts:  prompts,
 		log:      log.Default(),
 	}
 }
 
 func (d *DeepSeek) ReleaseNotes(changes string) (string, error) {
-	prompt := fmt.Sprintf(ReleaseNotes, changes)
+	prompt, err := d.prompts.Render("release-notes", Prompt{Commits: changes, Language: d.language})
+	if err != nil {
+		return "", err
+	}
 	return d.send("You are a helpful assistant generating GitHub release notes.", prompt, "")
 }
 
-func (d *DeepSeek) PrTitle(number, diff, issue, summary string) (string, error) {
-	prompt := fmt.Sprintf(PrTitle, diff, issue, number, number)
+func (d *DeepSeek) PrTitle(number, diff, issue, summary, commits string) (string, error) {
+	prompt, err := d.prompts.Render("pr-title", Prompt{Number: number, Diff: diff, Issue: issue, Summary: summary, Commits: commits, Language: d.language})
+	if err != nil {
+		return "", err
+	}
 	return d.send("You are a helpful assistant generating Git commit titles.", prompt, summary)
 }
 
-func (d *DeepSeek) PrBody(diff string, issue string, summary string) (string, error) {
-	prompt := fmt.Sprintf(PrBody, diff, issue)
+func (d *DeepSeek) PrBody(diff, issue, summary, commits, form string) (string, error) {
+	prompt, err := d.prompts.Render("pr-body", Prompt{Diff: diff, Issue: issue, Summary: summary, Commits: commits, Template: form, Language: d.language})
+	if err != nil {
+		return "", err
+	}
 	return d.send("You are a helpful assistant generating Git commit messages.", prompt, summary)
 }
 
 func (d *DeepSeek) IssueTitle(userInput string, summary string) (string, error) {
-	prompt := fmt.Sprintf(IssueTitle, userInput)
+	prompt, err := d.prompts.Render("issue-title", Prompt{Input: userInput, Summary: summary, Language: d.language})
+	if err != nil {
+		return "", err
+	}
 	return d.send("You are a helpful assistant creating GitHub issue titles.", prompt, summary)
 }
 
 func (d *DeepSeek) IssueBody(input string, summary string) (string, error) {
-	prompt := fmt.Sprintf(IssueBody, input)
+	prompt, err := d.prompts.Render("issue-body", Prompt{Input: input, Summary: summary, Language: d.language})
+	if err != nil {
+		return "", err
+	}
 	return d.send("You are a helpful assistant writing GitHub issue descriptions.", prompt, summary)
 }
 
 func (d *DeepSeek) IssueLabels(issue string, available []string) ([]string, error) {
 	alllabels := strings.Join(available, ", ")
-	prompt := fmt.Sprintf(Labels, issue, alllabels)
+	prompt, err := d.prompts.Render("labels", Prompt{Issue: issue, Labels: alllabels, Language: d.language})
+	if err != nil {
+		return nil, err
+	}
 	resp, err := d.send("You are a helpful assistant assigning GitHub issue labels.", prompt, "")
 	if err != nil {
 		return nil, err
@@ -92,19 +147,48 @@ func (d *DeepSeek) IssueLabels(issue string, available []string) ([]string, erro
 	return res, nil
 }
 
-func (d *DeepSeek) CommitMessage(number, diff, descr string) (string, error) {
-	prompt := appendIssue(fmt.Sprintf(CommitMsg, diff, number, number), descr)
+func (d *DeepSeek) CommitMessage(number, diff, descr, hint string) (string, error) {
+	prompt, err := d.prompts.Render("commit", Prompt{Number: number, Diff: diff, Issue: descr, Hint: hint, Language: d.language})
+	if err != nil {
+		return "", err
+	}
+	prompt = appendIssue(prompt, descr)
 	d.log.Debug("deepseek prompt: %q", prompt)
 	return d.send("You are a helpful assistant writing commit messages.", prompt, "")
 }
 
+func (d *DeepSeek) CommitPlan(number, changes, descr string) (string, error) {
+	prompt, err := d.prompts.Render("split", Prompt{Number: number, Diff: changes, Issue: descr, Language: d.language})
+	if err != nil {
+		return "", err
+	}
+	prompt = appendIssue(prompt, descr)
+	d.log.Debug("deepseek prompt: %q", prompt)
+	return d.send("You are a helpful assistant planning commits.", prompt, "")
+}
+
+func (d *DeepSeek) DiffSummary(diff string) (string, error) {
+	prompt, err := d.prompts.Render("diff-summary", Prompt{Diff: diff, Language: d.language})
+	if err != nil {
+		return "", err
+	}
+	d.log.Debug("deepseek prompt: %q", prompt)
+	return d.send("You are a helpful assistant summarizing code changes.", prompt, "")
+}
+
 func (d *DeepSeek) Summary(readme string) (string, error) {
-	prompt := fmt.Sprintf(Summary, readme)
+	prompt, err := d.prompts.Render("summary", Prompt{Readme: readme, Language: d.language})
+	if err != nil {
+		return "", err
+	}
 	return d.send("You are a helpful assistant writing project summaries.", prompt, "")
 }
 
 func (d *DeepSeek) SuggestBranch(descr string) (string, error) {
-	prompt := fmt.Sprintf(BranchName, descr)
+	prompt, err := d.prompts.Render("branch", Prompt{Input: descr, Language: d.language})
+	if err != nil {
+		return "", err
+	}
 	return d.send("You are a helpful assistant suggesting branch names.", prompt, "")
 }
 
@@ -114,14 +198,14 @@ func (d *DeepSeek) send(system string, user string, summary string) (string, err
 		content = appendSummary(content, summary)
 	}
 	content = appendLanguage(content, d.language)
-	content = trimPrompt(content)
+	content = d.limit.Fit(content)
 	body := chatRequest{
 		Model: d.model,
 		Messages: []chatMessage{
 			{Role: "system", Content: system},
 			{Role: "user", Content: content},
 		},
-		Stream: false,
+		Stream: d.stream != nil,
 	}
 	data, err := json.Marshal(body)
 	if err != nil {
@@ -133,7 +217,7 @@ func (d *DeepSeek) send(system string, user string, summary string) (string, err
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", d.token))
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := d.client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("error making request to deepseek api: %w", err)
 	}
@@ -144,7 +228,10 @@ func (d *DeepSeek) send(system string, user string, summary string) (string, err
 	}()
 	if resp.StatusCode != 200 {
 		content, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API error: %s", content)
+		return "", &StatusError{Code: resp.StatusCode, Body: string(content)}
+	}
+	if d.stream != nil {
+		return chatStream(resp.Body, d.stream)
 	}
 	var parsed chatResponse
 	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
@@ -157,3 +244,41 @@ func (d *DeepSeek) send(system string, user string, summary string) (string, err
 
 	return strings.TrimSpace(parsed.Choices[0].Message.Content), nil
 }
+
+// chatStream collects an OpenAI-compatible chat completion stream,
+// rendering every received piece of content to out.
+func chatStream(body io.Reader, out io.Writer) (string, error) {
+	var full strings.Builder
+	err := events(body, func(data string) error {
+		var chunk chatChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("error decoding stream chunk: %w", err)
+		}
+		if len(chunk.Choices) == 0 {
+			return nil
+		}
+		return render(out, &full, chunk.Choices[0].Delta.Content)
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := finish(out); err != nil {
+		return "", err
+	}
+	if full.Len() == 0 {
+		return "", errors.New("no content in stream")
+	}
+	return strings.TrimSpace(full.String()), nil
+}
+
+// completions builds the URL of the chat completions API, honouring a custom endpoint.
+func completions(base string, endpoint Endpoint) string {
+	if endpoint.Url != "" {
+		base = strings.TrimRight(endpoint.Url, "/")
+	}
+	res := base + "/chat/completions"
+	if endpoint.Version != "" {
+		res += "?api-version=" + url.QueryEscape(endpoint.Version)
+	}
+	return res
+}
@@ -1,11 +1,14 @@
 package ai
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -16,7 +19,7 @@ import (
 func TestDeepSeekAI_Summary(t *testing.T) {
 	server := echoServer(t)
 	defer server.Close()
-	ai := NewDeepSeek("test-token", true, "en").(*DeepSeek)
+	ai := NewDeepSeek("test-token", "", 0, true, "en", nil, nil, Endpoint{}, nil).(*DeepSeek)
 	ai.url = server.URL
 	expected := "Test README content"
 
@@ -30,7 +33,7 @@ func TestDeepSeekAI_Summary(t *testing.T) {
 func TestDeepSeekAI_ReleaseNotes(t *testing.T) {
 	server := echoServer(t)
 	defer server.Close()
-	ai := NewDeepSeek("test-token", false, "en").(*DeepSeek)
+	ai := NewDeepSeek("test-token", "", 0, false, "en", nil, nil, Endpoint{}, nil).(*DeepSeek)
 	ai.url = server.URL
 	expected := "Test changes"
 
@@ -44,13 +47,13 @@ func TestDeepSeekAI_ReleaseNotes(t *testing.T) {
 func TestDeepSeekAI_PrTitle(t *testing.T) {
 	server := echoServer(t)
 	defer server.Close()
-	ai := NewDeepSeek("test-token", true, "en").(*DeepSeek)
+	ai := NewDeepSeek("test-token", "", 0, true, "en", nil, nil, Endpoint{}, nil).(*DeepSeek)
 	ai.url = server.URL
 	expectedDiff := "Test diff"
 	expectedIssue := "Test issue"
 	expectedNumber := "42"
 
-	result, err := ai.PrTitle(expectedNumber, expectedDiff, expectedIssue, "")
+	result, err := ai.PrTitle(expectedNumber, expectedDiff, expectedIssue, "", "")
 
 	require.NoError(t, err, "Expected no error when generating PR title")
 	assert.Contains(t, result, "generate a one-line PR title", "Echo server should return a command")
@@ -61,12 +64,12 @@ func TestDeepSeekAI_PrTitle(t *testing.T) {
 func TestDeepSeekAI_PrBody(t *testing.T) {
 	server := echoServer(t)
 	defer server.Close()
-	ai := NewDeepSeek("test-token", true, "en").(*DeepSeek)
+	ai := NewDeepSeek("test-token", "", 0, true, "en", nil, nil, Endpoint{}, nil).(*DeepSeek)
 	ai.url = server.URL
 	expectedDiff := "Test diff"
 	expectedIssue := "Test issue"
 
-	result, err := ai.PrBody(expectedDiff, expectedIssue, "")
+	result, err := ai.PrBody(expectedDiff, expectedIssue, "", "", "")
 
 	require.NoError(t, err, "Expected no error when generating PR body")
 	assert.Contains(t, result, "generate a well-structured pull request body", "Echo server should return a command")
@@ -77,7 +80,7 @@ func TestDeepSeekAI_PrBody(t *testing.T) {
 func TestDeepSeekAI_IssueTitle(t *testing.T) {
 	server := echoServer(t)
 	defer server.Close()
-	ai := NewDeepSeek("test-token", true, "en").(*DeepSeek)
+	ai := NewDeepSeek("test-token", "", 0, true, "en", nil, nil, Endpoint{}, nil).(*DeepSeek)
 	ai.url = server.URL
 	expectedInput := "Test input"
 
@@ -91,7 +94,7 @@ func TestDeepSeekAI_IssueTitle(t *testing.T) {
 func TestDeepSeekAI_IssueBody(t *testing.T) {
 	server := echoServer(t)
 	defer server.Close()
-	ai := NewDeepSeek("test-token", true, "en").(*DeepSeek)
+	ai := NewDeepSeek("test-token", "", 0, true, "en", nil, nil, Endpoint{}, nil).(*DeepSeek)
 	ai.url = server.URL
 	expectedInput := "Test input"
 
@@ -105,7 +108,7 @@ func TestDeepSeekAI_IssueBody(t *testing.T) {
 func TestDeepSeekAI_IssueLabels(t *testing.T) {
 	server := echoServer(t)
 	defer server.Close()
-	ai := NewDeepSeek("test-token", true, "en").(*DeepSeek)
+	ai := NewDeepSeek("test-token", "", 0, true, "en", nil, nil, Endpoint{}, nil).(*DeepSeek)
 	ai.url = server.URL
 	expectedIssue := "Test issue"
 	availableLabels := []string{"bug", "feature", "enhancement"}
@@ -121,12 +124,12 @@ func TestDeepSeekAI_IssueLabels(t *testing.T) {
 func TestDeepSeekAI_CommitMessage(t *testing.T) {
 	server := echoServer(t)
 	defer server.Close()
-	ai := NewDeepSeek("test-token", true, "en").(*DeepSeek)
+	ai := NewDeepSeek("test-token", "", 0, true, "en", nil, nil, Endpoint{}, nil).(*DeepSeek)
 	ai.url = server.URL
 	expectedDiff := "Test diff"
 	expectedNumber := "42"
 
-	result, err := ai.CommitMessage(expectedNumber, expectedDiff, "")
+	result, err := ai.CommitMessage(expectedNumber, expectedDiff, "", "")
 
 	require.NoError(t, err, "Expected no error when generating commit message")
 	assert.Contains(t, result, "generate a single-line commit message", "Echo server should return a command")
@@ -136,7 +139,7 @@ func TestDeepSeekAI_CommitMessage(t *testing.T) {
 func TestDeepSeekAI_SuggestBranch(t *testing.T) {
 	server := echoServer(t)
 	defer server.Close()
-	ai := NewDeepSeek("test-token", true, "en").(*DeepSeek)
+	ai := NewDeepSeek("test-token", "", 0, true, "en", nil, nil, Endpoint{}, nil).(*DeepSeek)
 	ai.url = server.URL
 	expectedDescr := "Test description"
 
@@ -152,7 +155,7 @@ func TestDeepSeekAI_Handle404Response(t *testing.T) {
 		http.Error(w, "Not Found", http.StatusNotFound)
 	}))
 	defer server.Close()
-	ai := NewDeepSeek("test-token", true, "en").(*DeepSeek)
+	ai := NewDeepSeek("test-token", "", 0, true, "en", nil, nil, Endpoint{}, nil).(*DeepSeek)
 	ai.url = server.URL
 
 	_, err := ai.Summary("Test README content")
@@ -161,6 +164,32 @@ func TestDeepSeekAI_Handle404Response(t *testing.T) {
 	assert.Contains(t, err.Error(), "API error: Not Found", "Expected error message to contain 'API error: Not Found'")
 }
 
+func TestDeepSeekAI_CommitMessageWithHint(t *testing.T) {
+	server := echoServer(t)
+	defer server.Close()
+	ai := NewDeepSeek("test-token", "", 0, false, "en", nil, nil, Endpoint{}, nil).(*DeepSeek)
+	ai.url = server.URL
+
+	result, err := ai.CommitMessage("#1", "diff", "", "the message is too long")
+
+	require.NoError(t, err, "Expected no error")
+	assert.Contains(t, result, "<remarks>\nthe message is too long\n</remarks>", "Expected the hint to be sent to AI")
+}
+
+func TestDeepSeekAI_CustomTemplateGetsHintOnce(t *testing.T) {
+	server := echoServer(t)
+	defer server.Close()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "commit.tmpl"), []byte("commit {{.Diff}}, remarks: {{.Hint}}"), 0644))
+	ai := NewDeepSeek("test-token", "", 0, false, "en", nil, nil, Endpoint{}, NewPrompts(dir)).(*DeepSeek)
+	ai.url = server.URL
+
+	result, err := ai.CommitMessage("#1", "diff", "", "the message is too long")
+
+	require.NoError(t, err, "Expected no error")
+	assert.Equal(t, "commit diff, remarks: the message is too long", result, "Expected the hint to be sent once")
+}
+
 func echoServer(t *testing.T) *httptest.Server {
 	t.Helper()
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -178,3 +207,103 @@ func echoServer(t *testing.T) *httptest.Server {
 		require.NoError(t, err, "Failed to write response")
 	}))
 }
+
+func TestDeepSeekAI_Streams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request chatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request), "Failed to read request body")
+		assert.True(t, request.Stream, "Expected a streaming request")
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range []string{"fix(#2): ", "handle ", "streams"} {
+			_, err := fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":\"%s\"}}]}\n\n", chunk)
+			require.NoError(t, err, "Failed to write response")
+		}
+		_, err := fmt.Fprint(w, ": keep-alive\n\ndata: [DONE]\n\n")
+		require.NoError(t, err, "Failed to write response")
+	}))
+	defer server.Close()
+	var out bytes.Buffer
+	ai := NewDeepSeek("test-token", "", 0, false, "en", &out, nil, Endpoint{}, nil).(*DeepSeek)
+	ai.url = server.URL
+
+	msg, err := ai.CommitMessage("#2", "diff", "", "")
+
+	require.NoError(t, err, "Expected no error when streaming a commit message")
+	assert.Equal(t, "fix(#2): handle streams", msg)
+	assert.Equal(t, "fix(#2): handle streams\n", out.String(), "Expected the answer to be rendered as it arrives")
+}
+
+func TestDeepSeekAI_StreamsBrokenChunk(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprint(w, "data: {not-json\n\n")
+		require.NoError(t, err, "Failed to write response")
+	}))
+	defer server.Close()
+	ai := NewDeepSeek("test-token", "", 0, false, "en", &bytes.Buffer{}, nil, Endpoint{}, nil).(*DeepSeek)
+	ai.url = server.URL
+
+	_, err := ai.CommitMessage("#2", "diff", "", "")
+
+	require.Error(t, err, "Expected an error for a broken chunk")
+	assert.Contains(t, err.Error(), "error decoding stream chunk")
+}
+
+func TestDeepSeekAI_StreamsNothing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprint(w, "data: [DONE]\n\n")
+		require.NoError(t, err, "Failed to write response")
+	}))
+	defer server.Close()
+	ai := NewDeepSeek("test-token", "", 0, false, "en", &bytes.Buffer{}, nil, Endpoint{}, nil).(*DeepSeek)
+	ai.url = server.URL
+
+	_, err := ai.CommitMessage("#2", "diff", "", "")
+
+	require.Error(t, err, "Expected an error for an empty stream")
+	assert.Contains(t, err.Error(), "no content in stream")
+}
+
+func TestDeepSeekAI_CompatibleEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path, "Expected the base URL to be honoured")
+		assert.Equal(t, "2024-06-01", r.URL.Query().Get("api-version"), "Expected the API version")
+		_, err := fmt.Fprint(w, `{"choices":[{"message":{"content":"gateway"}}]}`)
+		require.NoError(t, err, "Failed to write response")
+	}))
+	defer server.Close()
+	ai := NewDeepSeek("test-token", "", 0, false, "en", nil, nil, Endpoint{Url: server.URL + "/v1/", Version: "2024-06-01"}, nil)
+
+	msg, err := ai.Summary("readme")
+
+	require.NoError(t, err, "Expected no error from a compatible endpoint")
+	assert.Equal(t, "gateway", msg)
+}
+
+func TestDeepSeekAI_ConfiguredModel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request chatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request), "Failed to read request body")
+		assert.Equal(t, "qwen2.5-coder", request.Model, "Expected the configured model to be requested")
+		_, err := fmt.Fprint(w, `{"choices":[{"message":{"content":"served"}}]}`)
+		require.NoError(t, err, "Failed to write response")
+	}))
+	defer server.Close()
+	ai := NewDeepSeek("test-token", "qwen2.5-coder", 0, false, "en", nil, nil, Endpoint{Url: server.URL}, nil)
+
+	msg, err := ai.Summary("readme")
+
+	require.NoError(t, err, "Expected no error from a compatible endpoint")
+	assert.Equal(t, "served", msg)
+}
+
+func TestDeepSeekAI_DefaultModel(t *testing.T) {
+	ai := NewDeepSeek("test-token", "", 0, false, "en", nil, nil, Endpoint{}, nil).(*DeepSeek)
+
+	assert.Equal(t, "deepseek-chat", ai.model)
+}
+
+func TestDeepSeekAI_DefaultEndpoint(t *testing.T) {
+	ai := NewDeepSeek("test-token", "", 0, false, "en", nil, nil, Endpoint{}, nil).(*DeepSeek)
+
+	assert.Equal(t, "https://api.deepseek.com/chat/completions", ai.url)
+}
@@ -24,12 +24,19 @@ func (m *MockAI) ReleaseNotes(changes string) (string, error) {
 	return fmt.Sprintf("Mock Release Notes\n\n%s", changes), nil
 }
 
-func (m *MockAI) PrTitle(branchName string, diff string, issue string, summary string) (string, error) {
+func (m *MockAI) PrTitle(branchName string, diff string, issue string, summary string, commits string) (string, error) {
 	return fmt.Sprintf("mock title for '%s' with issue #%s and summary: %s", branchName, issue, summary), nil
 }
 
-func (m *MockAI) PrBody(diff string, issue string, summary string) (string, error) {
-	return fmt.Sprintf("mock body for issue #%s and summary: %s\n\ndiff:\n%s", issue, summary, diff), nil
+func (m *MockAI) PrBody(diff string, issue string, summary string, commits string, form string) (string, error) {
+	body := fmt.Sprintf("mock body for issue #%s and summary: %s\n\ndiff:\n%s", issue, summary, diff)
+	if commits != "" {
+		body += fmt.Sprintf("\n\ncommits:\n%s", commits)
+	}
+	if form != "" {
+		body += fmt.Sprintf("\n\ntemplate:\n%s", form)
+	}
+	return body, nil
 }
 
 func (m *MockAI) IssueTitle(input string, summary string) (string, error) {
@@ -40,10 +47,30 @@ func (m *MockAI) IssueBody(input string, summary string) (string, error) {
 	return fmt.Sprintf("mock issue body for '%s' with summary: %s", input, summary), nil
 }
 
-func (m *MockAI) CommitMessage(issue, diff, descr string) (string, error) {
+func (m *MockAI) CommitMessage(issue, diff, descr, hint string) (string, error) {
 	return fmt.Sprintf("feat(%s): %s", issue, summary(diff)), nil
 }
 
+// CommitPlan puts all files listed as 'File: <name> (<status>)' into a single commit.
+func (m *MockAI) CommitPlan(issue, changes, descr string) (string, error) {
+	var files []string
+	for _, line := range strings.Split(changes, "\n") {
+		if name, ok := strings.CutPrefix(line, "File: "); ok {
+			name, _, _ = strings.Cut(name, " (")
+			files = append(files, name)
+		}
+	}
+	return fmt.Sprintf("feat(%s): update %d files\n  %s", issue, len(files), strings.Join(files, "\n  ")), nil
+}
+
+// DiffSummary lists the files of the diff.
+func (m *MockAI) DiffSummary(diff string) (string, error) {
+	if m.fail {
+		return "", fmt.Errorf("failed to summarize diff")
+	}
+	return fmt.Sprintf("- %s", summary(diff)), nil
+}
+
 func (m *MockAI) IssueLabels(issue string, available []string) ([]string, error) {
 	return available, nil
 }
@@ -26,7 +26,7 @@ func TestMockGenerateCommitMessage(t *testing.T) {
 	issue := "#100"
 	expected := fmt.Sprintf("feat(%s): %s", issue, "changed files: ai/mockai.go")
 
-	msg, err := mockAI.CommitMessage(issue, diff, "")
+	msg, err := mockAI.CommitMessage(issue, diff, "", "")
 
 	require.NoError(t, err, "Expected no error")
 	assert.Equal(t, expected, msg, "Expected commit message to match")
@@ -47,7 +47,7 @@ func TestMock_GeneratePrTitle(t *testing.T) {
 	branchName := "feature-branch"
 	expected := fmt.Sprintf("mock title for '%s' with issue #issue and summary: summary", branchName)
 
-	title, err := mockAI.PrTitle(branchName, "diff", "issue", "summary")
+	title, err := mockAI.PrTitle(branchName, "diff", "issue", "summary", "")
 
 	require.NoError(t, err, "Expected no error")
 	assert.Equal(t, expected, title, "Expected title to match")
@@ -78,7 +78,7 @@ func TestMock_GenerateIssueBody(t *testing.T) {
 func TestMock_GenerateBody(t *testing.T) {
 	ai := NewMockAI()
 	expected := "mock body for issue #issue and summary: summary\n\ndiff:\ndiff"
-	body, err := ai.PrBody("diff", "issue", "summary")
+	body, err := ai.PrBody("diff", "issue", "summary", "", "")
 
 	require.NoError(t, err, "Expected no error")
 	assert.Contains(t, body, expected, "Expected body to match")
@@ -0,0 +1,283 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/volodya-lombrozo/aidy/internal/log"
+	"github.com/volodya-lombrozo/aidy/internal/tokens"
+)
+
+const ollamaDefaultUrl = "http://localhost:11434"
+
+const ollamaDefaultModel = "llama3.2"
+
+// Ollama talks to a local model through the '/api/chat' endpoint of Ollama
+// or of any server that mimics it, so it works without internet access.
+type Ollama struct {
+	url      string
+	model    string
+	window   int
+	summary  bool
+	language string
+	stream   io.Writer
+	client   *Client
+	limit    tokens.Limit
+	prompts  *Prompts
+	log      log.Logger
+}
+
+type ollamaRequest struct {
+	Model    string         `json:"model"`
+	Messages []chatMessage  `json:"messages"`
+	Stream   bool           `json:"stream"`
+	Options  *ollamaOptions `json:"options,omitempty"`
+}
+
+// ollamaOptions asks Ollama for a context window larger than the default of the server,
+// which otherwise silently drops the beginning of long prompts.
+type ollamaOptions struct {
+	Context int `json:"num_ctx"`
+}
+
+type ollamaResponse struct {
+	Message chatMessage `json:"message"`
+	Done    bool        `json:"done"`
+	Error   string      `json:"error"`
+}
+
+// NewOllama creates a client of the Ollama server running at url.
+// Empty url and model fall back to 'http://localhost:11434' and 'llama3.2'.
+// If stream is not nil, answers are requested as a stream and rendered to it as they arrive.
+// A positive window overrides the known context window of the model and is asked of the server.
+// If client is nil, every request is sent once without a timeout.
+// If prompts is nil, the built-in templates are used.
+func NewOllama(url, model string, window int, summary bool, language string, stream io.Writer, client *Client, prompts *Prompts) AI {
+	if url == "" {
+		url = ollamaDefaultUrl
+	}
+	if model == "" {
+		model = ollamaDefaultModel
+	}
+	if client == nil {
+		client = once()
+	}
+	if prompts == nil {
+		prompts = NewPrompts()
+	}
+	return &Ollama{
+		url:      strings.TrimRight(url, "/") + "/api/chat",
+		model:    model,
+		window:   window,
+		summary:  summary,
+		language: language,
+		stream:   stream,
+		client:   client,
+		limit:    tokens.For("ollama", model, window),
+		prompts:  prompts,
+		log:      log.Default(),
+	}
+}
+
+func (o *Ollama) ReleaseNotes(changes string) (string, error) {
+	prompt, err := o.prompts.Render("release-notes", Prompt{Commits: changes, Language: o.language})
+	if err != nil {
+		return "", err
+	}
+	return o.send("You are a helpful assistant generating GitHub release notes.", prompt, "")
+}
+
+func (o *Ollama) PrTitle(number, diff, issue, summary, commits string) (string, error) {
+	prompt, err := o.prompts.Render("pr-title", Prompt{Number: number, Diff: diff, Issue: issue, Summary: summary, Commits: commits, Language: o.language})
+	if err != nil {
+		return "", err
+	}
+	return o.send("You are a helpful assistant generating Git commit titles.", prompt, summary)
+}
+
+func (o *Ollama) PrBody(diff, issue, summary, commits, form string) (string, error) {
+	prompt, err := o.prompts.Render("pr-body", Prompt{Diff: diff, Issue: issue, Summary: summary, Commits: commits, Template: form, Language: o.language})
+	if err != nil {
+		return "", err
+	}
+	return o.send("You are a helpful assistant generating Git commit messages.", prompt, summary)
+}
+
+func (o *Ollama) IssueTitle(userInput string, summary string) (string, error) {
+	prompt, err := o.prompts.Render("issue-title", Prompt{Input: userInput, Summary: summary, Language: o.language})
+	if err != nil {
+		return "", err
+	}
+	return o.send("You are a helpful assistant creating GitHub issue titles.", prompt, summary)
+}
+
+func (o *Ollama) IssueBody(input string, summary string) (string, error) {
+	prompt, err := o.prompts.Render("issue-body", Prompt{Input: input, Summary: summary, Language: o.language})
+	if err != nil {
+		return "", err
+	}
+	return o.send("You are a helpful assistant writing GitHub issue descriptions.", prompt, summary)
+}
+
+func (o *Ollama) IssueLabels(issue string, available []string) ([]string, error) {
+	alllabels := strings.Join(available, ", ")
+	prompt, err := o.prompts.Render("labels", Prompt{Issue: issue, Labels: alllabels, Language: o.language})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.send("You are a helpful assistant assigning GitHub issue labels.", prompt, "")
+	if err != nil {
+		return nil, err
+	}
+	var res []string
+	for _, label := range available {
+		if strings.Contains(resp, label) {
+			res = append(res, label)
+		}
+	}
+	return res, nil
+}
+
+func (o *Ollama) CommitMessage(number, diff, descr, hint string) (string, error) {
+	prompt, err := o.prompts.Render("commit", Prompt{Number: number, Diff: diff, Issue: descr, Hint: hint, Language: o.language})
+	if err != nil {
+		return "", err
+	}
+	prompt = appendIssue(prompt, descr)
+	o.log.Debug("ollama prompt: %q", prompt)
+	return o.send("You are a helpful assistant writing commit messages.", prompt, "")
+}
+
+func (o *Ollama) CommitPlan(number, changes, descr string) (string, error) {
+	prompt, err := o.prompts.Render("split", Prompt{Number: number, Diff: changes, Issue: descr, Language: o.language})
+	if err != nil {
+		return "", err
+	}
+	prompt = appendIssue(prompt, descr)
+	o.log.Debug("ollama prompt: %q", prompt)
+	return o.send("You are a helpful assistant planning commits.", prompt, "")
+}
+
+func (o *Ollama) DiffSummary(diff string) (string, error) {
+	prompt, err := o.prompts.Render("diff-summary", Prompt{Diff: diff, Language: o.language})
+	if err != nil {
+		return "", err
+	}
+	o.log.Debug("ollama prompt: %q", prompt)
+	return o.send("You are a helpful assistant summarizing code changes.", prompt, "")
+}
+
+func (o *Ollama) Summary(readme string) (string, error) {
+	prompt, err := o.prompts.Render("summary", Prompt{Readme: readme, Language: o.language})
+	if err != nil {
+		return "", err
+	}
+	return o.send("You are a helpful assistant writing project summaries.", prompt, "")
+}
+
+func (o *Ollama) SuggestBranch(descr string) (string, error) {
+	prompt, err := o.prompts.Render("branch", Prompt{Input: descr, Language: o.language})
+	if err != nil {
+		return "", err
+	}
+	return o.send("You are a helpful assistant suggesting branch names.", prompt, "")
+}
+
+func (o *Ollama) send(system, user, summary string) (string, error) {
+	content := user
+	if o.summary {
+		content = appendSummary(content, summary)
+	}
+	content = appendLanguage(content, o.language)
+	content = o.limit.Fit(content)
+	body := ollamaRequest{
+		Model: o.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: content},
+		},
+		Stream: o.stream != nil,
+	}
+	if o.window > 0 {
+		body.Options = &ollamaOptions{Context: o.window}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest("POST", o.url, bytes.NewBuffer(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error making request to ollama at %s: %w", o.url, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			o.log.Error("error closing response body: %v", err)
+		}
+	}()
+	if resp.StatusCode != 200 {
+		content, _ := io.ReadAll(resp.Body)
+		return "", &StatusError{Code: resp.StatusCode, Body: string(content)}
+	}
+	if o.stream != nil {
+		return o.receive(resp.Body)
+	}
+	var parsed ollamaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", fmt.Errorf("error decoding response: %w", err)
+	}
+	if parsed.Error != "" {
+		return "", fmt.Errorf("API error: %s", parsed.Error)
+	}
+	if parsed.Message.Content == "" {
+		return "", errors.New("no content in response")
+	}
+	return strings.TrimSpace(parsed.Message.Content), nil
+}
+
+// receive collects a stream of newline-delimited JSON messages,
+// rendering every piece of content as it arrives.
+func (o *Ollama) receive(body io.Reader) (string, error) {
+	var full strings.Builder
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var chunk ollamaResponse
+		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+			return "", fmt.Errorf("error decoding stream chunk: %w", err)
+		}
+		if chunk.Error != "" {
+			return "", fmt.Errorf("API error: %s", chunk.Error)
+		}
+		if err := render(o.stream, &full, chunk.Message.Content); err != nil {
+			return "", err
+		}
+		if chunk.Done {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("error reading stream: %w", err)
+	}
+	if err := finish(o.stream); err != nil {
+		return "", err
+	}
+	if full.Len() == 0 {
+		return "", errors.New("no content in stream")
+	}
+	return strings.TrimSpace(full.String()), nil
+}
@@ -0,0 +1,215 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOllama_Summary(t *testing.T) {
+	server := ollamaServer(t)
+	defer server.Close()
+	ai := NewOllama(server.URL, "", 0, true, "en", nil, nil, nil)
+	expected := "Test README content"
+
+	result, err := ai.Summary(expected)
+
+	require.NoError(t, err, "Expected no error when generating summary")
+	assert.Contains(t, result, "generate a short, single-paragraph summary", "Echo server should return a command")
+	assert.Contains(t, result, expected, "Expected summary to contain README content")
+}
+
+func TestOllama_ReleaseNotes(t *testing.T) {
+	server := ollamaServer(t)
+	defer server.Close()
+	ai := NewOllama(server.URL, "", 0, false, "en", nil, nil, nil)
+
+	result, err := ai.ReleaseNotes("Test changes")
+
+	require.NoError(t, err, "Expected no error when generating release notes")
+	assert.Contains(t, result, "Generate clear, concise release notes", "Echo server should return a command")
+	assert.Contains(t, result, "Test changes", "Expected release notes to contain changes")
+}
+
+func TestOllama_PrTitleAndBody(t *testing.T) {
+	server := ollamaServer(t)
+	defer server.Close()
+	ai := NewOllama(server.URL, "", 0, true, "en", nil, nil, nil)
+
+	title, err := ai.PrTitle("42", "Test diff", "Test issue", "Project summary", "")
+	require.NoError(t, err, "Expected no error when generating PR title")
+	body, err := ai.PrBody("Test diff", "Test issue", "Project summary", "", "")
+	require.NoError(t, err, "Expected no error when generating PR body")
+
+	assert.Contains(t, title, "generate a one-line PR title", "Echo server should return a command")
+	assert.Contains(t, title, "Project summary", "Expected summary to be appended")
+	assert.Contains(t, body, "Test diff", "Expected PR body to contain diff")
+}
+
+func TestOllama_Issue(t *testing.T) {
+	server := ollamaServer(t)
+	defer server.Close()
+	ai := NewOllama(server.URL, "", 0, false, "en", nil, nil, nil)
+
+	title, err := ai.IssueTitle("fix the build", "")
+	require.NoError(t, err, "Expected no error when generating issue title")
+	body, err := ai.IssueBody("fix the build", "")
+	require.NoError(t, err, "Expected no error when generating issue body")
+	labels, err := ai.IssueLabels("fix the build", []string{"bug", "unknown-label"})
+	require.NoError(t, err, "Expected no error when generating issue labels")
+
+	assert.Contains(t, title, "fix the build")
+	assert.Contains(t, body, "fix the build")
+	assert.Equal(t, []string{"bug"}, labels, "Expected only labels mentioned in the answer")
+}
+
+func TestOllama_CommitMessageAndBranch(t *testing.T) {
+	server := ollamaServer(t)
+	defer server.Close()
+	ai := NewOllama(server.URL, "", 0, false, "en", nil, nil, nil)
+
+	msg, err := ai.CommitMessage("#7", "Test diff", "Issue description", "")
+	require.NoError(t, err, "Expected no error when generating commit message")
+	branch, err := ai.SuggestBranch("Issue description")
+	require.NoError(t, err, "Expected no error when suggesting branch")
+
+	assert.Contains(t, msg, "Test diff")
+	assert.Contains(t, msg, "Issue description")
+	assert.Contains(t, branch, "Issue description")
+}
+
+func TestOllama_SendsModel(t *testing.T) {
+	var model string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/chat", r.URL.Path, "Expected the chat endpoint to be called")
+		var request ollamaRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request), "Failed to read request body")
+		model = request.Model
+		_, err := w.Write([]byte(`{"message":{"role":"assistant","content":"ok"},"done":true}`))
+		require.NoError(t, err, "Failed to write response")
+	}))
+	defer server.Close()
+	ai := NewOllama(server.URL+"/", "qwen2.5-coder", 0, false, "en", nil, nil, nil)
+
+	_, err := ai.Summary("readme")
+
+	require.NoError(t, err, "Expected no error")
+	assert.Equal(t, "qwen2.5-coder", model, "Expected the configured model to be requested")
+}
+
+func TestOllama_Defaults(t *testing.T) {
+	ai := NewOllama("", "", 0, false, "en", nil, nil, nil).(*Ollama)
+
+	assert.Equal(t, "http://localhost:11434/api/chat", ai.url)
+	assert.Equal(t, "llama3.2", ai.model)
+}
+
+func TestOllama_ReportsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error":"model 'missing' not found"}`))
+		require.NoError(t, err, "Failed to write response")
+	}))
+	defer server.Close()
+	ai := NewOllama(server.URL, "missing", 0, false, "en", nil, nil, nil)
+
+	_, err := ai.Summary("readme")
+
+	require.Error(t, err, "Expected an error when the model is missing")
+	assert.Contains(t, err.Error(), "model 'missing' not found")
+}
+
+func TestOllama_Streams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request ollamaRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request), "Failed to read request body")
+		assert.True(t, request.Stream, "Expected a streaming request")
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for _, chunk := range []string{"feat(#6): ", "run ", "locally"} {
+			_, err := fmt.Fprintf(w, "{\"message\":{\"role\":\"assistant\",\"content\":\"%s\"},\"done\":false}\n", chunk)
+			require.NoError(t, err, "Failed to write response")
+		}
+		_, err := fmt.Fprint(w, "{\"message\":{\"role\":\"assistant\",\"content\":\"\"},\"done\":true}\n")
+		require.NoError(t, err, "Failed to write response")
+	}))
+	defer server.Close()
+	var out bytes.Buffer
+	ai := NewOllama(server.URL, "", 0, false, "en", &out, nil, nil)
+
+	msg, err := ai.CommitMessage("#6", "diff", "", "")
+
+	require.NoError(t, err, "Expected no error when streaming a commit message")
+	assert.Equal(t, "feat(#6): run locally", msg)
+	assert.Equal(t, "feat(#6): run locally\n", out.String(), "Expected the answer to be rendered as it arrives")
+}
+
+func TestOllama_StreamsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprint(w, "{\"error\":\"out of memory\"}\n")
+		require.NoError(t, err, "Failed to write response")
+	}))
+	defer server.Close()
+	ai := NewOllama(server.URL, "", 0, false, "en", &bytes.Buffer{}, nil, nil)
+
+	_, err := ai.CommitMessage("#6", "diff", "", "")
+
+	require.Error(t, err, "Expected an error in the stream to fail the request")
+	assert.Contains(t, err.Error(), "out of memory")
+}
+
+// ollamaServer answers every chat request with the user message it received.
+func ollamaServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request ollamaRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request), "Failed to read request body")
+		require.Len(t, request.Messages, 2, "Expected system and user messages")
+		answer := request.Messages[1].Content
+		if request.Messages[0].Content == "You are a helpful assistant assigning GitHub issue labels." {
+			answer = "bug"
+		}
+		resp := ollamaResponse{Message: chatMessage{Role: "assistant", Content: answer}, Done: true}
+		require.NoError(t, json.NewEncoder(w).Encode(resp), "Failed to write response")
+	}))
+}
+
+func TestOllama_FitsPromptIntoWindow(t *testing.T) {
+	var request ollamaRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request), "Failed to read request body")
+		_, err := w.Write([]byte(`{"message":{"role":"assistant","content":"ok"},"done":true}`))
+		require.NoError(t, err, "Failed to write response")
+	}))
+	defer server.Close()
+	ai := NewOllama(server.URL, "", 1000, false, "en", nil, nil, nil)
+
+	_, err := ai.Summary(strings.Repeat("a", 10000))
+
+	require.NoError(t, err, "Expected no error")
+	assert.Len(t, []rune(request.Messages[1].Content), 3000, "Expected the prompt to leave a quarter of the window for the answer")
+	require.NotNil(t, request.Options, "Expected the window to be requested")
+	assert.Equal(t, 1000, request.Options.Context)
+}
+
+func TestOllama_KeepsServerWindowByDefault(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body), "Failed to read request body")
+		_, err := w.Write([]byte(`{"message":{"role":"assistant","content":"ok"},"done":true}`))
+		require.NoError(t, err, "Failed to write response")
+	}))
+	defer server.Close()
+	ai := NewOllama(server.URL, "", 0, false, "en", nil, nil, nil)
+
+	_, err := ai.Summary("readme")
+
+	require.NoError(t, err, "Expected no error")
+	assert.NotContains(t, body, "options", "Expected no options when the window isn't configured")
+}
@@ -2,14 +2,18 @@ package ai
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 
 	openai "github.com/sashabaranov/go-openai"
+	"github.com/volodya-lombrozo/aidy/internal/tokens"
 )
 
 type openClient interface {
 	CreateChatCompletion(context.Context, openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error)
+	CreateChatCompletionStream(context.Context, openai.ChatCompletionRequest) (*openai.ChatCompletionStream, error)
 }
 
 type OpenAI struct {
@@ -18,55 +22,126 @@ type OpenAI struct {
 	temperature float32
 	summary     bool
 	language    string
+	stream      io.Writer
+	ctx         context.Context
+	limit       tokens.Limit
+	prompts     *Prompts
 }
 
-func NewOpenAI(token, model string, temperature float32, summary bool, language string) *OpenAI {
-	return NewOpenAIWithClient(openai.NewClient(token), model, temperature, summary, language)
+// NewOpenAI creates an OpenAI client.
+// If stream is not nil, answers are requested as a stream and rendered to it as they arrive.
+// A positive window overrides the known context window of the model.
+// If client is nil, every request is sent once without a timeout.
+// A non-empty endpoint sends requests to another OpenAI-compatible API;
+// with a version set, it's treated as Azure OpenAI.
+// If prompts is nil, the built-in templates are used.
+func NewOpenAI(token, model string, window int, temperature float32, summary bool, language string, stream io.Writer, client *Client, endpoint Endpoint, prompts *Prompts) *OpenAI {
+	if client == nil {
+		client = once()
+	}
+	conf := openai.DefaultConfig(token)
+	if endpoint.Version != "" {
+		conf = openai.DefaultAzureConfig(token, endpoint.Url)
+		conf.APIVersion = endpoint.Version
+	} else if endpoint.Url != "" {
+		conf.BaseURL = strings.TrimRight(endpoint.Url, "/")
+	}
+	conf.HTTPClient = client.http
+	res := NewOpenAIWithClient(openai.NewClientWithConfig(conf), model, temperature, summary, language, stream)
+	res.ctx = client.ctx
+	res.limit = tokens.For("openai", model, window)
+	if prompts != nil {
+		res.prompts = prompts
+	}
+	return res
 }
 
-func NewOpenAIWithClient(client openClient, model string, temperature float32, summary bool, language string) *OpenAI {
+func NewOpenAIWithClient(client openClient, model string, temperature float32, summary bool, language string, stream io.Writer) *OpenAI {
 	return &OpenAI{
 		client:      client,
 		model:       model,
 		temperature: temperature,
 		summary:     summary,
 		language:    language,
+		stream:      stream,
+		ctx:         context.Background(),
+		limit:       tokens.For("openai", model, 0),
+		prompts:     NewPrompts(),
 	}
 }
 
 func (o *OpenAI) ReleaseNotes(changes string) (string, error) {
-	prompt := fmt.Sprintf(ReleaseNotes, changes)
+	prompt, err := o.prompts.Render("release-notes", Prompt{Commits: changes, Language: o.language})
+	if err != nil {
+		return "", err
+	}
 	return o.send(prompt, "")
 }
 
-func (o *OpenAI) PrTitle(number, diff, issue, summary string) (string, error) {
-	prompt := fmt.Sprintf(PrTitle, diff, issue, number, number)
+func (o *OpenAI) PrTitle(number, diff, issue, summary, commits string) (string, error) {
+	prompt, err := o.prompts.Render("pr-title", Prompt{Number: number, Diff: diff, Issue: issue, Summary: summary, Commits: commits, Language: o.language})
+	if err != nil {
+		return "", err
+	}
 	return o.send(prompt, summary)
 }
 
-func (o *OpenAI) PrBody(diff, issue, summary string) (string, error) {
-	prompt := fmt.Sprintf(PrBody, diff, issue)
+func (o *OpenAI) PrBody(diff, issue, summary, commits, form string) (string, error) {
+	prompt, err := o.prompts.Render("pr-body", Prompt{Diff: diff, Issue: issue, Summary: summary, Commits: commits, Template: form, Language: o.language})
+	if err != nil {
+		return "", err
+	}
 	return o.send(prompt, summary)
 }
 
 func (o *OpenAI) IssueTitle(input, summary string) (string, error) {
-	prompt := fmt.Sprintf(IssueTitle, input)
+	prompt, err := o.prompts.Render("issue-title", Prompt{Input: input, Summary: summary, Language: o.language})
+	if err != nil {
+		return "", err
+	}
 	return o.send(prompt, summary)
 }
 
 func (o *OpenAI) IssueBody(input string, summary string) (string, error) {
-	prompt := fmt.Sprintf(IssueBody, input)
+	prompt, err := o.prompts.Render("issue-body", Prompt{Input: input, Summary: summary, Language: o.language})
+	if err != nil {
+		return "", err
+	}
 	return o.send(prompt, summary)
 }
 
-func (o *OpenAI) CommitMessage(number, diff, descr string) (string, error) {
-	prompt := appendIssue(fmt.Sprintf(CommitMsg, diff, number, number), descr)
+func (o *OpenAI) CommitMessage(number, diff, descr, hint string) (string, error) {
+	prompt, err := o.prompts.Render("commit", Prompt{Number: number, Diff: diff, Issue: descr, Hint: hint, Language: o.language})
+	if err != nil {
+		return "", err
+	}
+	prompt = appendIssue(prompt, descr)
+	return o.send(prompt, "")
+}
+
+func (o *OpenAI) CommitPlan(number, changes, descr string) (string, error) {
+	prompt, err := o.prompts.Render("split", Prompt{Number: number, Diff: changes, Issue: descr, Language: o.language})
+	if err != nil {
+		return "", err
+	}
+	prompt = appendIssue(prompt, descr)
+	return o.send(prompt, "")
+}
+
+func (o *OpenAI) DiffSummary(diff string) (string, error) {
+	prompt, err := o.prompts.Render("diff-summary", Prompt{Diff: diff, Language: o.language})
+	if err != nil {
+		return "", err
+	}
 	return o.send(prompt, "")
 }
 
 func (o *OpenAI) IssueLabels(issue string, available []string) ([]string, error) {
 	alllabels := strings.Join(available, ", ")
-	prompt := fmt.Sprintf(Labels, issue, alllabels)
+	prompt, err := o.prompts.Render("labels", Prompt{Issue: issue, Labels: alllabels, Language: o.language})
+	if err != nil {
+		return nil, err
+	}
 	out, err := o.send(prompt, "")
 	if err != nil {
 		return nil, err
@@ -81,12 +156,18 @@ func (o *OpenAI) IssueLabels(issue string, available []string) ([]string, error)
 }
 
 func (o *OpenAI) Summary(readme string) (string, error) {
-	prompt := fmt.Sprintf(Summary, readme)
+	prompt, err := o.prompts.Render("summary", Prompt{Readme: readme, Language: o.language})
+	if err != nil {
+		return "", err
+	}
 	return o.send(prompt, "")
 }
 
 func (o *OpenAI) SuggestBranch(descr string) (string, error) {
-	prompt := fmt.Sprintf(BranchName, descr)
+	prompt, err := o.prompts.Render("branch", Prompt{Input: descr, Language: o.language})
+	if err != nil {
+		return "", err
+	}
 	return o.send(prompt, "")
 }
 
@@ -100,7 +181,7 @@ func (o *OpenAI) send(prompt, summary string) (string, error) {
 		content = appendSummary(content, summary)
 	}
 	content = appendLanguage(content, o.language)
-	content = trimPrompt(content)
+	content = o.limit.Fit(content)
 	req := openai.ChatCompletionRequest{
 		Model: o.model,
 		Messages: []openai.ChatCompletionMessage{
@@ -111,7 +192,10 @@ func (o *OpenAI) send(prompt, summary string) (string, error) {
 		},
 		Temperature: o.temperature,
 	}
-	resp, err := o.client.CreateChatCompletion(context.Background(), req)
+	if o.stream != nil {
+		return o.receive(req)
+	}
+	resp, err := o.client.CreateChatCompletion(o.ctx, req)
 
 	if err != nil {
 		return "", err
@@ -121,3 +205,38 @@ func (o *OpenAI) send(prompt, summary string) (string, error) {
 	}
 	return "", fmt.Errorf("no text generated")
 }
+
+// receive requests the answer as a stream and renders it as it arrives.
+func (o *OpenAI) receive(req openai.ChatCompletionRequest) (string, error) {
+	req.Stream = true
+	stream, err := o.client.CreateChatCompletionStream(o.ctx, req)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+	var full strings.Builder
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+		if err := render(o.stream, &full, chunk.Choices[0].Delta.Content); err != nil {
+			return "", err
+		}
+	}
+	if err := finish(o.stream); err != nil {
+		return "", err
+	}
+	if full.Len() == 0 {
+		return "", fmt.Errorf("no text generated")
+	}
+	return strings.TrimSpace(full.String()), nil
+}
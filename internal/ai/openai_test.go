@@ -1,8 +1,12 @@
 package ai
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -36,35 +40,39 @@ func (m echo) CreateChatCompletion(ctx context.Context, req openai.ChatCompletio
 	return openai.ChatCompletionResponse{}, fmt.Errorf("nothing to answer")
 }
 
+func (m echo) CreateChatCompletionStream(ctx context.Context, req openai.ChatCompletionRequest) (*openai.ChatCompletionStream, error) {
+	return nil, fmt.Errorf("streaming is not supported by echo")
+}
+
 func TestOpenAi_GeneratesTitle(t *testing.T) {
-	openAI := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en")
+	openAI := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en", nil)
 
-	title, err := openAI.PrTitle("123", "test diff", "successful issue-description", "project-summary")
+	title, err := openAI.PrTitle("123", "test diff", "successful issue-description", "project-summary", "")
 
 	require.NoError(t, err, "Expected no error when generating PR title")
 	assert.Contains(t, title, "generate a one-line PR title", "anser should contain the command to generate a PR title")
 }
 
 func TestOpenAi_GeneratesTitleWithError(t *testing.T) {
-	openAI := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en")
+	openAI := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en", nil)
 
-	_, err := openAI.PrTitle("123", "test diff", "error issue-description", "project-summary")
+	_, err := openAI.PrTitle("123", "test diff", "error issue-description", "project-summary", "")
 
 	require.Error(t, err, "Expected error when generating PR title with error input")
 	assert.Equal(t, "error during openai request", err.Error(), "Expected error message to match mock response")
 }
 
 func TestOpenAi_GeneratesBody(t *testing.T) {
-	openai := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en")
+	openai := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en", nil)
 
-	body, err := openai.PrBody("test diff", "successful issue-description", "project-summary")
+	body, err := openai.PrBody("test diff", "successful issue-description", "project-summary", "", "")
 
 	require.NoError(t, err, "Expected no error when generating PR body")
 	assert.Contains(t, body, "generate a well-structured pull request body", "Expected PR body to match mock response")
 }
 
 func TestOpenAI_SuggestBranch(t *testing.T) {
-	openAI := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en")
+	openAI := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en", nil)
 	description := "successful description"
 
 	branch, err := openAI.SuggestBranch(description)
@@ -75,7 +83,7 @@ func TestOpenAI_SuggestBranch(t *testing.T) {
 }
 
 func TestOpenAI_SuggestBranch_Error(t *testing.T) {
-	openAI := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en")
+	openAI := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en", nil)
 	description := "error description"
 
 	_, err := openAI.SuggestBranch(description)
@@ -85,7 +93,7 @@ func TestOpenAI_SuggestBranch_Error(t *testing.T) {
 }
 
 func TestOpenAI_ReleaseNotes(t *testing.T) {
-	openAI := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en")
+	openAI := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en", nil)
 	changes := "successful changes"
 
 	notes, err := openAI.ReleaseNotes(changes)
@@ -96,7 +104,7 @@ func TestOpenAI_ReleaseNotes(t *testing.T) {
 }
 
 func TestOpenAI_ReleaseNotes_Error(t *testing.T) {
-	openAI := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en")
+	openAI := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en", nil)
 	changes := "error changes"
 
 	_, err := openAI.ReleaseNotes(changes)
@@ -106,7 +114,7 @@ func TestOpenAI_ReleaseNotes_Error(t *testing.T) {
 }
 
 func TestOpenAI_IssueTitle(t *testing.T) {
-	openAI := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en")
+	openAI := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en", nil)
 	input := "successful issue input"
 
 	title, err := openAI.IssueTitle(input, "project-summary")
@@ -117,7 +125,7 @@ func TestOpenAI_IssueTitle(t *testing.T) {
 }
 
 func TestOpenAI_IssueBody(t *testing.T) {
-	openAI := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en")
+	openAI := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en", nil)
 	input := "successful issue input"
 
 	body, err := openAI.IssueBody(input, "project-summary")
@@ -128,11 +136,11 @@ func TestOpenAI_IssueBody(t *testing.T) {
 }
 
 func TestOpenAI_CommitMessage(t *testing.T) {
-	openAI := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en")
+	openAI := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en", nil)
 	number := "123"
 	diff := "successful diff"
 
-	message, err := openAI.CommitMessage(number, diff, "")
+	message, err := openAI.CommitMessage(number, diff, "", "")
 
 	require.NoError(t, err, "Expected no error when generating commit message")
 	assert.Contains(t, message, diff, "Expected commit message to contain diff")
@@ -140,7 +148,7 @@ func TestOpenAI_CommitMessage(t *testing.T) {
 }
 
 func TestOpenAI_IssueLabels(t *testing.T) {
-	openAI := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en")
+	openAI := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en", nil)
 	issue := "I successfully suggest using 'feature' label here"
 	available := []string{"bug", "feature", "enhancement"}
 
@@ -149,3 +157,94 @@ func TestOpenAI_IssueLabels(t *testing.T) {
 	require.NoError(t, err, "Expected no error when generating issue labels")
 	assert.ElementsMatch(t, labels, available, "Expected issue labels to match available labels")
 }
+
+func TestOpenAi_Streams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request openai.ChatCompletionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request), "Failed to read request body")
+		assert.True(t, request.Stream, "Expected a streaming request")
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range []string{"feat(#1): ", "add ", "streaming"} {
+			_, err := fmt.Fprintf(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"%s\"}}]}\n\n", chunk)
+			require.NoError(t, err, "Failed to write response")
+		}
+		_, err := fmt.Fprint(w, "data: [DONE]\n\n")
+		require.NoError(t, err, "Failed to write response")
+	}))
+	defer server.Close()
+	conf := openai.DefaultConfig("test-token")
+	conf.BaseURL = server.URL
+	var out bytes.Buffer
+	brain := NewOpenAIWithClient(openai.NewClientWithConfig(conf), "test-model", 0.5, false, "en", &out)
+
+	msg, err := brain.CommitMessage("#1", "diff", "", "")
+
+	require.NoError(t, err, "Expected no error when streaming a commit message")
+	assert.Equal(t, "feat(#1): add streaming", msg, "Expected the full answer to be returned")
+	assert.Equal(t, "feat(#1): add streaming\n", out.String(), "Expected the answer to be rendered as it arrives")
+}
+
+func TestOpenAi_TrimsStreamedAnswer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range []string{`\n`, "add title", `\n\n`} {
+			_, err := fmt.Fprintf(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"%s\"}}]}\n\n", chunk)
+			require.NoError(t, err, "Failed to write response")
+		}
+		_, err := fmt.Fprint(w, "data: [DONE]\n\n")
+		require.NoError(t, err, "Failed to write response")
+	}))
+	defer server.Close()
+	conf := openai.DefaultConfig("test-token")
+	conf.BaseURL = server.URL
+	brain := NewOpenAIWithClient(openai.NewClientWithConfig(conf), "test-model", 0.5, false, "en", &bytes.Buffer{})
+
+	title, err := brain.IssueTitle("input", "")
+
+	require.NoError(t, err, "Expected no error when streaming a title")
+	assert.Equal(t, "add title", title, "Expected the streamed answer to be trimmed")
+}
+
+func TestOpenAi_StreamError(t *testing.T) {
+	brain := NewOpenAIWithClient(NewEcho(), "test-model", 0.5, false, "en", &bytes.Buffer{})
+
+	_, err := brain.CommitMessage("#1", "diff", "", "")
+
+	require.Error(t, err, "Expected an error when the stream can't be opened")
+	assert.Contains(t, err.Error(), "streaming is not supported")
+}
+
+func TestOpenAi_CompatibleEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/chat/completions", r.URL.Path, "Expected the base URL to be honoured")
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		assert.Equal(t, "aidy", r.Header.Get("X-Title"), "Expected the extra header to be sent")
+		_, err := fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"routed"}}]}`)
+		require.NoError(t, err, "Failed to write response")
+	}))
+	defer server.Close()
+	client := NewClient(context.Background(), Policy{}).WithHeaders(map[string]string{"X-Title": "aidy"})
+	brain := NewOpenAI("test-token", "meta-llama/llama-3-70b", 0, 0.5, false, "en", nil, client, Endpoint{Url: server.URL + "/api/v1/"}, nil)
+
+	msg, err := brain.Summary("readme")
+
+	require.NoError(t, err, "Expected no error from a compatible endpoint")
+	assert.Equal(t, "routed", msg)
+}
+
+func TestOpenAi_AzureEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/openai/deployments/gpt-4o/chat/completions", r.URL.Path, "Expected the Azure deployment path")
+		assert.Equal(t, "2024-06-01", r.URL.Query().Get("api-version"), "Expected the API version")
+		assert.Equal(t, "test-token", r.Header.Get("api-key"), "Expected Azure authentication")
+		_, err := fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"azure"}}]}`)
+		require.NoError(t, err, "Failed to write response")
+	}))
+	defer server.Close()
+	brain := NewOpenAI("test-token", "gpt-4o", 0, 0.5, false, "en", nil, nil, Endpoint{Url: server.URL, Version: "2024-06-01"}, nil)
+
+	msg, err := brain.Summary("readme")
+
+	require.NoError(t, err, "Expected no error from Azure OpenAI")
+	assert.Equal(t, "azure", msg)
+}
@@ -1,28 +1,35 @@
 package ai
 
+// Built-in prompt templates.
+// They are rendered with text/template and a Prompt, see Prompts.
 const (
 	PrTitle = `You are an expert software engineer who creates concise and informative pull request titles for GitHub.
 
 Your task is to generate a one-line PR title based on the provided diff and issue description.
 
 <diff>
-%s
+{{.Diff}}
 </diff>
+{{if .Commits}}
+The branch consists of the following commits, oldest first, they tell the intent of the changes:
 
+<commits>
+{{.Commits}}
+</commits>
+{{end}}
 This pull request addresses the following issue:
 
 <issue>
-%s
+{{.Issue}}
 </issue>
 
 Carefully review both the diff and the issue description. Then, generate a PR title in the following format:
 
-<type>(%s): <description>
+{{.Format}}
 
-- Valid <type> values: fix, feat, build, chore, ci, docs, style, refactor, perf, test.
-- Always include the issue reference %s in the title.
+{{.Rules}}
 - Use the imperative mood (e.g., "add feature", not "added feature" or "adding feature").
-- Keep the title within 72 characters.
+- Keep the title within {{.MaxLength}} characters.
 - Do not include explanations, comments, or line breaks. Return only the title line.
 `
 
@@ -31,16 +38,38 @@ Carefully review both the diff and the issue description. Then, generate a PR ti
 Your task is to generate a well-structured pull request body based on the provided diff and issue description.
 
 <diff>
-%s
+{{.Diff}}
 </diff>
+{{if .Commits}}
+The branch consists of the following commits, oldest first, they tell the intent of the changes:
 
+<commits>
+{{.Commits}}
+</commits>
+{{end}}
 This pull request addresses the following issue:
 
 <issue>
-%s
+{{.Issue}}
 </issue>
 
-Carefully analyze the diff and issue. Then, generate a concise and informative pull request description.
+Carefully analyze the diff and issue. Then, {{if .Template}}fill in the pull request template of the repository:
+
+<template>
+{{.Template}}
+</template>
+
+The description must:
+- Keep all headings of the template, in the same order.
+- Fill in each section briefly, in plain words; write "N/A" under the sections that don't apply.
+- Keep all checkboxes, and check ("- [x]") only the ones the changes surely fulfil.
+- Drop the HTML comments of the template ("<!-- ... -->"), they are instructions for you.
+- Use backticks for code or identifiers when appropriate.
+
+Formatting rules:
+- Do not add sections the template doesn't have.
+- Reply only with the filled in template — no additional text or explanations.
+{{else}}generate a concise and informative pull request description.
 
 The description must:
 - Briefly explain what the PR does (one or two sentences).
@@ -54,38 +83,97 @@ The description must:
 Formatting rules:
 - Do not add section headers.
 - Reply only with the pull request body — no additional text or explanations.
-`
+{{end}}`
 
-	CommitMsg = `You are an expert software engineer who writes concise, one-line Git commit messages based on code diffs.
+	CommitMsg = `You are an expert software engineer who writes concise{{if not .Body}}, one-line{{end}} Git commit messages based on code diffs.
 
-Your task is to generate a single-line commit message for the following changes. 
+Your task is to generate a {{if .Body}}commit message with a subject line and a body{{else}}single-line commit message{{end}} for the following changes. 
 
 <diff>
-%s
+{{.Diff}}
 </diff>
 
 Review diffs carefully.
 
-The commit message must follow this format:
-<type>(%s): <description>
+The {{if .Body}}subject line{{else}}commit message{{end}} must follow this format:
+{{.Format}}
 
 Where:
-- <type> is one of: fix, feat, build, chore, ci, docs, style, refactor, perf, test
-- %s is the issue reference (do not modify it)
+{{.Rules}}
 
 Ensure the message:
 - Starts with the appropriate prefix
 - Uses the imperative mood (e.g., "Add feature", not "Added feature" or "Adding feature")
-- Does not exceed 72 characters
+- Does not exceed {{.MaxLength}} characters{{if .Body}} in the subject line
+
+After the subject line, add an empty line and a body that:
+- Explains why the change was made, not how
+- Is wrapped at 72 characters
+- Ends with a "BREAKING CHANGE: <description>" line if the change breaks compatibility, and the subject has "!" after the type{{end}}{{if .Hint}}
+
+Take into account the following remarks:
+<remarks>
+{{.Hint}}
+</remarks>{{end}}
+
+Reply with the commit message only — no explanations{{if .Body}} or comments{{else}}, comments, or line breaks{{end}}.`
+
+	SplitPlan = `You are an expert software engineer who splits large changes into small, atomic Git commits.
+
+Your task is to group the following changes into logical commits, each with its own commit message.
+
+<changes>
+{{.Diff}}
+</changes>
+
+Each commit message must follow this format:
+{{.Format}}
+
+Where:
+{{.Rules}}
+
+Reply with the plan only, in the following format, without explanations or comments:
+
+<commit message>
+  <file>
+  <file>#<hunk>
+
+<commit message>
+  <file>
+
+Where:
+- Each commit starts with its message, followed by its files, one per line, indented with two spaces
+- Commits are separated by an empty line
+- A file is listed with its path as it appears in the changes
+- To put only some hunks of a file into a commit, add '#' and the hunk number, e.g. 'main.go#2'
+- Every file or hunk goes to exactly one commit
+- Commits are ordered so that each of them builds on the previous ones
+- Commit messages use the imperative mood and do not exceed {{.MaxLength}} characters
+`
+
+	DiffSummary = `You are an expert software engineer who reviews large changes one part at a time.
+
+Summarize the following part of a large diff for a reviewer who will never see the diff itself:
+
+<diff>
+{{.Diff}}
+</diff>
+
+Guidelines:
+- Name the files or packages that changed and describe what changed in each and why, if it's evident
+- Mention new, removed or renamed functions, types, commands and options
+- Point out changes of behaviour, public APIs, configuration and dependencies
+- Skip formatting, whitespace and other changes that don't affect the meaning
+- Use short bullet points, at most 15 of them
 
-Reply with the commit message only — no explanations, comments, or line breaks.`
+Reply with the summary only — no introductions, explanations, or comments.`
 
 	IssueTitle = `You are an expert software engineer who writes clear and concise titles for GitHub issues.
 
 Generate a one-line issue title based on the following user input:
 
 <input>
-%s
+{{.Input}}
 </input>
 
 The title should:
@@ -102,7 +190,7 @@ Reply only with the issue title — no explanations, comments, or line breaks.
 Generate an issue body based on the following user input:
 
 <input>
-%s
+{{.Input}}
 </input>
 
 The description should:
@@ -121,13 +209,13 @@ Reply only with the issue body — no explanations, comments, or extra formattin
 Your task is to select the most relevant labels for the following issue:
 
 <issue>
-%s
+{{.Issue}}
 </issue>
 
 Available labels:
 
 <labels>
-%s
+{{.Labels}}
 </labels>
 
 Reply only with the list of selected labels — no explanations, comments, or additional formatting.`
@@ -137,7 +225,7 @@ Focus on key features, purpose, technologies used, and any setup or usage highli
 The summary must be concise, comprehensive, and free of any additional commentary or explanation — just the raw summary text.
 
 <readme>
-%s
+{{.Readme}}
 </readme>
 
 Reply only with the issue body — no explanations, comments, or extra formatting.`
@@ -145,7 +233,7 @@ Reply only with the issue body — no explanations, comments, or extra formattin
 Generate a branch name based on the following issue description:
 
 <description>
-%s
+{{.Input}}
 </description>
 
 Create a branch name using one or two words that describe the issue.
@@ -163,7 +251,7 @@ Reply only with the branch name — no explanations, comments, or extra formatti
 Generate clear, concise release notes based on the list of commit messages provided below.
 
 <commits>
-%s
+{{.Commits}}
 </commits>
 
 Your task:
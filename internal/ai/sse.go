@@ -0,0 +1,61 @@
+package ai
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// events reads a server-sent events stream and calls handle with the payload
+// of every 'data:' line until the stream ends or the '[DONE]' marker arrives.
+// Event names, comments and blank separator lines are skipped.
+func events(body io.Reader, handle func(data string) error) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			return nil
+		}
+		if data == "" {
+			continue
+		}
+		if err := handle(data); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading stream: %w", err)
+	}
+	return nil
+}
+
+// render writes a chunk of the streamed answer to out, if streaming is enabled,
+// and appends it to the full answer.
+func render(out io.Writer, full *strings.Builder, chunk string) error {
+	full.WriteString(chunk)
+	if out == nil {
+		return nil
+	}
+	if _, err := io.WriteString(out, chunk); err != nil {
+		return fmt.Errorf("error rendering stream: %w", err)
+	}
+	return nil
+}
+
+// finish ends the rendered answer with a line break, so that
+// whatever is printed next starts on its own line.
+func finish(out io.Writer) error {
+	if out == nil {
+		return nil
+	}
+	if _, err := io.WriteString(out, "\n"); err != nil {
+		return fmt.Errorf("error rendering stream: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package ai
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvents_ReadsDataLines(t *testing.T) {
+	stream := "event: delta\ndata: first\n\n: comment\ndata: second\n\ndata: [DONE]\ndata: ignored\n"
+	var received []string
+
+	err := events(strings.NewReader(stream), func(data string) error {
+		received = append(received, data)
+		return nil
+	})
+
+	require.NoError(t, err, "Expected no error when reading the stream")
+	assert.Equal(t, []string{"first", "second"}, received)
+}
+
+func TestEvents_StopsOnHandlerError(t *testing.T) {
+	err := events(strings.NewReader("data: first\ndata: second\n"), func(data string) error {
+		return fmt.Errorf("bad chunk '%s'", data)
+	})
+
+	require.Error(t, err, "Expected the handler error to be returned")
+	assert.Equal(t, "bad chunk 'first'", err.Error())
+}
+
+func TestRender_WritesChunks(t *testing.T) {
+	var out bytes.Buffer
+	var full strings.Builder
+
+	require.NoError(t, render(&out, &full, "Hello, "))
+	require.NoError(t, render(&out, &full, "world"))
+
+	assert.Equal(t, "Hello, world", out.String())
+	assert.Equal(t, "Hello, world", full.String())
+}
+
+func TestRender_WithoutOutput(t *testing.T) {
+	var full strings.Builder
+
+	require.NoError(t, render(nil, &full, "chunk"))
+
+	assert.Equal(t, "chunk", full.String())
+}
+
+func TestFinish_EndsLine(t *testing.T) {
+	var out bytes.Buffer
+
+	require.NoError(t, finish(&out))
+	require.NoError(t, finish(nil))
+
+	assert.Equal(t, "\n", out.String())
+}
@@ -0,0 +1,151 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/volodya-lombrozo/aidy/internal/convention"
+)
+
+// Prompt holds the values available in prompt templates, e.g. '{{.Diff}}'.
+type Prompt struct {
+	// Diff is the code change under discussion.
+	Diff string
+	// Issue is the description of the related issue.
+	Issue string
+	// Summary is the project summary, if it's enabled.
+	Summary string
+	// Number is the issue reference, e.g. '#42' or 'PROJ-42'.
+	Number string
+	// Labels is the comma-separated list of available labels.
+	Labels string
+	// Language is the language of the answer, e.g. 'en'.
+	Language string
+	// Input is the free text given by the user.
+	Input string
+	// Readme is the content of the project README.
+	Readme string
+	// Commits is the list of commit messages of a release or of a pull request branch.
+	Commits string
+	// Template is the pull request template of the repository to fill in, if it has one.
+	Template string
+	// Format is the shape of a commit message or PR title, e.g. '<type>(#42): <description>'.
+	Format string
+	// Rules explain the placeholders of the Format, one per line.
+	Rules string
+	// Hint is the feedback on a previous answer, e.g. the rules it broke.
+	Hint string
+	// Body tells whether a commit message should have a body after the subject line.
+	Body bool
+	// MaxLength is the maximum length of a commit message or PR title.
+	MaxLength int
+}
+
+// Prompts renders prompt templates.
+// A template named 'commit' is read from 'commit.tmpl' in the first directory
+// that has it, falling back to the built-in one.
+// Format, Rules, MaxLength and Body are filled from the commit convention.
+type Prompts struct {
+	dirs       []string
+	convention convention.Convention
+}
+
+var builtin = map[string]string{
+	"pr-title":      PrTitle,
+	"pr-body":       PrBody,
+	"commit":        CommitMsg,
+	"issue-title":   IssueTitle,
+	"issue-body":    IssueBody,
+	"labels":        Labels,
+	"summary":       Summary,
+	"branch":        BranchName,
+	"release-notes": ReleaseNotes,
+	"split":         SplitPlan,
+	"diff-summary":  DiffSummary,
+}
+
+// NewPrompts creates prompts that are looked up in dirs in the given order.
+func NewPrompts(dirs ...string) *Prompts {
+	return &Prompts{dirs: dirs}
+}
+
+// WithConvention returns prompts that describe the given commit convention.
+func (p *Prompts) WithConvention(c convention.Convention) *Prompts {
+	return &Prompts{dirs: p.dirs, convention: c}
+}
+
+// Convention returns the commit convention the prompts describe.
+func (p *Prompts) Convention() convention.Convention {
+	return p.convention
+}
+
+// Defaults returns the built-in templates by their names.
+func Defaults() map[string]string {
+	res := make(map[string]string, len(builtin))
+	for name, text := range builtin {
+		res[name] = text
+	}
+	return res
+}
+
+// Names returns the names of all templates in alphabetical order.
+func Names() []string {
+	res := make([]string, 0, len(builtin))
+	for name := range builtin {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Render fills the template with the given name.
+func (p *Prompts) Render(name string, data Prompt) (string, error) {
+	text, origin, err := p.find(name)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("error parsing prompt template %s: %w", origin, err)
+	}
+	if data.Format == "" {
+		data.Format = p.convention.Format(data.Number)
+	}
+	if data.Rules == "" {
+		data.Rules = p.convention.Rules(data.Number)
+	}
+	if data.MaxLength == 0 {
+		data.MaxLength = p.convention.Limit()
+	}
+	data.Body = data.Body || p.convention.Body
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("error rendering prompt template %s: %w", origin, err)
+	}
+	return out.String(), nil
+}
+
+// find returns the text of the template and where it comes from.
+func (p *Prompts) find(name string) (string, string, error) {
+	for _, dir := range p.dirs {
+		path := filepath.Join(dir, name+".tmpl")
+		content, err := os.ReadFile(path)
+		if err == nil {
+			return string(content), path, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", "", fmt.Errorf("error reading prompt template %s: %w", path, err)
+		}
+	}
+	text, ok := builtin[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown prompt template '%s'", name)
+	}
+	return text, fmt.Sprintf("'%s'", name), nil
+}
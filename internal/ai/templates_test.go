@@ -0,0 +1,142 @@
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volodya-lombrozo/aidy/internal/convention"
+)
+
+func TestPrompts_RendersBuiltin(t *testing.T) {
+	prompts := NewPrompts(t.TempDir())
+
+	prompt, err := prompts.Render("commit", Prompt{Diff: "the-diff", Number: "#9"})
+
+	require.NoError(t, err, "Expected the built-in template to be rendered")
+	assert.Contains(t, prompt, "<diff>\nthe-diff\n</diff>")
+	assert.Contains(t, prompt, "<type>(#9): <description>")
+}
+
+func TestPrompts_PrefersFirstDirectory(t *testing.T) {
+	repo := t.TempDir()
+	home := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "commit.tmpl"), []byte("repo {{.Number}} {{.Language}}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(home, "commit.tmpl"), []byte("home {{.Number}}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(home, "pr-title.tmpl"), []byte("home title {{.Issue}}"), 0644))
+	prompts := NewPrompts(repo, home)
+
+	commit, err := prompts.Render("commit", Prompt{Number: "#9", Language: "de"})
+	require.NoError(t, err, "Expected the repository template to be rendered")
+	title, err := prompts.Render("pr-title", Prompt{Issue: "issue"})
+	require.NoError(t, err, "Expected the home template to be rendered")
+
+	assert.Equal(t, "repo #9 de", commit, "Expected the repository template to win")
+	assert.Equal(t, "home title issue", title, "Expected the home template to be used")
+}
+
+func TestPrompts_ReportsBrokenTemplate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "summary.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte("{{.Readme"), 0644))
+
+	_, err := NewPrompts(dir).Render("summary", Prompt{})
+
+	require.Error(t, err, "Expected an error for a broken template")
+	assert.Contains(t, err.Error(), path, "Expected the file to be named")
+}
+
+func TestPrompts_ReportsUnknownField(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "branch.tmpl"), []byte("{{.Ticket}}"), 0644))
+
+	_, err := NewPrompts(dir).Render("branch", Prompt{})
+
+	require.Error(t, err, "Expected an error for an unknown field")
+	assert.Contains(t, err.Error(), "Ticket")
+}
+
+func TestPrompts_UnknownTemplate(t *testing.T) {
+	_, err := NewPrompts().Render("poem", Prompt{})
+
+	require.Error(t, err, "Expected an error for an unknown template")
+	assert.Contains(t, err.Error(), "unknown prompt template 'poem'")
+}
+
+func TestPrompts_DefaultsCoverAllNames(t *testing.T) {
+	defaults := Defaults()
+
+	assert.Len(t, Names(), len(defaults))
+	for _, name := range Names() {
+		_, err := NewPrompts().Render(name, Prompt{})
+		assert.NoError(t, err, "Expected built-in template '%s' to be valid", name)
+	}
+}
+
+func TestPrompts_ChangeProviderPrompts(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "summary.tmpl"), []byte("custom: {{.Readme}}"), 0644))
+	server := echoServer(t)
+	defer server.Close()
+	ai := NewDeepSeek("test-token", "", 0, false, "en", nil, nil, Endpoint{}, NewPrompts(dir)).(*DeepSeek)
+	ai.url = server.URL
+
+	result, err := ai.Summary("readme")
+
+	require.NoError(t, err, "Expected no error with a custom template")
+	assert.Equal(t, "custom: readme", result)
+}
+
+func TestPrompts_DoNotLeakBetweenProviders(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "summary.tmpl"), []byte("custom: {{.Readme}}"), 0644))
+	server := echoServer(t)
+	defer server.Close()
+	custom := NewDeepSeek("test-token", "", 0, false, "en", nil, nil, Endpoint{}, NewPrompts(dir)).(*DeepSeek)
+	custom.url = server.URL
+	builtin := NewDeepSeek("test-token", "", 0, false, "en", nil, nil, Endpoint{}, nil).(*DeepSeek)
+	builtin.url = server.URL
+
+	_, err := custom.Summary("readme")
+	require.NoError(t, err, "Expected no error with a custom template")
+	result, err := builtin.Summary("readme")
+
+	require.NoError(t, err, "Expected no error with the built-in template")
+	assert.Contains(t, result, "<readme>\nreadme\n</readme>", "Expected the built-in template to be used")
+}
+
+func TestPrompts_DescribesConvention(t *testing.T) {
+	prompts := NewPrompts().WithConvention(convention.Convention{Types: []string{"add", "fix"}, Issue: "trailer", Scope: "component", MaxLength: 50})
+
+	prompt, err := prompts.Render("commit", Prompt{Diff: "the-diff", Number: "#9"})
+
+	require.NoError(t, err, "Expected the built-in template to be rendered")
+	assert.Contains(t, prompt, "<type>(<scope>): <description>")
+	assert.Contains(t, prompt, "<type> is one of: add, fix")
+	assert.Contains(t, prompt, "Does not exceed 50 characters")
+	assert.NotContains(t, prompt, "(#9)", "Expected the issue to be kept out of the scope")
+}
+
+func TestPrompts_AsksForBody(t *testing.T) {
+	prompts := NewPrompts().WithConvention(convention.Convention{Body: true})
+
+	prompt, err := prompts.Render("commit", Prompt{Diff: "the-diff", Number: "#9"})
+
+	require.NoError(t, err, "Expected the built-in template to be rendered")
+	assert.Contains(t, prompt, "commit message with a subject line and a body")
+	assert.Contains(t, prompt, "Explains why the change was made, not how")
+	assert.NotContains(t, prompt, "no explanations, comments, or line breaks")
+}
+
+func TestPrompts_FillsInPullRequestTemplate(t *testing.T) {
+	prompts := NewPrompts()
+
+	prompt, err := prompts.Render("pr-body", Prompt{Diff: "the-diff", Template: "## Summary\n\n- [ ] Tests added"})
+
+	require.NoError(t, err, "Expected the built-in template to be rendered")
+	assert.Contains(t, prompt, "<template>\n## Summary\n\n- [ ] Tests added\n</template>")
+	assert.Contains(t, prompt, "Keep all headings of the template")
+	assert.NotContains(t, prompt, "Be approximately 50–100 characters long", "Expected the free-form rules to be replaced")
+}
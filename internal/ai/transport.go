@@ -0,0 +1,218 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/volodya-lombrozo/aidy/internal/log"
+)
+
+// Policy describes how patient AI HTTP calls are.
+type Policy struct {
+	// Timeout limits how long a single attempt waits for the response headers.
+	// Zero means no limit.
+	Timeout time.Duration
+	// Retries is how many times a request is repeated after a 429 or 5xx answer
+	// or a network failure.
+	Retries int
+	// Backoff is the delay before the first retry; it doubles with every attempt.
+	Backoff time.Duration
+	// MaxBackoff caps a single delay, including the one asked for by 'Retry-After'.
+	MaxBackoff time.Duration
+}
+
+// Client is the HTTP layer shared by all AI providers.
+// It repeats failed requests according to a Policy and cancels them
+// together with its context.
+type Client struct {
+	ctx  context.Context
+	http *http.Client
+}
+
+// Endpoint tells OpenAI-compatible providers where to send requests,
+// e.g. to Azure OpenAI, OpenRouter, vLLM or an internal gateway.
+// The zero value means the provider's own API.
+type Endpoint struct {
+	// Url is the base URL the API paths are appended to, e.g. 'https://openrouter.ai/api/v1'.
+	Url string
+	// Version is sent as the 'api-version' query parameter, as Azure OpenAI expects.
+	Version string
+}
+
+// DefaultPolicy returns the policy used when nothing is configured.
+func DefaultPolicy() Policy {
+	return Policy{
+		Timeout:    2 * time.Minute,
+		Retries:    3,
+		Backoff:    time.Second,
+		MaxBackoff: 30 * time.Second,
+	}
+}
+
+// NewClient creates a client whose requests follow the policy
+// and are cancelled when ctx is done.
+func NewClient(ctx context.Context, policy Policy) *Client {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.ResponseHeaderTimeout = policy.Timeout
+	return &Client{
+		ctx: ctx,
+		http: &http.Client{
+			Transport: &retrying{
+				base:   base,
+				policy: policy,
+				log:    log.Default(),
+			},
+		},
+	}
+}
+
+// once returns a client that sends every request a single time without a timeout.
+func once() *Client {
+	return &Client{ctx: context.Background(), http: http.DefaultClient}
+}
+
+// WithHeaders returns a copy of the client that adds the headers to every request.
+func (c *Client) WithHeaders(headers map[string]string) *Client {
+	if len(headers) == 0 {
+		return c
+	}
+	base := c.http.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Client{
+		ctx: c.ctx,
+		http: &http.Client{
+			Transport: &headed{base: base, headers: headers},
+			Timeout:   c.http.Timeout,
+		},
+	}
+}
+
+// Do sends the request within the client context.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	return c.http.Do(req.WithContext(c.ctx))
+}
+
+type headed struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+func (h *headed) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := req.Clone(req.Context())
+	for name, value := range h.headers {
+		next.Header.Set(name, value)
+	}
+	return h.base.RoundTrip(next)
+}
+
+type retrying struct {
+	base   http.RoundTripper
+	policy Policy
+	log    log.Logger
+}
+
+func (r *retrying) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	attempt := req
+	for try := 0; ; try++ {
+		resp, err := r.base.RoundTrip(attempt)
+		if try >= r.policy.Retries || ctx.Err() != nil || !retriable(resp, err) {
+			return resp, err
+		}
+		next, rerr := rewind(req)
+		if rerr != nil {
+			return resp, err
+		}
+		wait := r.delay(try, resp)
+		if err != nil {
+			r.log.Warn("request to %s failed: %v, retrying in %s", req.URL.Host, err, wait)
+		} else {
+			r.log.Warn("request to %s returned %s, retrying in %s", req.URL.Host, resp.Status, wait)
+			discard(resp)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		attempt = next
+	}
+}
+
+// delay calculates how long to wait before the next attempt.
+// 'Retry-After' of the last response wins over the exponential backoff.
+func (r *retrying) delay(try int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return r.limit(wait)
+		}
+	}
+	wait := r.limit(r.policy.Backoff << try)
+	if wait <= 0 {
+		return 0
+	}
+	return wait/2 + rand.N(wait/2+1)
+}
+
+func (r *retrying) limit(wait time.Duration) time.Duration {
+	if r.policy.MaxBackoff > 0 && (wait > r.policy.MaxBackoff || wait < 0) {
+		return r.policy.MaxBackoff
+	}
+	return wait
+}
+
+func retriable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	code := resp.StatusCode
+	return code == http.StatusTooManyRequests || code >= 500 && code != http.StatusNotImplemented
+}
+
+// retryAfter parses the 'Retry-After' header given either in seconds or as an HTTP date.
+func retryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		if at.Before(now) {
+			return 0, true
+		}
+		return at.Sub(now), true
+	}
+	return 0, false
+}
+
+// rewind prepares a copy of the request with a fresh body for another attempt.
+func rewind(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return next, nil
+	}
+	if req.GetBody == nil {
+		return nil, errors.New("request body can't be sent twice")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	next.Body = body
+	return next, nil
+}
+
+func discard(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	_ = resp.Body.Close()
+}
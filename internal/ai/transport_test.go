@@ -0,0 +1,176 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_RetriesOnTooManyRequests(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err, "Failed to read request body")
+		assert.Equal(t, "payload", string(body), "Expected the body to be sent with every attempt")
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, err = w.Write([]byte("ok"))
+		require.NoError(t, err, "Failed to write response")
+	}))
+	defer server.Close()
+	client := NewClient(context.Background(), Policy{Retries: 3, Backoff: time.Millisecond})
+	req, err := http.NewRequest("POST", server.URL, bytes.NewBufferString("payload"))
+	require.NoError(t, err, "Failed to create request")
+
+	resp, err := client.Do(req)
+
+	require.NoError(t, err, "Expected the request to succeed after retries")
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), calls.Load(), "Expected two retries")
+}
+
+func TestClient_GivesUpAfterRetries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	client := NewClient(context.Background(), Policy{Retries: 2, Backoff: time.Millisecond})
+	req, err := http.NewRequest("GET", server.URL, nil)
+	require.NoError(t, err, "Failed to create request")
+
+	resp, err := client.Do(req)
+
+	require.NoError(t, err, "Expected the last response to be returned")
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(3), calls.Load(), "Expected the first attempt and two retries")
+}
+
+func TestClient_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	client := NewClient(context.Background(), Policy{Retries: 3, Backoff: time.Millisecond})
+	req, err := http.NewRequest("GET", server.URL, nil)
+	require.NoError(t, err, "Failed to create request")
+
+	resp, err := client.Do(req)
+
+	require.NoError(t, err, "Expected the response to be returned")
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, int32(1), calls.Load(), "Expected no retries for 401")
+}
+
+func TestClient_TimesOut(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	client := NewClient(context.Background(), Policy{Timeout: 10 * time.Millisecond})
+	req, err := http.NewRequest("GET", server.URL, nil)
+	require.NoError(t, err, "Failed to create request")
+
+	_, err = client.Do(req)
+
+	require.Error(t, err, "Expected the request to time out")
+	assert.Contains(t, err.Error(), "timeout")
+}
+
+func TestClient_StopsWhenContextIsCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	client := NewClient(ctx, Policy{Retries: 3, Backoff: time.Millisecond, MaxBackoff: time.Minute})
+	req, err := http.NewRequest("GET", server.URL, nil)
+	require.NoError(t, err, "Failed to create request")
+
+	start := time.Now()
+	_, err = client.Do(req)
+
+	require.Error(t, err, "Expected the cancelled context to stop retries")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second, "Expected not to wait for 'Retry-After'")
+}
+
+func TestRetrying_HonoursRetryAfter(t *testing.T) {
+	r := &retrying{policy: Policy{Backoff: time.Millisecond, MaxBackoff: time.Minute}}
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"7"}}}
+
+	assert.Equal(t, 7*time.Second, r.delay(0, resp))
+}
+
+func TestRetrying_CapsRetryAfter(t *testing.T) {
+	r := &retrying{policy: Policy{Backoff: time.Millisecond, MaxBackoff: 2 * time.Second}}
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"3600"}}}
+
+	assert.Equal(t, 2*time.Second, r.delay(0, resp))
+}
+
+func TestRetrying_BacksOffExponentiallyWithJitter(t *testing.T) {
+	r := &retrying{policy: Policy{Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}}
+
+	for try, max := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		wait := r.delay(try, nil)
+		assert.GreaterOrEqual(t, wait, max*time.Millisecond/2, "Expected at least half of the backoff for try %d", try)
+		assert.LessOrEqual(t, wait, max*time.Millisecond, "Expected at most the backoff for try %d", try)
+	}
+}
+
+func TestRetryAfter_ParsesDate(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	wait, ok := retryAfter("Wed, 01 Jan 2025 12:00:30 GMT", now)
+
+	assert.True(t, ok, "Expected the date to be parsed")
+	assert.Equal(t, 30*time.Second, wait)
+}
+
+func TestRetryAfter_IgnoresGarbage(t *testing.T) {
+	_, ok := retryAfter("soon", time.Now())
+
+	assert.False(t, ok, "Expected an unparsable header to be ignored")
+}
+
+func TestDeepSeekAI_RetriesOverloadedApi(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, err := w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"feat(#5): retry"}}]}`))
+		require.NoError(t, err, "Failed to write response")
+	}))
+	defer server.Close()
+	client := NewClient(context.Background(), Policy{Retries: 1, Backoff: time.Millisecond})
+	ai := NewDeepSeek("test-token", "", 0, false, "en", nil, client, Endpoint{}, nil).(*DeepSeek)
+	ai.url = server.URL
+
+	msg, err := ai.CommitMessage("#5", "diff", "", "")
+
+	require.NoError(t, err, "Expected the retried request to succeed")
+	assert.Equal(t, "feat(#5): retry", msg)
+}
@@ -3,14 +3,33 @@ package aidy
 type Aidy interface {
 	Release(interval, repo string, notes bool) error
 	PrintConfig() error
-	Commit(issue bool) error
+	Commit(issue bool, review bool, staged bool, paths []string) error
+	Split(issue bool) error
 	Squash(issue bool)
-	PullRequest(fixes bool, target string, duplicate bool, source string) error
-	MergeRequest(fixes bool, target string, duplicate bool, source string) error
+	PullRequest(opts PullRequestOptions) error
+	MergeRequest(fixes bool, target string, duplicate bool, source string, template string) error
 	Issue(task string) error
 	Heal() error
-	Append()
-	Clean()
-	Diff() error
+	Append(staged bool)
+	Clean(responses bool, summaries bool, expired bool, model string) error
+	Diff(mode string) error
 	StartIssue(number string) error
 }
+
+// PullRequestOptions tell how to open a pull request.
+// Target is the branch to merge into, the base branch if empty.
+// With Duplicate, the title and the body are taken from the pull request of the Source branch,
+// the current one if empty, instead of being generated.
+// Template names the pull request template of the repository to fill in.
+// Reviewers are requested besides the code owners of the changes.
+type PullRequestOptions struct {
+	Fixes     bool
+	Target    string
+	Duplicate bool
+	Source    string
+	Template  string
+	Draft     bool
+	Reviewers []string
+	Assignees []string
+	Milestone string
+}
@@ -0,0 +1,38 @@
+package aidy
+
+import (
+	"fmt"
+
+	"github.com/volodya-lombrozo/aidy/internal/git"
+)
+
+// historyShare is how many times the diff budget is larger than the one of the commit messages of a branch.
+const historyShare = 8
+
+// branchChanges returns the changes and the commit messages of the branch since it forked from the target,
+// or from the base branch when there is no target.
+// The messages take at most a historyShare of the diff budget and the diff gets the rest,
+// so together they fit the prompt. The messages are empty when they can't be read.
+func (r *real) branchChanges(target string) (string, string, error) {
+	if target == "" {
+		var err error
+		if target, err = r.baseBranch(); err != nil {
+			return "", "", fmt.Errorf("error determining base branch: %v", err)
+		}
+	}
+	budget := r.budget()
+	commits, err := git.History(r.git, target, git.Budget{Tokens: budget.Tokens / historyShare, Tokenizer: budget.Tokenizer})
+	if err != nil {
+		r.logger.Warn("commits of the branch are not passed to AI because of %v", err)
+		commits = ""
+	}
+	mode := r.settings.mode
+	diff, err := git.DiffWith(r.git, target, mode, budget.Spend(commits))
+	if err != nil {
+		return "", "", fmt.Errorf("error getting git diff: %v", err)
+	}
+	if diff, err = r.digest(diff, mode.Args(git.MergeBase(r.git, target))...); err != nil {
+		return "", "", err
+	}
+	return diff, commits, nil
+}
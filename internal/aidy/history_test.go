@@ -0,0 +1,40 @@
+package aidy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volodya-lombrozo/aidy/internal/ai"
+	"github.com/volodya-lombrozo/aidy/internal/git"
+	"github.com/volodya-lombrozo/aidy/internal/log"
+)
+
+func TestReal_BranchChanges_ShareBudgetWithCommits(t *testing.T) {
+	small := git.Budget{Tokens: 120}
+	raw := large("main.go", 16)
+	outputs := map[string]string{
+		"merge-base HEAD main":                        "abc123",
+		"diff abc123 HEAD":                            raw,
+		"diff --name-status abc123 HEAD":              "M\tmain.go",
+		"diff --no-renames abc123 HEAD":               raw,
+		"diff --no-renames --name-status abc123 HEAD": "M\tmain.go",
+	}
+	brain := &summarizer{AI: ai.NewMockAI()}
+	alone := &real{git: &scripted{Git: git.NewMock(), outputs: outputs}, ai: brain, logger: log.NewMock(), settings: settings{budget: small}}
+
+	_, commits, err := alone.branchChanges("")
+
+	require.NoError(t, err, "expected no error for a branch without commits")
+	assert.Empty(t, commits)
+	assert.Empty(t, brain.asked, "Expected the diff to fit the whole budget")
+
+	outputs["log --reverse --no-merges --format=%B%x00 abc123..HEAD"] = "feat: add parser\n\nThe old one was too slow, so it's replaced.\n\x00\nfix: handle empty input\n\x00\n"
+	shared := &real{git: &scripted{Git: git.NewMock(), outputs: outputs}, ai: brain, logger: log.NewMock(), settings: settings{budget: small}}
+
+	_, commits, err = shared.branchChanges("")
+
+	require.NoError(t, err, "expected no error for a branch with commits")
+	assert.Equal(t, "- feat: add parser\n- fix: handle empty input", commits, "Expected only the subjects to fit their share")
+	assert.NotEmpty(t, brain.asked, "Expected the diff to be summarized in what the commits left")
+}
@@ -0,0 +1,102 @@
+package aidy
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+
+	"github.com/volodya-lombrozo/aidy/internal/git"
+)
+
+// defaultWorkers is the number of parts of a large diff summarized at the same time.
+const defaultWorkers = 4
+
+// digest returns the rendered diff as it is when it fits the context window of the model.
+// Otherwise, the changes are split into parts, AI summarizes each of them,
+// and the summaries are returned instead of the diff.
+// args select the changes as for 'git diff', e.g. '--cached' and some paths.
+func (r *real) digest(diff string, args ...string) (string, error) {
+	if !git.Truncated(diff) {
+		return diff, nil
+	}
+	changes, err := r.changes(args...)
+	if err != nil {
+		return "", err
+	}
+	chunks := r.budget().Chunks(changes)
+	r.logger.Info("the diff is too large, summarizing it in %d parts...", len(chunks))
+	summaries, err := r.summarize(chunks)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("The diff is too large to show, these are the summaries of its parts:\n\n%s", strings.Join(summaries, "\n\n")), nil
+}
+
+// summarize asks AI to summarize the chunks, a few of them at a time.
+// Summaries are cached by the hash of their chunks, so unchanged parts aren't summarized again.
+func (r *real) summarize(chunks []string) ([]string, error) {
+	res := make([]string, len(chunks))
+	var todo []int
+	for i, chunk := range chunks {
+		if summary, ok := r.remembered(chunk); ok {
+			res[i] = summary
+		} else {
+			todo = append(todo, i)
+		}
+	}
+	r.logger.Debug("%d of %d parts of the diff are summarized already", len(chunks)-len(todo), len(chunks))
+	workers := r.workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+	errs := make([]error, len(chunks))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, len(todo)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if res[i], errs[i] = r.ai.DiffSummary(chunks[i]); errs[i] != nil {
+					errs[i] = fmt.Errorf("part %d: %v", i+1, errs[i])
+				}
+			}
+		}()
+	}
+	for _, i := range todo {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("error summarizing the diff: %v", err)
+	}
+	for _, i := range todo {
+		r.remember(chunks[i], res[i])
+	}
+	return res, nil
+}
+
+func (r *real) remembered(chunk string) (string, bool) {
+	if r.summaries == nil {
+		return "", false
+	}
+	return r.summaries.Get(fingerprint(chunk))
+}
+
+func (r *real) remember(chunk, summary string) {
+	if r.summaries == nil {
+		return
+	}
+	if err := r.summaries.Set(fingerprint(chunk), summary); err != nil {
+		r.logger.Warn("failed to cache the summary of a part of the diff: %v", err)
+	}
+}
+
+func fingerprint(chunk string) string {
+	hash := fnv.New64a()
+	hash.Write([]byte(chunk))
+	return fmt.Sprintf("%x", hash.Sum64())
+}
@@ -0,0 +1,94 @@
+package aidy
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volodya-lombrozo/aidy/internal/ai"
+	"github.com/volodya-lombrozo/aidy/internal/cache"
+	"github.com/volodya-lombrozo/aidy/internal/git"
+	"github.com/volodya-lombrozo/aidy/internal/log"
+)
+
+func TestReal_Digest_KeepsDiffThatFits(t *testing.T) {
+	brain := &summarizer{AI: ai.NewMockAI()}
+	repo := &scripted{Git: git.NewMock()}
+	raidy := &real{git: repo, ai: brain, logger: log.NewMock()}
+	diff := git.DefaultBudget().Render(large("main.go", 1))
+
+	digested, err := raidy.digest(diff)
+
+	require.NoError(t, err, "expected no error for a small diff")
+	assert.Equal(t, diff, digested, "Expected the diff to be kept")
+	assert.Empty(t, brain.asked, "Expected AI not to be asked")
+	assert.Empty(t, repo.commands, "Expected no more git commands")
+}
+
+func TestReal_Digest_SummarizesPartsOfLargeDiff(t *testing.T) {
+	small := git.Budget{Tokens: 400}
+	raw := large("cmd/root.go", 40) + "\n" + large("internal/ai/ai.go", 40) + "\n" + large("internal/git/git.go", 40)
+	repo := &scripted{Git: git.NewMock(), outputs: map[string]string{
+		"diff --no-renames --cached main":               raw,
+		"diff --no-renames --name-status --cached main": "M\tcmd/root.go\nM\tinternal/ai/ai.go\nM\tinternal/git/git.go",
+	}}
+	brain := &summarizer{AI: ai.NewMockAI()}
+	summaries, err := cache.NewFileCache(filepath.Join(t.TempDir(), "summaries.js"))
+	require.NoError(t, err, "failed to create cache")
+	raidy := &real{git: repo, ai: brain, logger: log.NewMock(), summaries: summaries, workers: 2, settings: settings{budget: small}}
+
+	digested, err := raidy.digest(small.Render(raw), "--cached", "main")
+
+	require.NoError(t, err, "expected no error for a large diff")
+	assert.Len(t, brain.asked, 3, "Expected each directory to be summarized")
+	assert.Contains(t, digested, "The diff is too large to show")
+	assert.Contains(t, digested, "- changed files: cmd/root.go\n\n- changed files: internal/ai/ai.go\n\n- changed files: internal/git/git.go", "Expected summaries in the order of the files")
+
+	again, err := raidy.digest(small.Render(raw), "--cached", "main")
+
+	require.NoError(t, err, "expected no error for the same diff")
+	assert.Len(t, brain.asked, 3, "Expected cached summaries to be used")
+	assert.Equal(t, digested, again)
+}
+
+func TestReal_Digest_FailsWhenPartCantBeSummarized(t *testing.T) {
+	small := git.Budget{Tokens: 400}
+	raw := large("cmd/root.go", 40) + "\n" + large("internal/ai/ai.go", 40)
+	repo := &scripted{Git: git.NewMock(), outputs: map[string]string{
+		"diff --no-renames --cached":               raw,
+		"diff --no-renames --name-status --cached": "M\tcmd/root.go\nM\tinternal/ai/ai.go",
+	}}
+	raidy := &real{git: repo, ai: ai.NewFailedMockAI(), logger: log.NewMock(), settings: settings{budget: small}}
+
+	_, err := raidy.digest(small.Render(raw), "--cached")
+
+	require.Error(t, err, "expected an error when AI fails")
+	assert.Contains(t, err.Error(), "error summarizing the diff")
+}
+
+type summarizer struct {
+	ai.AI
+	mu    sync.Mutex
+	asked []string
+}
+
+func (s *summarizer) DiffSummary(diff string) (string, error) {
+	s.mu.Lock()
+	s.asked = append(s.asked, diff)
+	s.mu.Unlock()
+	return s.AI.DiffSummary(diff)
+}
+
+// large builds a diff of the file that adds the given number of lines.
+func large(name string, lines int) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n@@ -1,%d +1,%d @@", name, name, name, name, lines, lines))
+	for i := 0; i < lines; i++ {
+		sb.WriteString(fmt.Sprintf("\n+line %d of %s", i, name))
+	}
+	return sb.String()
+}
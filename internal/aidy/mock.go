@@ -3,6 +3,7 @@ package aidy
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 type Mock struct {
@@ -23,8 +24,22 @@ func (m *Mock) PrintConfig() error {
 	return nil
 }
 
-func (m *Mock) Commit(issue bool) error {
+func (m *Mock) Commit(issue bool, review bool, staged bool, paths []string) error {
 	m.logs = append(m.logs, "Commit called")
+	if review {
+		m.logs = append(m.logs, "Commit review requested")
+	}
+	if staged {
+		m.logs = append(m.logs, "Commit of staged changes requested")
+	}
+	if len(paths) > 0 {
+		m.logs = append(m.logs, fmt.Sprintf("Commit of paths requested: %s", strings.Join(paths, ", ")))
+	}
+	return nil
+}
+
+func (m *Mock) Split(issue bool) error {
+	m.logs = append(m.logs, "Split called")
 	return nil
 }
 
@@ -32,12 +47,12 @@ func (m *Mock) Squash(issue bool) {
 	m.logs = append(m.logs, "Squash called")
 }
 
-func (m *Mock) PullRequest(fixes bool, target string, duplicate bool, source string) error {
+func (m *Mock) PullRequest(opts PullRequestOptions) error {
 	m.logs = append(m.logs, "PullRequest called")
 	return nil
 }
 
-func (m *Mock) MergeRequest(fixes bool, target string, duplicate bool, source string) error {
+func (m *Mock) MergeRequest(fixes bool, target string, duplicate bool, source string, template string) error {
 	m.logs = append(m.logs, "MergeRequest called")
 	return nil
 }
@@ -52,15 +67,16 @@ func (m *Mock) Heal() error {
 	return nil
 }
 
-func (m *Mock) Append() {
+func (m *Mock) Append(staged bool) {
 	m.logs = append(m.logs, "Append called")
 }
 
-func (m *Mock) Clean() {
+func (m *Mock) Clean(responses bool, summaries bool, expired bool, model string) error {
 	m.logs = append(m.logs, "Clean called")
+	return nil
 }
 
-func (m *Mock) Diff() error {
+func (m *Mock) Diff(mode string) error {
 	m.logs = append(m.logs, "Diff called")
 	return nil
 }
@@ -83,18 +99,23 @@ func NewFailingMock() *FailingMock {
 func (f *FailingMock) Release(interval string, repo string, notes bool) error {
 	return errors.New("error")
 }
-func (f *FailingMock) PrintConfig() error      { return errors.New("error") }
-func (f *FailingMock) Commit(issue bool) error { return errors.New("error") }
-func (f *FailingMock) Squash(issue bool)       {}
-func (f *FailingMock) PullRequest(fixes bool, target string, duplicate bool, source string) error {
+func (f *FailingMock) PrintConfig() error { return errors.New("error") }
+func (f *FailingMock) Commit(issue bool, review bool, staged bool, paths []string) error {
+	return errors.New("error")
+}
+func (f *FailingMock) Squash(issue bool) {}
+func (f *FailingMock) PullRequest(opts PullRequestOptions) error {
+	return errors.New("error")
+}
+func (f *FailingMock) MergeRequest(fixes bool, target string, duplicate bool, source string, template string) error {
 	return errors.New("error")
 }
-func (f *FailingMock) MergeRequest(fixes bool, target string, duplicate bool, source string) error {
+func (f *FailingMock) Clean(responses bool, summaries bool, expired bool, model string) error {
 	return errors.New("error")
 }
 func (f *FailingMock) Issue(task string) error        { return errors.New("error") }
+func (f *FailingMock) Split(issue bool) error         { return errors.New("error") }
 func (f *FailingMock) Heal() error                    { return errors.New("error") }
-func (f *FailingMock) Append()                        {}
-func (f *FailingMock) Clean()                         {}
-func (f *FailingMock) Diff() error                    { return errors.New("error") }
+func (f *FailingMock) Append(staged bool)             {}
+func (f *FailingMock) Diff(mode string) error         { return errors.New("error") }
 func (f *FailingMock) StartIssue(number string) error { return errors.New("error") }
@@ -23,7 +23,7 @@ func TestMockAidy_PrintConfig(t *testing.T) {
 
 func TestMockAidy_Commit(t *testing.T) {
 	aidy := NewMock()
-	err := aidy.Commit(true)
+	err := aidy.Commit(true, false, false, nil)
 	require.NoError(t, err)
 	assert.Contains(t, aidy.Logs(), "Commit called")
 }
@@ -36,7 +36,7 @@ func TestMockAidy_Squash(t *testing.T) {
 
 func TestMockAidy_PullRequest(t *testing.T) {
 	aidy := NewMock()
-	err := aidy.PullRequest(true, "main", false, "")
+	err := aidy.PullRequest(PullRequestOptions{Fixes: true, Target: "main"})
 	require.NoError(t, err)
 	assert.Contains(t, aidy.Logs(), "PullRequest called")
 }
@@ -57,19 +57,19 @@ func TestMockAidy_Heal(t *testing.T) {
 
 func TestMockAidy_Append(t *testing.T) {
 	aidy := NewMock()
-	aidy.Append()
+	aidy.Append(false)
 	assert.Contains(t, aidy.Logs(), "Append called")
 }
 
 func TestMockAidy_Clean(t *testing.T) {
 	aidy := NewMock()
-	aidy.Clean()
+	_ = aidy.Clean(false, false, false, "")
 	assert.Contains(t, aidy.Logs(), "Clean called")
 }
 
 func TestMockAidy_Diff(t *testing.T) {
 	aidy := NewMock()
-	err := aidy.Diff()
+	err := aidy.Diff("")
 	assert.NoError(t, err)
 	assert.Contains(t, aidy.Logs(), "Diff called")
 }
@@ -0,0 +1,146 @@
+package aidy
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/volodya-lombrozo/aidy/internal/git"
+)
+
+// planned is a commit of a split plan: a message and the changes to commit,
+// either whole files, like 'main.go', or their hunks, like 'main.go#2'.
+type planned struct {
+	message string
+	entries []string
+}
+
+// parsePlan reads a plan of commits, where each commit is its message followed
+// by its changes indented with spaces, and commits are separated by empty lines.
+func parsePlan(text string) ([]planned, error) {
+	var plan []planned
+	var current *planned
+	for n, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "```"):
+			current = nil
+		case line[0] == ' ' || line[0] == '\t':
+			if current == nil {
+				return nil, fmt.Errorf("line %d: '%s' doesn't belong to any commit", n+1, trimmed)
+			}
+			current.entries = append(current.entries, strings.TrimPrefix(trimmed, "- "))
+		case current == nil || len(current.entries) == 0:
+			if current == nil {
+				plan = append(plan, planned{})
+				current = &plan[len(plan)-1]
+			}
+			current.message = strings.TrimSpace(current.message + "\n" + trimmed)
+		default:
+			return nil, fmt.Errorf("line %d: changes of commit '%s' must be followed by an empty line", n+1, current.message)
+		}
+	}
+	for _, commit := range plan {
+		if len(commit.entries) == 0 {
+			return nil, fmt.Errorf("commit '%s' has no changes", commit.message)
+		}
+	}
+	if len(plan) == 0 {
+		return nil, fmt.Errorf("the plan has no commits")
+	}
+	return plan, nil
+}
+
+// formatPlan writes the plan in the form parsePlan reads.
+func formatPlan(plan []planned) string {
+	blocks := make([]string, 0, len(plan))
+	for _, commit := range plan {
+		lines := []string{commit.message}
+		for _, entry := range commit.entries {
+			lines = append(lines, "  "+entry)
+		}
+		blocks = append(blocks, strings.Join(lines, "\n"))
+	}
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+// selection is what a planned commit takes from a file:
+// the whole file or some of its hunks.
+type selection struct {
+	change git.Change
+	whole  bool
+	hunks  []int
+}
+
+// resolve checks the plan against the changes and returns what each commit takes from each file,
+// and the files that no commit takes anything from.
+func resolve(plan []planned, changes []git.Change) ([][]selection, []string, error) {
+	byName := make(map[string]git.Change, len(changes))
+	for _, change := range changes {
+		byName[change.Name] = change
+	}
+	taken := make(map[string]bool)
+	res := make([][]selection, 0, len(plan))
+	for _, commit := range plan {
+		var selections []selection
+		index := make(map[string]int)
+		for _, entry := range commit.entries {
+			name, hunk, partial := strings.Cut(entry, "#")
+			change, ok := byName[name]
+			if !ok {
+				return nil, nil, fmt.Errorf("commit '%s' refers to '%s', which has no changes", commit.message, name)
+			}
+			key := name
+			n := 0
+			if partial {
+				var err error
+				if n, err = strconv.Atoi(hunk); err != nil || n < 1 || n > len(change.Hunks()) {
+					return nil, nil, fmt.Errorf("commit '%s' refers to hunk '%s' of '%s', which has %d hunks", commit.message, hunk, name, len(change.Hunks()))
+				}
+				key = fmt.Sprintf("%s#%d", name, n)
+			}
+			if taken[key] || (partial && taken[name]) || (!partial && anyHunk(taken, name)) {
+				return nil, nil, fmt.Errorf("'%s' is planned for more than one commit", entry)
+			}
+			taken[key] = true
+			i, ok := index[name]
+			if !ok {
+				selections = append(selections, selection{change: change})
+				i = len(selections) - 1
+				index[name] = i
+			}
+			if partial {
+				selections[i].hunks = append(selections[i].hunks, n)
+			} else {
+				selections[i].whole = true
+			}
+		}
+		for i := range selections {
+			sort.Ints(selections[i].hunks)
+		}
+		res = append(res, selections)
+	}
+	var left []string
+	for _, change := range changes {
+		if !taken[change.Name] && len(change.Hunks()) == 0 {
+			left = append(left, change.Name)
+			continue
+		}
+		for i := range change.Hunks() {
+			if !taken[change.Name] && !taken[fmt.Sprintf("%s#%d", change.Name, i+1)] {
+				left = append(left, fmt.Sprintf("%s#%d", change.Name, i+1))
+			}
+		}
+	}
+	return res, left, nil
+}
+
+func anyHunk(taken map[string]bool, name string) bool {
+	for key := range taken {
+		if strings.HasPrefix(key, name+"#") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package aidy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volodya-lombrozo/aidy/internal/git"
+)
+
+func TestParsePlan(t *testing.T) {
+	plan, err := parsePlan("```\nfeat(#1): add cache\n  cache.go\n  - main.go#2\n\n\nfix(#1): fix typo\n\tREADME.md\n```\n")
+
+	require.NoError(t, err, "Expected the plan to be parsed")
+	assert.Equal(t, []planned{
+		{message: "feat(#1): add cache", entries: []string{"cache.go", "main.go#2"}},
+		{message: "fix(#1): fix typo", entries: []string{"README.md"}},
+	}, plan)
+	assert.Equal(t, "feat(#1): add cache\n  cache.go\n  main.go#2\n\nfix(#1): fix typo\n  README.md\n", formatPlan(plan))
+}
+
+func TestParsePlan_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		plan  string
+		error string
+	}{
+		{"empty", "\n\n", "the plan has no commits"},
+		{"no changes", "feat: add cache\n\nfix: typo\n  README.md", "commit 'feat: add cache' has no changes"},
+		{"orphan file", "  README.md", "line 1: 'README.md' doesn't belong to any commit"},
+		{"no separator", "feat: add cache\n  cache.go\nfix: typo\n  README.md", "line 3: changes of commit 'feat: add cache' must be followed by an empty line"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parsePlan(test.plan)
+
+			require.Error(t, err, "Expected the plan to be rejected")
+			assert.Contains(t, err.Error(), test.error)
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	changes := []git.Change{
+		{Name: "cache.go", Status: "A", DiffBlock: "diff --git a/cache.go b/cache.go\n@@ -0,0 +1 @@\n+package cache"},
+		{Name: "main.go", Status: "M", DiffBlock: "diff --git a/main.go b/main.go\n@@ -1 +1 @@\n-a\n+b\n@@ -9 +9 @@\n-c\n+d"},
+		{Name: "logo.png", Status: "A"},
+	}
+	plan := []planned{
+		{message: "feat: add cache", entries: []string{"cache.go", "main.go#2"}},
+		{message: "fix: fix main", entries: []string{"main.go#1"}},
+	}
+
+	selections, left, err := resolve(plan, changes)
+
+	require.NoError(t, err, "Expected the plan to be resolved")
+	require.Len(t, selections, 2)
+	assert.True(t, selections[0][0].whole, "Expected the whole file to be taken")
+	assert.Equal(t, []int{2}, selections[0][1].hunks)
+	assert.Equal(t, []int{1}, selections[1][0].hunks)
+	assert.Equal(t, []string{"logo.png"}, left, "Expected unplanned files to be reported")
+}
+
+func TestResolve_Invalid(t *testing.T) {
+	changes := []git.Change{
+		{Name: "main.go", Status: "M", DiffBlock: "diff --git a/main.go b/main.go\n@@ -1 +1 @@\n-a\n+b"},
+	}
+	tests := []struct {
+		name    string
+		entries []string
+		error   string
+	}{
+		{"unknown file", []string{"cache.go"}, "refers to 'cache.go', which has no changes"},
+		{"unknown hunk", []string{"main.go#2"}, "refers to hunk '2' of 'main.go', which has 1 hunks"},
+		{"twice", []string{"main.go", "main.go#1"}, "'main.go#1' is planned for more than one commit"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := resolve([]planned{{message: "feat: x", entries: test.entries}}, changes)
+
+			require.Error(t, err, "Expected the plan to be rejected")
+			assert.Contains(t, err.Error(), test.error)
+		})
+	}
+}
@@ -1,25 +1,32 @@
 package aidy
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"hash/fnv"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	msemver "github.com/Masterminds/semver/v3"
 	"github.com/volodya-lombrozo/aidy/internal/ai"
 	"github.com/volodya-lombrozo/aidy/internal/cache"
 	"github.com/volodya-lombrozo/aidy/internal/config"
+	"github.com/volodya-lombrozo/aidy/internal/convention"
 	"github.com/volodya-lombrozo/aidy/internal/executor"
 	"github.com/volodya-lombrozo/aidy/internal/git"
 	"github.com/volodya-lombrozo/aidy/internal/github"
 	"github.com/volodya-lombrozo/aidy/internal/gitlab"
+	"github.com/volodya-lombrozo/aidy/internal/jira"
 	"github.com/volodya-lombrozo/aidy/internal/log"
 	"github.com/volodya-lombrozo/aidy/internal/output"
+	"github.com/volodya-lombrozo/aidy/internal/tokens"
+	"github.com/volodya-lombrozo/aidy/internal/tracker"
 	"golang.org/x/mod/semver"
 )
 
@@ -27,26 +34,58 @@ type real struct {
 	git        git.Git
 	github     github.Github
 	gitlab     gitlab.Gitlab
+	tracker    tracker.Tracker
 	ai         ai.AI
-	editor     output.Output
+	editor     output.Submitter
 	config     config.Config
 	cache      cache.AidyCache
 	printer    output.Output
 	texteditor output.TextEditor
 	logger     log.Logger
 	in         *os.File
+	convention convention.Convention
+	review     bool
+	staged     bool
+	summaries  cache.Cache
+	workers    int
+	ttl        time.Duration
+	settings   settings
 }
 
+// settings tell which changes of a branch are sent to AI and how they are rendered.
+type settings struct {
+	// base is the branch changes are compared with, e.g. the one given with '--base'.
+	// Empty means it's found in the repository.
+	base string
+	// mode selects the changes of the branch.
+	mode git.Mode
+	// budget limits how much of a diff is sent to AI, the default one if it's empty.
+	budget git.Budget
+}
+
+// responsesPath is where the answers of AI are cached, relative to the repository root.
+const responsesPath = ".aidy/responses.js"
+
+// summariesPath is where the summaries of large diffs are cached, relative to the repository root.
+const summariesPath = ".aidy/summaries.js"
+
+// cachePath is where the target remote and the project summary are cached, relative to the repository root.
+const cachePath = ".aidy/cache.js"
+
 // Create a real aidy instance
 // This function initializes the aidy instance with the provided parameters.
 // Parameters:
+// - ctx: context that cancels in-flight AI requests
 // - summary: whether to use a project summary in AI requests
 // - aider: whether to use the aider configuration
 // - ailess: whether to use AI or not
 // - silent: whether to suppress output
 // - debug: whether to enable debug logging
 // - language: language for AI-generated text (e.g. "en", "fr", "de"); defaults to "en"
-func NewAidy(summary bool, aider bool, ailess bool, silent bool, debug bool, language string) Aidy {
+// - stream: whether to print AI answers to the terminal as they arrive
+// - fresh: whether to ask AI again instead of using its cached answers
+// - base: the branch to compare changes with; found in the configuration or the repository if empty
+func NewAidy(ctx context.Context, summary bool, aider bool, ailess bool, silent bool, debug bool, language string, stream bool, fresh bool, base string) Aidy {
 	var aidy real
 	aidy.in = os.Stdin
 	InitLogger(silent, debug)
@@ -64,7 +103,7 @@ func NewAidy(summary bool, aider bool, ailess bool, silent bool, debug bool, lan
 		aidy.logger.Error("git is not installed or not found: %v", err)
 		os.Exit(1)
 	}
-	if aidy.cache, err = NewCache(aidy.git, ".aidy/cache.js"); err != nil {
+	if aidy.cache, err = NewCache(aidy.git, cachePath); err != nil {
 		aidy.logger.Error("failed to initialize cache: %v", err)
 		os.Exit(1)
 	}
@@ -72,7 +111,59 @@ func NewAidy(summary bool, aider bool, ailess bool, silent bool, debug bool, lan
 		aidy.logger.Error("failed to initialize configuration: %v", err)
 		os.Exit(1)
 	}
-	if aidy.ai, err = Brain(ailess, summary, aidy.config, language); err != nil {
+	if aidy.convention, err = aidy.config.Convention(); err != nil {
+		aidy.logger.Error("failed to read commit convention: %v", err)
+		os.Exit(1)
+	}
+	if aidy.review, err = aidy.config.Review(); err != nil {
+		aidy.logger.Error("failed to read review setting: %v", err)
+		os.Exit(1)
+	}
+	if aidy.staged, err = aidy.config.Staged(); err != nil {
+		aidy.logger.Error("failed to read staged setting: %v", err)
+		os.Exit(1)
+	}
+	if aidy.workers, err = aidy.config.Workers(); err != nil {
+		aidy.logger.Error("failed to read workers setting: %v", err)
+		os.Exit(1)
+	}
+	if stream {
+		aidy.workers = 1
+	}
+	if aidy.summaries, err = cache.NewGitCache(summariesPath, aidy.git); err != nil {
+		aidy.logger.Warn("failed to open the cache of diff summaries: %v", err)
+	}
+	aidy.settings.base = base
+	if aidy.settings.base == "" {
+		if aidy.settings.base, err = aidy.config.BaseBranch(); err != nil {
+			aidy.logger.Error("failed to read base-branch setting: %v", err)
+			os.Exit(1)
+		}
+	}
+	if aidy.settings.mode, err = aidy.config.DiffMode(); err != nil {
+		aidy.logger.Error("failed to read diff-mode setting: %v", err)
+		os.Exit(1)
+	}
+	if aidy.settings.budget, err = Budget(aidy.config); err != nil {
+		aidy.logger.Error("failed to set diff limits: %v", err)
+		os.Exit(1)
+	}
+	var out io.Writer
+	if stream {
+		out = os.Stdout
+	}
+	if aidy.ttl, err = aidy.config.CacheTTL(); err != nil {
+		aidy.logger.Error("failed to read cache-ttl setting: %v", err)
+		os.Exit(1)
+	}
+	var answers cache.Cache
+	if aidy.ttl > 0 {
+		if answers, err = cache.NewGitCache(responsesPath, aidy.git); err != nil {
+			aidy.logger.Warn("failed to open the cache of AI answers: %v", err)
+		}
+	}
+	prompts := NewPrompts(aidy.git).WithConvention(aidy.convention)
+	if aidy.ai, err = Brain(ctx, ailess, summary, aidy.config, language, prompts, out, answers, fresh); err != nil {
 		aidy.logger.Error("failed to initialize AI: %v", err)
 		os.Exit(1)
 	}
@@ -80,7 +171,14 @@ func NewAidy(summary bool, aider bool, ailess bool, silent bool, debug bool, lan
 		aidy.logger.Error("failed to initialize GitHub client: %v", err)
 		os.Exit(1)
 	}
-	aidy.gitlab = gitlab.NewGitlab(shell)
+	if aidy.gitlab, err = NewGitLab(aidy.git, aidy.config); err != nil {
+		aidy.logger.Error("failed to initialize GitLab client: %v", err)
+		os.Exit(1)
+	}
+	if aidy.tracker, err = NewTracker(aidy.config, aidy.github, aidy.gitlab); err != nil {
+		aidy.logger.Error("failed to initialize issue tracker: %v", err)
+		os.Exit(1)
+	}
 	if err = aidy.InitSummary(summary, "README.md"); err != nil {
 		aidy.logger.Warn("failed to initialize project summary: %v", err)
 	}
@@ -100,6 +198,9 @@ func InitLogger(silent bool, debug bool) {
 	log.Set(logger)
 }
 
+// githubRepo matches the 'owner/name' of a GitHub repository in its SSH or HTTPS address.
+var githubRepo = regexp.MustCompile(`(?:git@github\.com:|https://github\.com/)([^/]+/.+?)(?:\.git)?$`)
+
 func (r *real) SetTarget() error {
 	target := r.cache.Remote()
 	if target != "" {
@@ -112,7 +213,6 @@ func (r *real) SetTarget() error {
 		}
 		lines := strings.Split(out, "\n")
 		r.logger.Debug("found %d remote repositories:\n%s", len(lines), out)
-		re := regexp.MustCompile(`(?:git@github\.com:|https://github\.com/)([^/]+/.+?)(?:\.git)?$`)
 		unique := make(map[string]struct{})
 		for _, line := range lines {
 			fields := strings.Fields(line)
@@ -122,7 +222,7 @@ func (r *real) SetTarget() error {
 			} else {
 				address = fields[1]
 			}
-			matches := re.FindStringSubmatch(address)
+			matches := githubRepo.FindStringSubmatch(address)
 			if len(matches) == 2 {
 				unique[string(matches[1])] = struct{}{}
 			}
@@ -134,7 +234,9 @@ func (r *real) SetTarget() error {
 		sort.Strings(repos)
 		if len(repos) == 1 {
 			r.logger.Debug("found one remote repository: %s", repos[0])
-			r.cache.WithRemote(repos[0])
+			if err := r.cache.WithRemote(repos[0]); err != nil {
+				return err
+			}
 		} else if len(repos) < 1 {
 			return fmt.Errorf("no remote repositories found, please set one")
 		} else {
@@ -149,56 +251,107 @@ func (r *real) SetTarget() error {
 				return fmt.Errorf("invalid choice: %v", err)
 			}
 			repo := repos[choice-1]
-			r.cache.WithRemote(repo)
+			if err := r.cache.WithRemote(repo); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
-func (r *real) Diff() error {
-	diff, err := r.git.Diff()
+// Diff prints the changes of the branch since it forked from the base branch.
+// mode is 'committed', 'staged' or 'worktree', the one from the configuration if empty.
+func (r *real) Diff(mode string) error {
+	diff, err := r.diffIn(mode)
 	if err != nil {
 		return fmt.Errorf("failed to get diff: '%v'", err)
-	} else {
-		r.print(fmt.Sprintf("diff with the base branch:\n%s\n", diff))
-		return nil
 	}
+	r.print(fmt.Sprintf("diff with the base branch:\n%s\n", diff))
+	return nil
 }
 
-func (r *real) Commit(issue bool) error {
+func (r *real) diffIn(mode string) (string, error) {
+	parsed := r.settings.mode
+	if mode != "" {
+		var err error
+		if parsed, err = git.ParseMode(mode); err != nil {
+			return "", err
+		}
+	}
+	base, err := r.baseBranch()
+	if err != nil {
+		return "", fmt.Errorf("error determining base branch: %v", err)
+	}
+	return git.DiffWith(r.git, base, parsed, r.budget())
+}
+
+func (r *real) Commit(issue bool, review bool, staged bool, paths []string) error {
+	review = review || r.review
+	if staged && len(paths) > 0 {
+		return fmt.Errorf("staged changes can't be restricted to paths, stage the paths you need instead")
+	}
+	staged = (staged || r.staged) && len(paths) == 0
 	branch, err := r.git.CurrentBranch()
 	if err != nil {
 		return fmt.Errorf("error getting branch name: %v", err)
 	}
 	nissue := inumber(branch)
-	r.logger.Info("retrieving the description for issue #%s...", nissue)
-	var descr string
-	if issue {
-		if err = r.SetTarget(); err != nil {
-			r.logger.Warn("failed to set target repository: %v", err)
-		}
-		descr, err = r.github.Description(nissue)
-		if err != nil {
-			return fmt.Errorf("error retrieving issue description: %v", err)
+	descr, err := r.describe(issue, nissue)
+	if err != nil {
+		return err
+	}
+	var index string
+	if review {
+		if index, err = r.git.Run("write-tree"); err != nil {
+			return fmt.Errorf("error saving the index: %v", err)
 		}
-	} else {
-		descr = ""
 	}
-	_, err = r.git.Run("add", "--all")
+	diff, err := r.stage(staged, paths)
 	if err != nil {
-		return fmt.Errorf("error adding changes: %v", err)
+		return err
 	}
-	diff, diffErr := r.git.CurrentDiff()
-	if diffErr != nil {
-		return fmt.Errorf("error getting current diff: %v", diffErr)
+	selected := []string{"--cached"}
+	if len(paths) > 0 {
+		selected = append(append(selected, "--"), paths...)
+	}
+	if diff, err = r.digest(diff, selected...); err != nil {
+		return err
 	}
 	iref := issueRef(nissue)
 	r.logger.Info("generating commit message for %s...", iref)
-	msg, cerr := r.ai.CommitMessage(iref, diff, descr)
+	msg, cerr := r.commitMessage(iref, diff, descr, "", !review, false)
 	if cerr != nil {
-		return fmt.Errorf("error generating commit message: %v", cerr)
+		return cerr
+	}
+	if review {
+		msg, cerr = r.texteditor.Review(msg, func(hint string) (string, error) {
+			r.logger.Info("regenerating commit message for %s...", iref)
+			return r.commitMessage(iref, diff, descr, hint, false, true)
+		})
+		if errors.Is(cerr, output.ErrCanceled) {
+			if _, err = r.git.Run("read-tree", strings.TrimSpace(index)); err != nil {
+				return fmt.Errorf("error restoring the index: %v", err)
+			}
+			r.logger.Info("commit canceled")
+			return nil
+		}
+		if cerr != nil {
+			return fmt.Errorf("failed to review commit message: %v", cerr)
+		}
+		msg = strings.TrimSpace(msg)
 	}
-	_, err = r.git.Run("commit", "-m", msg)
+	var coauthors []string
+	if r.convention.Coauthors {
+		if coauthors, err = r.coauthors(); err != nil {
+			r.logger.Warn("failed to find co-authors: %v", err)
+		}
+	}
+	msg = r.convention.Sign(msg, iref, coauthors...)
+	args := []string{"commit", "-m", msg}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+	_, err = r.git.Run(args...)
 	if err != nil {
 		return fmt.Errorf("error committing changes: %v", err)
 	}
@@ -206,6 +359,292 @@ func (r *real) Commit(issue bool) error {
 	return r.Heal()
 }
 
+// describe returns the description of the issue, if it's requested.
+func (r *real) describe(issue bool, nissue string) (string, error) {
+	r.logger.Info("retrieving the description for issue #%s...", nissue)
+	if !issue {
+		return "", nil
+	}
+	if err := r.SetTarget(); err != nil {
+		r.logger.Warn("failed to set target repository: %v", err)
+	}
+	descr, err := r.issues(r.github).Description(nissue)
+	if err != nil {
+		return "", fmt.Errorf("error retrieving issue description: %v", err)
+	}
+	return descr, nil
+}
+
+// Split commits all changes as several commits planned by AI.
+// The plan is shown for review before anything is committed.
+func (r *real) Split(issue bool) error {
+	branch, err := r.git.CurrentBranch()
+	if err != nil {
+		return fmt.Errorf("error getting branch name: %v", err)
+	}
+	nissue := inumber(branch)
+	descr, err := r.describe(issue, nissue)
+	if err != nil {
+		return err
+	}
+	index, err := r.git.Run("write-tree")
+	if err != nil {
+		return fmt.Errorf("error saving the index: %v", err)
+	}
+	restore := func() error {
+		if _, err := r.git.Run("read-tree", strings.TrimSpace(index)); err != nil {
+			return fmt.Errorf("error restoring the index: %v", err)
+		}
+		return nil
+	}
+	if _, err = r.git.Run("add", "--all"); err != nil {
+		return fmt.Errorf("error adding changes: %v", err)
+	}
+	changes, err := r.changes("--cached")
+	if err != nil {
+		return errors.Join(err, restore())
+	}
+	if len(changes) == 0 {
+		return errors.Join(fmt.Errorf("there are no changes to split"), restore())
+	}
+	iref := issueRef(nissue)
+	r.logger.Info("planning commits for %d changed files...", len(changes))
+	answer, err := r.ai.CommitPlan(iref, r.budget().Spend(descr).Hunks(changes), descr)
+	if err != nil {
+		return errors.Join(fmt.Errorf("error planning commits: %v", err), restore())
+	}
+	plan, err := parsePlan(answer)
+	if err != nil {
+		return errors.Join(fmt.Errorf("AI suggested an invalid plan: %v", err), restore())
+	}
+	reviewed, err := r.texteditor.Edit(formatPlan(plan))
+	if errors.Is(err, output.ErrCanceled) {
+		r.logger.Info("split canceled")
+		return restore()
+	}
+	if err != nil {
+		return errors.Join(fmt.Errorf("failed to review the plan: %v", err), restore())
+	}
+	if plan, err = parsePlan(reviewed); err != nil {
+		return errors.Join(fmt.Errorf("invalid plan: %v", err), restore())
+	}
+	selections, left, err := resolve(plan, changes)
+	if err != nil {
+		return errors.Join(fmt.Errorf("invalid plan: %v", err), restore())
+	}
+	// HEAD doesn't resolve before the first commit of the repository, the branch is removed then.
+	head, _ := r.git.Run("rev-parse", "--verify", "-q", "HEAD")
+	head = strings.TrimSpace(head)
+	created := 0
+	rollback := func() error {
+		switch {
+		case created == 0:
+		case head != "":
+			if _, err := r.git.Run("reset", "-q", "--soft", head); err != nil {
+				return fmt.Errorf("error returning to commit %s: %v", head, err)
+			}
+		default:
+			if _, err := r.git.Run("update-ref", "-d", "HEAD"); err != nil {
+				return fmt.Errorf("error removing the created commits: %v", err)
+			}
+		}
+		return restore()
+	}
+	if _, err = r.git.Run("reset", "-q"); err != nil {
+		return errors.Join(fmt.Errorf("error unstaging changes: %v", err), restore())
+	}
+	for i, commit := range plan {
+		if err = r.stageSelection(selections[i]); err != nil {
+			return errors.Join(fmt.Errorf("error staging changes for '%s': %v", commit.message, err), rollback())
+		}
+		msg := r.convention.Sign(r.convention.Heal(commit.message, iref), iref)
+		if _, err = r.git.Run("commit", "-m", msg); err != nil {
+			return errors.Join(fmt.Errorf("error committing '%s': %v", commit.message, err), rollback())
+		}
+		created++
+		r.logger.Info("commit %d/%d was created with message: '%s'", i+1, len(plan), msg)
+	}
+	if len(left) > 0 {
+		r.logger.Warn("changes that weren't planned for any commit are left uncommitted: %s", strings.Join(left, ", "))
+	}
+	return nil
+}
+
+// changes returns the changes by file.
+// args select them as for 'git diff', e.g. '--cached' for the staged ones or a commit and some paths.
+func (r *real) changes(args ...string) ([]git.Change, error) {
+	command := func(option ...string) []string {
+		return append(append([]string{"diff", "--no-renames"}, option...), args...)
+	}
+	diff, err := r.git.Run(command()...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting staged diff: %v", err)
+	}
+	names, err := r.git.Run(command("--name-status")...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting staged files: %v", err)
+	}
+	stat, err := r.git.Run(command("--stat")...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting staged stat: %v", err)
+	}
+	return git.NewSummary(diff, stat, names).Changes(), nil
+}
+
+// stageSelection stages whole files with 'git add' and single hunks with 'git apply --cached'.
+func (r *real) stageSelection(selections []selection) error {
+	for _, sel := range selections {
+		if !sel.whole {
+			if err := r.applyCached(sel); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := r.git.Run("add", "--all", "--", sel.change.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyCached stages the selected hunks of a file through a temporary patch file.
+func (r *real) applyCached(sel selection) error {
+	patch, err := sel.change.Patch(sel.hunks...)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp("", "aidy-split-*.patch")
+	if err != nil {
+		return fmt.Errorf("error creating patch file: %v", err)
+	}
+	defer func() {
+		if err := os.Remove(tmp.Name()); err != nil {
+			r.logger.Warn("failed to remove temp file '%s': %v", tmp.Name(), err)
+		}
+	}()
+	if _, err = tmp.WriteString(patch); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("error writing patch file: %v", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("error closing patch file: %v", err)
+	}
+	_, err = r.git.Run("apply", "--cached", tmp.Name())
+	return err
+}
+
+// stage prepares the changes to commit and returns their diff.
+// All changes are staged, unless only the staged ones are committed;
+// paths restrict the changes to the given pathspecs.
+func (r *real) stage(staged bool, paths []string) (string, error) {
+	if !staged {
+		args := []string{"add", "--all"}
+		if len(paths) > 0 {
+			args = append(append(args, "--"), paths...)
+		}
+		if _, err := r.git.Run(args...); err != nil {
+			return "", fmt.Errorf("error adding changes: %v", err)
+		}
+	}
+	args := []string{"diff", "--cached"}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+	diff, err := r.git.Run(args...)
+	if err != nil {
+		return "", fmt.Errorf("error getting staged diff: %v", err)
+	}
+	if (staged || len(paths) > 0) && strings.TrimSpace(diff) == "" {
+		return "", fmt.Errorf("there are no staged changes to commit")
+	}
+	return r.budget().Render(diff), nil
+}
+
+// baseBranch returns the branch changes are compared with, the configured one or the one found in the repository.
+func (r *real) baseBranch() (string, error) {
+	if r.settings.base != "" {
+		return r.settings.base, nil
+	}
+	return r.git.BaseBranch()
+}
+
+// budget returns the budget of the diffs sent to AI, the default one if it isn't set.
+func (r *real) budget() git.Budget {
+	if r.settings.budget.Tokens == 0 {
+		return git.DefaultBudget()
+	}
+	return r.settings.budget
+}
+
+// commitMessage asks AI for a commit message that follows the convention, taking the user's hint into account.
+// When the message breaks the rules, AI is asked again with the violations
+// and if it still can't get it right, the user edits the message, if edit is set.
+// A fresh message is generated again instead of being taken from the cache.
+func (r *real) commitMessage(iref, diff, descr, hint string, edit, fresh bool) (string, error) {
+	brain := r.ai
+	if fresh {
+		brain = ai.Fresh(r.ai)
+	}
+	var msg string
+	var violations []string
+	for attempt := 0; attempt <= r.convention.Repairs(); attempt++ {
+		remarks := hint
+		if attempt > 0 {
+			r.logger.Warn("commit message '%s' breaks the convention, asking AI to fix it (%d/%d)", msg, attempt, r.convention.Repairs())
+			remarks = strings.TrimSpace(fmt.Sprintf("%s\nThe previous commit message '%s' was rejected because:\n- %s", hint, msg, strings.Join(violations, "\n- ")))
+		}
+		generated, err := brain.CommitMessage(iref, diff, descr, remarks)
+		if err != nil {
+			return "", fmt.Errorf("error generating commit message: %v", err)
+		}
+		msg = convention.Parse(r.convention.Heal(strings.TrimSpace(generated), iref), r.convention.Trailer).Wrap().String()
+		violations = r.convention.Lint(msg)
+		if len(violations) == 0 {
+			return msg, nil
+		}
+	}
+	r.logger.Warn("commit message still breaks the convention:\n- %s", strings.Join(violations, "\n- "))
+	if !edit {
+		return msg, nil
+	}
+	reviewed, err := r.texteditor.Edit(msg)
+	if err != nil {
+		if errors.Is(err, output.ErrCanceled) {
+			return "", fmt.Errorf("commit was canceled")
+		}
+		return "", fmt.Errorf("failed to review commit message: %v", err)
+	}
+	return strings.TrimSpace(reviewed), nil
+}
+
+// coauthors returns everyone else who committed to the current branch, e.g. 'Jane Doe <jane@example.com>'.
+func (r *real) coauthors() ([]string, error) {
+	base, err := r.baseBranch()
+	if err != nil {
+		return nil, fmt.Errorf("error determining base branch: %v", err)
+	}
+	out, err := r.git.Run("log", base+"..HEAD", "--format=%an <%ae>")
+	if err != nil {
+		return nil, fmt.Errorf("error getting commit authors: %v", err)
+	}
+	email, err := r.git.Run("config", "user.email")
+	if err != nil {
+		return nil, fmt.Errorf("error getting user email: %v", err)
+	}
+	me := "<" + strings.TrimSpace(email) + ">"
+	var res []string
+	seen := make(map[string]bool)
+	for _, author := range strings.Split(strings.TrimSpace(out), "\n") {
+		author = strings.TrimSpace(author)
+		if author == "" || strings.HasSuffix(author, me) || seen[author] {
+			continue
+		}
+		seen[author] = true
+		res = append(res, author)
+	}
+	return res, nil
+}
+
 func (r *real) Issue(task string) error {
 	if err := r.SetTarget(); err != nil {
 		r.logger.Warn("failed to set target repository: %v", err)
@@ -245,7 +684,10 @@ func (r *real) Issue(task string) error {
 		cmd = fmt.Sprintf("\n%s", escapeBackticks(fmt.Sprintf("gh issue create --title \"%s\" --body \"%s\"", healQuotes(title), healQuotes(body))))
 	}
 	cmd = fmt.Sprintf("%s%s\n", cmd, repo)
-	return r.editor.Print(cmd)
+	issue := github.Issue{Repo: remote, Title: healQuotes(title), Body: healQuotes(body), Labels: suitable}
+	return r.editor.Submit(cmd, func() (string, error) {
+		return r.github.CreateIssue(issue)
+	})
 }
 
 func escapeBackticks(input string) string {
@@ -280,17 +722,9 @@ func healQuote(open rune, text string) string {
 	return text
 }
 
-func healPRTitle(text string, issue string) string {
-	re := regexp.MustCompile(`(fix|feat|build|chore|ci|docs|style|refactor|perf|test)\((?:#?\d+|#?[A-Z][A-Z0-9]+-\d+)\)`)
-	replaced := re.ReplaceAllStringFunc(text, func(m string) string {
-		groups := re.FindStringSubmatch(m)
-		if len(groups) >= 2 {
-			commitType := groups[1]
-			return fmt.Sprintf("%s(%s)", commitType, issueRef(issue))
-		}
-		return m
-	})
-	return replaced
+// healPRTitle makes the title follow the commit convention, e.g. puts the right issue into the scope.
+func healPRTitle(text string, issue string, conv convention.Convention) string {
+	return conv.Heal(text, issueRef(issue))
 }
 
 func (r *real) PrintConfig() error {
@@ -319,6 +753,18 @@ func (r *real) PrintConfig() error {
 	} else {
 		r.print(fmt.Sprintf("GitHub API key: %s", mask(gh)))
 	}
+	gl, err := r.config.GitlabKey()
+	if err != nil {
+		r.print(fmt.Sprintf("error retrieving GitLab API key: %v", err))
+	} else {
+		r.print(fmt.Sprintf("GitLab API key: %s", mask(gl)))
+	}
+	url, err := r.config.GitlabUrl()
+	if err != nil {
+		r.print(fmt.Sprintf("error retrieving GitLab URL: %v", err))
+	} else {
+		r.print(fmt.Sprintf("GitLab URL: %s", url))
+	}
 	return err
 }
 
@@ -337,8 +783,8 @@ func (r *real) print(msg string) {
 	}
 }
 
-func (r *real) PullRequest(fixes bool, target string, duplicate bool, source string) error {
-	if duplicate && target == "" {
+func (r *real) PullRequest(opts PullRequestOptions) error {
+	if opts.Duplicate && opts.Target == "" {
 		return fmt.Errorf("--duplicate requires --target to specify the branch to duplicate the pull request against")
 	}
 	if err := r.SetTarget(); err != nil {
@@ -349,45 +795,74 @@ func (r *real) PullRequest(fixes bool, target string, duplicate bool, source str
 		return fmt.Errorf("error getting branch name: %v", err)
 	}
 	lookup := branch
-	if duplicate && source != "" {
-		lookup = source
+	if opts.Duplicate && opts.Source != "" {
+		lookup = opts.Source
 	}
 	nissue := inumber(lookup)
 	var title, body string
-	if duplicate {
+	if opts.Duplicate {
 		r.logger.Info("looking up the pull request for branch '%s' to duplicate...", lookup)
 		title, body, err = r.github.PullRequestByBranch(lookup)
 		if err != nil {
 			return fmt.Errorf("error finding an existing pull request to duplicate: %v", err)
 		}
 	} else {
-		diff, err := r.git.Diff()
+		diff, commits, err := r.branchChanges(opts.Target)
 		if err != nil {
-			return fmt.Errorf("error getting git diff: %v", err)
+			return err
+		}
+		form, err := r.form(githubForms, opts.Template)
+		if err != nil {
+			if opts.Template != "" {
+				return fmt.Errorf("error reading pull request template: %v", err)
+			}
+			r.logger.Warn("pull request template is ignored because of %v", err)
 		}
 		summary, _ := r.cache.Summary()
 		r.logger.Info("retrieving the description for issue #%s...", nissue)
-		issue, err := r.github.Description(nissue)
+		issue, err := r.issues(r.github).Description(nissue)
 		if err != nil {
 			issue = "not-found"
 			r.logger.Warn("issue description not found for issue #%s because of %v, using default value", nissue, err)
 		}
 		r.logger.Info("generating pull request title...")
-		title, err = r.ai.PrTitle(issueRef(nissue), diff, issue, summary)
+		title, err = r.ai.PrTitle(issueRef(nissue), diff, issue, summary, commits)
 		if err != nil {
 			return fmt.Errorf("error generating pull request title: %v", err)
 		}
-		r.logger.Info("generating pull request body...")
-		body, err = r.ai.PrBody(diff, issue, summary)
+		if form != "" {
+			r.logger.Info("filling in the pull request template...")
+		} else {
+			r.logger.Info("generating pull request body...")
+		}
+		body, err = r.ai.PrBody(diff, issue, summary, commits, form)
 		if err != nil {
 			return fmt.Errorf("error generating pull request body: %v", err)
 		}
-		if fixes {
+		if opts.Fixes {
 			body = body + fmt.Sprintf("\n\nFixes %s", issueRef(nissue))
 		} else {
 			body = body + fmt.Sprintf("\n\nRelated to %s", issueRef(nissue))
 		}
 	}
+	labels := r.labels(body, r.github.Labels)
+	reviewers := unique(opts.Reviewers, r.owners(opts.Target))
+	var extra string
+	if opts.Draft {
+		extra += " --draft"
+	}
+	if len(reviewers) > 0 {
+		extra += fmt.Sprintf(" --reviewer \"%s\"", strings.Join(reviewers, ","))
+	}
+	if len(opts.Assignees) > 0 {
+		extra += fmt.Sprintf(" --assignee \"%s\"", strings.Join(opts.Assignees, ","))
+	}
+	if opts.Milestone != "" {
+		extra += fmt.Sprintf(" --milestone \"%s\"", opts.Milestone)
+	}
+	if len(labels) > 0 {
+		extra += fmt.Sprintf(" --label \"%s\"", strings.Join(labels, ","))
+	}
 	remote := r.cache.Remote()
 	var repo string
 	if remote != "" {
@@ -396,16 +871,137 @@ func (r *real) PullRequest(fixes bool, target string, duplicate bool, source str
 		repo = ""
 	}
 	var base string
-	if target != "" {
-		base = " --base " + target
+	if opts.Target != "" {
+		base = " --base " + opts.Target
+	}
+	head := r.head(branch, remote)
+	if head != branch {
+		extra += " --head " + head
+	}
+	pr := github.PullRequest{
+		Repo:      remote,
+		Title:     healPRTitle(healQuotes(title), nissue, r.convention),
+		Body:      healQuotes(body),
+		Labels:    labels,
+		Base:      opts.Target,
+		Head:      head,
+		Draft:     opts.Draft,
+		Reviewers: reviewers,
+		Assignees: opts.Assignees,
+		Milestone: opts.Milestone,
+	}
+	cmd := escapeBackticks(fmt.Sprintf("gh pr create --title \"%s\" --body \"%s\"%s%s%s", pr.Title, pr.Body, extra, repo, base))
+	return r.editor.Submit(cmd, func() (string, error) {
+		if pr.Base == "" {
+			into, err := r.baseBranch()
+			if err != nil {
+				return "", fmt.Errorf("error determining base branch: %v", err)
+			}
+			pr.Base = git.Trunk(r.git, into)
+		}
+		return r.github.CreatePullRequest(pr)
+	})
+}
+
+// head returns the branch as a pull request into repo refers to it,
+// prefixed with 'owner:' when the branch is pushed to a fork rather than to repo itself.
+// The branch is taken as pushed to the remote it tracks, or to 'origin' if it tracks none.
+func (r *real) head(branch, repo string) string {
+	remote, err := r.git.Run("config", "--get", fmt.Sprintf("branch.%s.remote", branch))
+	if remote = strings.TrimSpace(remote); err != nil || remote == "" || remote == "." {
+		remote = "origin"
+	}
+	url, err := r.git.Run("remote", "get-url", remote)
+	if err != nil {
+		return branch
 	}
-	prtitle := healPRTitle(healQuotes(title), nissue)
-	prbody := healQuotes(body)
-	cmd := escapeBackticks(fmt.Sprintf("gh pr create --title \"%s\" --body \"%s\"%s%s", prtitle, prbody, repo, base))
-	return r.editor.Print(cmd)
+	matches := githubRepo.FindStringSubmatch(strings.TrimSpace(url))
+	if repo == "" || len(matches) != 2 || strings.EqualFold(matches[1], repo) {
+		return branch
+	}
+	owner, _, _ := strings.Cut(matches[1], "/")
+	return owner + ":" + branch
 }
 
-func (r *real) MergeRequest(fixes bool, target string, duplicate bool, source string) error {
+// labels asks AI to pick the labels of the repository, as listed by available, that suit the pull or merge request.
+// They are only a suggestion, so the request is created without them if they can't be picked.
+func (r *real) labels(body string, available func() ([]string, error)) []string {
+	known, err := available()
+	if err != nil {
+		r.logger.Warn("labels are not suggested because of %v", err)
+		return nil
+	}
+	if len(known) == 0 {
+		return nil
+	}
+	r.logger.Info("choosing suitable labels...")
+	suitable, err := r.ai.IssueLabels(body, known)
+	if err != nil {
+		r.logger.Warn("labels are not suggested because of %v", err)
+		return nil
+	}
+	return suitable
+}
+
+// owners returns the reviewers the CODEOWNERS file of the repository assigns to the changed files,
+// the changes are counted since the branch forked from the target, or from the base branch when there is no target.
+// The authenticated user is left out, GitHub doesn't let the author of a pull request review it.
+func (r *real) owners(target string) []string {
+	root, err := r.git.Root()
+	if err != nil {
+		return nil
+	}
+	var content []byte
+	for _, path := range github.CodeownersPaths {
+		if content, err = os.ReadFile(filepath.Join(root, filepath.FromSlash(path))); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil
+	}
+	if target == "" {
+		if target, err = r.baseBranch(); err != nil {
+			r.logger.Warn("reviewers are not suggested because of %v", err)
+			return nil
+		}
+	}
+	files, err := git.ChangedFiles(r.git, target, r.settings.mode)
+	if err != nil {
+		r.logger.Warn("reviewers are not suggested because of %v", err)
+		return nil
+	}
+	owners := github.ParseCodeowners(string(content)).Reviewers(files)
+	me, err := r.github.User()
+	if err != nil {
+		r.logger.Warn("the author of the pull request may be among its reviewers because of %v", err)
+		return owners
+	}
+	var res []string
+	for _, owner := range owners {
+		if !strings.EqualFold(owner, me) {
+			res = append(res, owner)
+		}
+	}
+	return res
+}
+
+// unique joins the lists, keeping only the first occurrence of each item.
+func unique(lists ...[]string) []string {
+	seen := map[string]bool{}
+	var res []string
+	for _, list := range lists {
+		for _, item := range list {
+			if !seen[item] {
+				seen[item] = true
+				res = append(res, item)
+			}
+		}
+	}
+	return res
+}
+
+func (r *real) MergeRequest(fixes bool, target string, duplicate bool, source string, template string) error {
 	if duplicate && target == "" {
 		return fmt.Errorf("--duplicate requires --target to specify the branch to duplicate the merge request against")
 	}
@@ -426,18 +1022,35 @@ func (r *real) MergeRequest(fixes bool, target string, duplicate bool, source st
 			return fmt.Errorf("error finding an existing merge request to duplicate: %v", err)
 		}
 	} else {
-		diff, err := r.git.Diff()
+		diff, commits, err := r.branchChanges(target)
 		if err != nil {
-			return fmt.Errorf("error getting git diff: %v", err)
+			return err
+		}
+		form, err := r.form(gitlabForms, template)
+		if err != nil {
+			if template != "" {
+				return fmt.Errorf("error reading merge request template: %v", err)
+			}
+			r.logger.Warn("merge request template is ignored because of %v", err)
 		}
 		summary, _ := r.cache.Summary()
+		r.logger.Info("retrieving the description for issue #%s...", nissue)
+		issue, err := r.issues(r.gitlab).Description(nissue)
+		if err != nil {
+			issue = "not-found"
+			r.logger.Warn("issue description not found for issue #%s because of %v, using default value", nissue, err)
+		}
 		r.logger.Info("generating merge request title...")
-		title, err = r.ai.PrTitle(issueRef(nissue), diff, "", summary)
+		title, err = r.ai.PrTitle(issueRef(nissue), diff, issue, summary, commits)
 		if err != nil {
 			return fmt.Errorf("error generating merge request title: %v", err)
 		}
-		r.logger.Info("generating merge request body...")
-		body, err = r.ai.PrBody(diff, "", summary)
+		if form != "" {
+			r.logger.Info("filling in the merge request template...")
+		} else {
+			r.logger.Info("generating merge request body...")
+		}
+		body, err = r.ai.PrBody(diff, issue, summary, commits, form)
 		if err != nil {
 			return fmt.Errorf("error generating merge request body: %v", err)
 		}
@@ -447,14 +1060,32 @@ func (r *real) MergeRequest(fixes bool, target string, duplicate bool, source st
 			body = body + fmt.Sprintf("\n\nRelated to %s", issueRef(nissue))
 		}
 	}
-	var targetBranch string
-	if target != "" {
-		targetBranch = " --target-branch " + target
+	var extra string
+	labels := r.labels(body, r.gitlab.Labels)
+	if len(labels) > 0 {
+		extra += fmt.Sprintf(" --label \"%s\"", strings.Join(labels, ","))
 	}
-	mrtitle := healPRTitle(healQuotes(title), nissue)
-	mrbody := healQuotes(body)
-	cmd := escapeBackticks(fmt.Sprintf("glab mr create --title \"%s\" --description \"%s\"%s", mrtitle, mrbody, targetBranch))
-	return r.editor.Print(cmd)
+	if target != "" {
+		extra += " --target-branch " + target
+	}
+	mr := gitlab.MergeRequest{
+		Title:  healPRTitle(healQuotes(title), nissue, r.convention),
+		Body:   healQuotes(body),
+		Labels: labels,
+		Source: branch,
+		Target: target,
+	}
+	cmd := escapeBackticks(fmt.Sprintf("glab mr create --title \"%s\" --description \"%s\"%s", mr.Title, mr.Body, extra))
+	return r.editor.Submit(cmd, func() (string, error) {
+		if mr.Target == "" {
+			into, err := r.baseBranch()
+			if err != nil {
+				return "", fmt.Errorf("error determining base branch: %v", err)
+			}
+			mr.Target = git.Trunk(r.git, into)
+		}
+		return r.gitlab.CreateMergeRequest(mr)
+	})
 }
 
 func inumber(branch string) string {
@@ -479,6 +1110,15 @@ func inumber(branch string) string {
 	return found
 }
 
+// issues returns the issue tracker configured for the repository,
+// or fallback, the tracker of the hosting service, if none is configured.
+func (r *real) issues(fallback tracker.Tracker) tracker.Tracker {
+	if r.tracker != nil {
+		return r.tracker
+	}
+	return fallback
+}
+
 func issueRef(id string) string {
 	if regexp.MustCompile(`^\d+$`).MatchString(id) {
 		return "#" + id
@@ -486,8 +1126,13 @@ func issueRef(id string) string {
 	return id
 }
 
-func (r *real) Append() {
-	err := r.git.Append()
+func (r *real) Append(staged bool) {
+	var err error
+	if staged || r.staged {
+		_, err = r.git.Run("commit", "--amend", "--no-edit")
+	} else {
+		err = r.git.Append()
+	}
 	if err != nil {
 		r.logger.Error("error appending to commit: %v", err)
 		os.Exit(1)
@@ -495,19 +1140,73 @@ func (r *real) Append() {
 	r.logger.Info("changes were appended to the last commit")
 }
 
-func (r *real) Clean() {
-	dir, err := os.Getwd()
+// Clean clears the '.aidy' directory, or only the caches that are asked for:
+// all AI answers, the summaries of large diffs, the expired answers or the answers of a model.
+func (r *real) Clean(responses bool, summaries bool, expired bool, model string) error {
+	root, err := r.git.Root()
 	if err != nil {
-		r.logger.Error("Can't understand what is the current directory, '%v'", err)
-		os.Exit(1)
+		return fmt.Errorf("error getting repository root: %v", err)
 	}
-	cache := filepath.Join(dir, ".aidy")
-	err = os.RemoveAll(cache)
+	if !responses && !summaries && !expired && model == "" {
+		for _, path := range []string{responsesPath, summariesPath, cachePath} {
+			for _, file := range []string{path, path + ".lock"} {
+				if err = remove(filepath.Join(root, file)); err != nil {
+					return err
+				}
+			}
+		}
+		r.logger.Info("aidy cache was cleared")
+		return nil
+	}
+	if summaries {
+		if err = remove(filepath.Join(root, summariesPath)); err != nil {
+			return err
+		}
+		r.logger.Info("summaries of large diffs were cleared")
+	}
+	if responses {
+		if err = remove(filepath.Join(root, responsesPath)); err != nil {
+			return err
+		}
+		r.logger.Info("AI answers were cleared")
+	} else if expired || model != "" {
+		return r.purge(expired, model)
+	}
+	return nil
+}
+
+// purge removes the cached AI answers that are expired, belong to the model, or both when both are given.
+func (r *real) purge(expired bool, model string) error {
+	answers, err := cache.NewGitCache(responsesPath, r.git)
 	if err != nil {
-		r.logger.Error("Can't clear '.aidy' directory, '%v'", err)
-		os.Exit(1)
+		return fmt.Errorf("can't open the cache of AI answers: %v", err)
 	}
-	r.logger.Info("'.aidy' directory was cleared")
+	now := time.Now()
+	removed := 0
+	for _, key := range answers.Keys() {
+		if model != "" && !strings.HasPrefix(key, model+"/") {
+			continue
+		}
+		if expired {
+			entry, _ := answers.Get(key)
+			if !ai.Expired(entry, r.ttl, now) {
+				continue
+			}
+		}
+		if err = answers.Delete(key); err != nil {
+			return fmt.Errorf("can't remove a cached AI answer: %v", err)
+		}
+		removed++
+	}
+	r.logger.Info("%d cached AI answers were removed", removed)
+	return nil
+}
+
+func remove(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("can't remove '%s', '%v'", path, err)
+	}
+	return nil
 }
 
 func (r *real) StartIssue(number string) error {
@@ -517,17 +1216,19 @@ func (r *real) StartIssue(number string) error {
 	if number == "" {
 		return fmt.Errorf("error: no issue number provided")
 	}
-	re := regexp.MustCompile(`\d+`)
-	found := re.FindString(number)
+	found := regexp.MustCompile(`[A-Z][A-Z0-9]+-\d+`).FindString(number)
+	if found == "" {
+		found = regexp.MustCompile(`\d+`).FindString(number)
+	}
 	if found == "" {
 		return fmt.Errorf("error: invalid issue number '%s'", number)
 	}
-	r.logger.Info("retrieving the description for issue #%s...", found)
-	descr, err := r.github.Description(found)
+	r.logger.Info("retrieving the description for issue %s...", issueRef(found))
+	descr, err := r.issues(r.github).Description(found)
 	if err != nil {
 		return fmt.Errorf("error retrieving issue description: %v", err)
 	}
-	r.logger.Info("generating branch name for issue #%s...", found)
+	r.logger.Info("generating branch name for issue %s...", issueRef(found))
 	raw, err := r.ai.SuggestBranch(descr)
 	if err != nil {
 		return fmt.Errorf("error generating branch name: %v", err)
@@ -734,17 +1435,17 @@ func latest(tags []string) string {
 }
 
 func (r *real) Squash(issue bool) {
-	base, err := r.git.BaseBranch()
+	base, err := r.baseBranch()
 	if err != nil {
 		r.logger.Error("Error determining base branch: %v", err)
 		os.Exit(1)
 	}
-	err = r.git.Reset("refs/heads/" + base)
+	err = r.git.Reset(git.MergeBase(r.git, base))
 	if err != nil {
 		r.logger.Error("Error executing git reset: %v", err)
 		os.Exit(1)
 	}
-	err = r.Commit(issue)
+	err = r.Commit(issue, false, false, nil)
 	if err != nil {
 		r.logger.Error("Error committing changes: %v", err)
 		os.Exit(1)
@@ -762,9 +1463,16 @@ func (r *real) Heal() error {
 	if gitErr != nil {
 		return fmt.Errorf("error getting current commit message: %v", gitErr)
 	}
+	ref := issueRef(inumber)
 	re := regexp.MustCompile(`#\d+|[A-Z][A-Z0-9]+-\d+`)
-	updated := re.ReplaceAllString(message, issueRef(inumber))
-	err = r.git.Amend(updated)
+	msg := convention.Parse(message, r.convention.Trailer)
+	msg.Subject = r.convention.Heal(re.ReplaceAllString(msg.Subject, ref), ref)
+	for i, trailer := range msg.Trailers {
+		if !strings.HasPrefix(strings.ToLower(trailer), "co-authored-by:") {
+			msg.Trailers[i] = re.ReplaceAllString(trailer, ref)
+		}
+	}
+	err = r.git.Amend(msg.String())
 	return err
 }
 
@@ -797,7 +1505,9 @@ func (r *real) InitSummary(required bool, file string) error {
 			if err != nil {
 				return fmt.Errorf("error generating summary for README.md: %v", err)
 			}
-			r.cache.WithSummary(summary, shash)
+			if err := r.cache.WithSummary(summary, shash); err != nil {
+				return err
+			}
 			r.logger.Info("summary was generated and saved to the cache with hash '%s', using it", shash)
 		} else {
 			r.logger.Info("summary is already saved in the cache with hash '%s', using it", shash)
@@ -814,6 +1524,52 @@ func NewGitHub(git git.Git, conf config.Config, cache cache.AidyCache) (github.G
 	return github.NewGithub("https://api.github.com", git, token, cache), nil
 }
 
+func NewGitLab(git git.Git, conf config.Config) (gitlab.Gitlab, error) {
+	url, err := conf.GitlabUrl()
+	if err != nil {
+		return nil, fmt.Errorf("error getting gitlab url from configuration: %v", err)
+	}
+	token, err := conf.GitlabKey()
+	if err != nil {
+		return nil, fmt.Errorf("error getting gitlab token from configuration: %v", err)
+	}
+	return gitlab.NewGitlab(url, git, token), nil
+}
+
+// NewTracker creates the issue tracker chosen by the 'issue-tracker' configuration key.
+// It returns nil if no tracker is configured, and then each command uses
+// the tracker of its hosting service: GitHub for 'pr', GitLab for 'mr'.
+func NewTracker(conf config.Config, gh github.Github, gl gitlab.Gitlab) (tracker.Tracker, error) {
+	name, err := conf.IssueTracker()
+	if err != nil {
+		return nil, fmt.Errorf("error getting issue tracker from configuration: %v", err)
+	}
+	switch name {
+	case "":
+		return nil, nil
+	case "github":
+		return gh, nil
+	case "gitlab":
+		return gl, nil
+	case "jira":
+		url, err := conf.JiraUrl()
+		if err != nil {
+			return nil, fmt.Errorf("error getting jira url from configuration: %v", err)
+		}
+		user, err := conf.JiraUser()
+		if err != nil {
+			return nil, fmt.Errorf("error getting jira user from configuration: %v", err)
+		}
+		token, err := conf.JiraKey()
+		if err != nil {
+			return nil, fmt.Errorf("error getting jira token from configuration: %v", err)
+		}
+		return jira.NewJira(url, user, token), nil
+	default:
+		return nil, fmt.Errorf("unknown issue tracker '%s' specified in configuration", name)
+	}
+}
+
 func NewConf(aider bool, git git.Git) (config.Config, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -828,37 +1584,160 @@ func NewConf(aider bool, git git.Git) (config.Config, error) {
 	return conf, nil
 }
 
-func Brain(ailess bool, summary bool, conf config.Config, language string) (ai.AI, error) {
+// Brain creates the AI client for the configured provider, rendering the given prompts.
+// When fallback models are configured, they are tried in order if the default one fails.
+// When stream is not nil, answers are written to it as they arrive.
+// Requests follow the timeout and retry settings of the configuration and stop when ctx is done.
+// The answers of each model are kept in responses when 'cache-ttl' is set,
+// and fresh makes models answer again instead of taking their answers from there.
+func Brain(ctx context.Context, ailess bool, summary bool, conf config.Config, language string, prompts *ai.Prompts, stream io.Writer, responses cache.Cache, fresh bool) (ai.AI, error) {
 	if ailess {
 		return ai.NewMockAI(), nil
 	}
+	fallbacks, err := conf.Fallbacks()
+	if err != nil {
+		return nil, fmt.Errorf("error getting fallback models from configuration: %v", err)
+	}
+	ttl, err := conf.CacheTTL()
+	if err != nil {
+		return nil, fmt.Errorf("error getting cache TTL from configuration: %v", err)
+	}
+	remember := func(brain ai.AI, name string) ai.AI {
+		if responses == nil || ttl == 0 {
+			return brain
+		}
+		return ai.NewCached(brain, responses, prompts, name, language, ttl, fresh)
+	}
+	if len(fallbacks) == 0 {
+		brain, name, err := model(ctx, summary, conf, language, prompts, stream)
+		if err != nil {
+			return nil, err
+		}
+		return remember(brain, name), nil
+	}
+	var links []ai.Link
+	for _, each := range append([]config.Config{conf}, fallbacks...) {
+		brain, name, err := model(ctx, summary, each, language, prompts, stream)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, ai.Link{Name: name, AI: remember(brain, name)})
+	}
+	return ai.NewChain(links...), nil
+}
+
+// Budget returns the limit of the diffs sent to AI, sized to the smallest context window
+// of the default and the fallback models, so the prompt fits whichever of them answers.
+func Budget(conf config.Config) (git.Budget, error) {
+	ignore, err := conf.Ignore()
+	if err != nil {
+		return git.Budget{}, fmt.Errorf("error getting ignored files from configuration: %v", err)
+	}
+	fallbacks, err := conf.Fallbacks()
+	if err != nil {
+		return git.Budget{}, fmt.Errorf("error getting fallback models from configuration: %v", err)
+	}
+	var smallest tokens.Limit
+	for i, each := range append([]config.Config{conf}, fallbacks...) {
+		limit, err := limitOf(each)
+		if err != nil {
+			return git.Budget{}, err
+		}
+		if i == 0 || limit.Diff() < smallest.Diff() {
+			smallest = limit
+		}
+	}
+	return git.Budget{Tokens: smallest.Diff(), Ignore: append(git.DefaultIgnore(), ignore...), Tokenizer: smallest.Tokenizer}, nil
+}
+
+// limitOf returns the context window of a single configured model with its tokenizer.
+func limitOf(conf config.Config) (tokens.Limit, error) {
 	provider, err := conf.Provider()
 	if err != nil {
-		return nil, fmt.Errorf("error getting AI provider from configuration: %v", err)
+		return tokens.Limit{}, fmt.Errorf("error getting AI provider from configuration: %v", err)
+	}
+	id, err := conf.Model()
+	if err != nil {
+		return tokens.Limit{}, fmt.Errorf("error getting AI model from configuration: %v", err)
+	}
+	size, err := conf.ContextWindow()
+	if err != nil {
+		return tokens.Limit{}, fmt.Errorf("error getting AI context window from configuration: %v", err)
+	}
+	return tokens.For(provider, id, size), nil
+}
+
+// model creates the AI client of a single configured model and returns it with its name.
+func model(ctx context.Context, summary bool, conf config.Config, language string, prompts *ai.Prompts, stream io.Writer) (ai.AI, string, error) {
+	provider, err := conf.Provider()
+	if err != nil {
+		return nil, "", fmt.Errorf("error getting AI provider from configuration: %v", err)
 	}
 	token, err := conf.Token()
 	if err != nil {
-		return nil, fmt.Errorf("error getting AI token: %v", err)
+		return nil, "", fmt.Errorf("error getting AI token: %v", err)
+	}
+	if token == "" && provider != "ollama" {
+		return nil, "", fmt.Errorf("AI token not found in configuration")
+	}
+	id, err := conf.Model()
+	if err != nil {
+		return nil, "", fmt.Errorf("error getting AI model from configuration: %v", err)
+	}
+	policy := ai.DefaultPolicy()
+	if policy.Timeout, err = conf.Timeout(); err != nil {
+		return nil, "", fmt.Errorf("error getting AI timeout from configuration: %v", err)
+	}
+	if policy.Retries, err = conf.Retries(); err != nil {
+		return nil, "", fmt.Errorf("error getting AI retries from configuration: %v", err)
 	}
-	if token == "" {
-		return nil, fmt.Errorf("AI token not found in configuration")
+	headers, err := conf.Headers()
+	if err != nil {
+		return nil, "", fmt.Errorf("error getting AI headers from configuration: %v", err)
 	}
-	model, err := conf.Model()
+	window, err := conf.ContextWindow()
 	if err != nil {
-		return nil, fmt.Errorf("error getting AI model from configuration: %v", err)
+		return nil, "", fmt.Errorf("error getting AI context window from configuration: %v", err)
+	}
+	client := ai.NewClient(ctx, policy).WithHeaders(headers)
+	var endpoint ai.Endpoint
+	if endpoint.Url, err = conf.BaseUrl(); err != nil {
+		return nil, "", fmt.Errorf("error getting AI base URL from configuration: %v", err)
+	}
+	if endpoint.Version, err = conf.ApiVersion(); err != nil {
+		return nil, "", fmt.Errorf("error getting AI API version from configuration: %v", err)
 	}
 	var brain ai.AI
 	switch provider {
 	case "deepseek":
-		brain = ai.NewDeepSeek(token, summary, language)
+		brain = ai.NewDeepSeek(token, id, window, summary, language, stream, client, endpoint, prompts)
 	case "openai":
-		brain = ai.NewOpenAI(token, model, 0.2, summary, language)
+		brain = ai.NewOpenAI(token, id, window, 0.2, summary, language, stream, client, endpoint, prompts)
 	case "anthropic":
-		brain = ai.NewAnthropic(token, model, summary, language)
+		brain = ai.NewAnthropic(token, id, window, summary, language, stream, client, prompts)
+	case "ollama":
+		brain = ai.NewOllama(endpoint.Url, id, window, summary, language, stream, client, prompts)
 	default:
-		return nil, fmt.Errorf("unknown AI provider '%s' specified in configuration", provider)
+		return nil, "", fmt.Errorf("unknown AI provider '%s' specified in configuration", provider)
+	}
+	name := provider
+	if id != "" {
+		name = fmt.Sprintf("%s/%s", provider, id)
+	}
+	return brain, name, nil
+}
+
+// NewPrompts looks for prompt templates in '.aidy/prompts' of the repository first
+// and then in '~/.aidy/prompts'.
+func NewPrompts(repo git.Git) *ai.Prompts {
+	var dirs []string
+	if root, err := repo.Root(); err == nil {
+		dirs = append(dirs, filepath.Join(root, ".aidy", "prompts"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".aidy", "prompts"))
 	}
-	return brain, nil
+	return ai.NewPrompts(dirs...)
 }
 
 func NewCache(repo git.Git, path string) (cache.AidyCache, error) {
@@ -1,9 +1,13 @@
 package aidy
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"os"
 	"path/filepath"
@@ -13,12 +17,15 @@ import (
 	"github.com/volodya-lombrozo/aidy/internal/ai"
 	"github.com/volodya-lombrozo/aidy/internal/cache"
 	"github.com/volodya-lombrozo/aidy/internal/config"
+	"github.com/volodya-lombrozo/aidy/internal/convention"
 	"github.com/volodya-lombrozo/aidy/internal/executor"
 	"github.com/volodya-lombrozo/aidy/internal/git"
 	"github.com/volodya-lombrozo/aidy/internal/github"
 	"github.com/volodya-lombrozo/aidy/internal/gitlab"
+	"github.com/volodya-lombrozo/aidy/internal/jira"
 	"github.com/volodya-lombrozo/aidy/internal/log"
 	"github.com/volodya-lombrozo/aidy/internal/output"
+	"github.com/volodya-lombrozo/aidy/internal/tracker"
 )
 
 func TestReal_NewGitHub_RemovesSuccessfully(t *testing.T) {
@@ -44,6 +51,20 @@ func TestReal_NewGitHub_CreatesSuccessfully(t *testing.T) {
 	assert.NotNil(t, github, "Expected GitHub instance to be initialized")
 }
 
+func TestReal_NewPrompts_PrefersRepository(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	dir := filepath.Join(tmp, ".aidy", "prompts")
+	require.NoError(t, os.MkdirAll(dir, 0755), "Failed to create prompts directory")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "commit.tmpl"), []byte("team style {{.Number}}"), 0644))
+
+	prompts := NewPrompts(git.NewMockWithDir(tmp))
+	prompt, err := prompts.Render("commit", ai.Prompt{Number: "#9"})
+
+	require.NoError(t, err, "Expected the repository template to be rendered")
+	assert.Equal(t, "team style #9", prompt)
+}
+
 func TestReal_NewCache_NoFile(t *testing.T) {
 	tmp := t.TempDir()
 	filepath := filepath.Join(tmp, ".unexisting.json")
@@ -73,7 +94,7 @@ func TestReal_NewCache_CreatesSuccessfully(t *testing.T) {
 }
 
 func TestReal_InitialisesAI_Mock(t *testing.T) {
-	brain, err := Brain(true, false, config.NewMock(), "en")
+	brain, err := Brain(context.Background(), true, false, config.NewMock(), "en", nil, nil, nil, false)
 
 	require.NoError(t, err, "Expected no error when initializing AI")
 	assert.NotNil(t, brain, "Expected brain to be initialized")
@@ -82,7 +103,7 @@ func TestReal_InitialisesAI_Mock(t *testing.T) {
 func TestReal_InitialisesAI_OpenAI(t *testing.T) {
 	conf := config.NewMock()
 	conf.MockProvider = "openai"
-	brain, err := Brain(false, false, conf, "en")
+	brain, err := Brain(context.Background(), false, false, conf, "en", nil, nil, nil, false)
 
 	require.NoError(t, err, "Expected no error when initializing AI without cache")
 	assert.NotNil(t, brain, "Expected brain to be initialized")
@@ -92,17 +113,85 @@ func TestReal_InitialisesAI_DeepSeek(t *testing.T) {
 	conf := config.NewMock()
 	conf.MockProvider = "deepseek"
 
-	brain, err := Brain(false, false, conf, "en")
+	brain, err := Brain(context.Background(), false, false, conf, "en", nil, nil, nil, false)
 
 	require.NoError(t, err, "Expected no error when initializing AI without cache")
 	assert.NotNil(t, brain, "Expected brain to be initialized")
 }
 
+func TestReal_InitialisesAI_AnthropicStream(t *testing.T) {
+	conf := config.NewMock()
+	conf.MockProvider = "anthropic"
+
+	brain, err := Brain(context.Background(), false, false, conf, "en", nil, &bytes.Buffer{}, nil, false)
+
+	require.NoError(t, err, "Expected no error when initializing a streaming AI")
+	assert.IsType(t, &ai.Anthropic{}, brain, "Expected Anthropic client to be created")
+}
+
+func TestReal_InitialisesAI_OllamaWithoutToken(t *testing.T) {
+	conf := config.NewMock()
+	conf.MockProvider = "ollama"
+	conf.MockToken = ""
+	conf.MockBaseUrl = "http://gpu-box:11434"
+
+	brain, err := Brain(context.Background(), false, false, conf, "en", nil, nil, nil, false)
+
+	require.NoError(t, err, "Expected no token to be required for a local model")
+	assert.IsType(t, &ai.Ollama{}, brain, "Expected Ollama client to be created")
+}
+
+func TestReal_InitialisesAI_CompatibleEndpoint(t *testing.T) {
+	conf := config.NewMock()
+	conf.MockBaseUrl = "https://openrouter.ai/api/v1"
+	conf.MockHeaders = map[string]string{"X-Title": "aidy"}
+
+	brain, err := Brain(context.Background(), false, false, conf, "en", nil, nil, nil, false)
+
+	require.NoError(t, err, "Expected no error when initializing AI with a custom endpoint")
+	assert.IsType(t, &ai.OpenAI{}, brain, "Expected OpenAI client to be created")
+}
+
+func TestReal_InitialisesAI_CachedAnswers(t *testing.T) {
+	conf := config.NewMock()
+	conf.MockTTL = time.Hour
+
+	brain, err := Brain(context.Background(), false, false, conf, "en", nil, nil, cache.NewMockCache(), false)
+
+	require.NoError(t, err, "Expected no error when initializing AI with a cache")
+	assert.IsType(t, &ai.Cached{}, brain, "Expected answers to be cached")
+}
+
+func TestReal_InitialisesAI_FallbackChain(t *testing.T) {
+	conf := config.NewMock()
+	conf.MockProvider = "deepseek"
+	fallback := config.NewMock()
+	fallback.MockProvider = "anthropic"
+	conf.MockFallbacks = []config.Config{fallback}
+
+	brain, err := Brain(context.Background(), false, false, conf, "en", nil, nil, nil, false)
+
+	require.NoError(t, err, "Expected no error when initializing AI with fallbacks")
+	assert.IsType(t, &ai.Chain{}, brain, "Expected a chain of models to be created")
+}
+
+func TestReal_InitialisesAI_BrokenFallback(t *testing.T) {
+	conf := config.NewMock()
+	fallback := config.NewMock()
+	fallback.MockProvider = "unknown"
+	conf.MockFallbacks = []config.Config{fallback}
+
+	_, err := Brain(context.Background(), false, false, conf, "en", nil, nil, nil, false)
+
+	require.Error(t, err, "Expected an error when a fallback model can't be created")
+	assert.Contains(t, err.Error(), "unknown AI provider 'unknown'")
+}
+
 func TestReal_InitialisesAI_UnknownProvider(t *testing.T) {
 	conf := config.NewMock()
 	conf.MockProvider = "unknown"
 
-	brain, err := Brain(false, false, conf, "en")
+	brain, err := Brain(context.Background(), false, false, conf, "en", nil, nil, nil, false)
 
 	require.Error(t, err, "Expected error when initializing AI with unknown provider")
 	assert.Nil(t, brain, "Expected brain to be nil when provider is unknown")
@@ -112,7 +201,7 @@ func TestReal_InitSummary_ErrorGettingProvider(t *testing.T) {
 	conf := config.NewMock()
 	conf.Error = fmt.Errorf("error getting provider")
 
-	brain, err := Brain(false, false, conf, "en")
+	brain, err := Brain(context.Background(), false, false, conf, "en", nil, nil, nil, false)
 
 	require.Error(t, err, "Expected error when getting provider fails")
 	assert.Nil(t, brain, "Expected brain to be nil when getting provider fails")
@@ -211,7 +300,7 @@ func TestReal_SetTarget_IfOnlyOne(t *testing.T) {
 
 func TestReal_SetTarget_NoRepos(t *testing.T) {
 	cache := cache.NewMockAidyCache()
-	cache.WithRemote("")
+	_ = cache.WithRemote("")
 	aidy := &real{git: git.NewMock(), config: config.NewMock(), cache: cache, printer: output.NewMock(), logger: log.NewMock()}
 
 	err := aidy.SetTarget()
@@ -222,7 +311,7 @@ func TestReal_SetTarget_NoRepos(t *testing.T) {
 
 func TestReal_SetTarget_RealGitOutput(t *testing.T) {
 	cache := cache.NewMockAidyCache()
-	cache.WithRemote("")
+	_ = cache.WithRemote("")
 	shell := executor.NewMock()
 	shell.Output = "upstream        git@github.com:yegor256/jaxec.git (fetch)"
 	git := git.NewMockWithShell(shell)
@@ -242,7 +331,7 @@ func TestReal_SetTarget_RealGitOutput(t *testing.T) {
 
 func TestReal_SetTarget_MultipleRemotes(t *testing.T) {
 	cache := cache.NewMockAidyCache()
-	cache.WithRemote("")
+	_ = cache.WithRemote("")
 	shell := executor.NewMock()
 	shell.Output = "https://github.com/cqfn/kaicode.github.io.git\nhttps://github.com/volodya-lombrozo/aidy.git\n"
 	git := git.NewMockWithShell(shell)
@@ -262,7 +351,7 @@ func TestReal_SetTarget_MultipleRemotes(t *testing.T) {
 
 func TestReal_SetTarget_MultipleRemotes_WrongChoice(t *testing.T) {
 	cache := cache.NewMockAidyCache()
-	cache.WithRemote("")
+	_ = cache.WithRemote("")
 	shell := executor.NewMock()
 	shell.Output = "https://github.com/cqfn/kaicode.github.io.git\nhttps://github.com/volodya-lombrozo/aidy.git\n"
 	git := git.NewMockWithShell(shell)
@@ -282,7 +371,7 @@ func TestReal_SetTarget_MultipleRemotes_WrongChoice(t *testing.T) {
 
 func TestReal_SetTarget_GitError(t *testing.T) {
 	cache := cache.NewMockAidyCache()
-	cache.WithRemote("")
+	_ = cache.WithRemote("")
 	aidy := &real{
 		git:     git.NewMockWithError(fmt.Errorf("mock error")),
 		config:  config.NewMock(),
@@ -300,7 +389,7 @@ func TestReal_SetTarget_PreservesDots(t *testing.T) {
 	cache := cache.NewMockAidyCache()
 	shell := executor.NewMock()
 	shell.Output = "https://github.com/cqfn/kaicode.github.io.git"
-	cache.WithRemote("")
+	_ = cache.WithRemote("")
 	aidy := &real{
 		git:     git.NewMockWithShell(shell),
 		config:  config.NewMock(),
@@ -317,15 +406,27 @@ func TestReal_SetTarget_PreservesDots(t *testing.T) {
 
 func TestReal_PrintsDiff_Successfully(t *testing.T) {
 	printer := output.NewMock()
-	git := git.NewMock()
-	raidy := &real{git: git, config: config.NewMock(), printer: printer}
+	repo := &scripted{Git: git.NewMock(), outputs: map[string]string{
+		"merge-base HEAD main":           "abc123",
+		"diff abc123 HEAD":               "diff --git a/main.go b/main.go\n+func Main() {}",
+		"diff --name-status abc123 HEAD": "M\tmain.go",
+	}}
+	raidy := &real{git: repo, config: config.NewMock(), printer: printer}
 
-	err := raidy.Diff()
+	err := raidy.Diff("")
 
-	captured := printer.Captured()
 	require.NoError(t, err, "Expected no error when printing diff")
-	expected := "diff with the base branch:\nmock-diff\n"
-	assert.Equal(t, expected, captured, "Expected diff to be printed")
+	assert.Contains(t, printer.Captured(), "diff with the base branch:\n", "Expected diff to be printed")
+	assert.Contains(t, printer.Captured(), "+func Main() {}", "Expected the changes since the merge-base")
+}
+
+func TestReal_BaseBranch_PrefersTheGivenBranch(t *testing.T) {
+	raidy := &real{git: git.NewMock(), settings: settings{base: "develop"}}
+
+	base, err := raidy.baseBranch()
+
+	require.NoError(t, err)
+	assert.Equal(t, "develop", base)
 }
 
 func TestReal_PrintsDiff_Error(t *testing.T) {
@@ -333,10 +434,11 @@ func TestReal_PrintsDiff_Error(t *testing.T) {
 	git := git.NewMockWithError(fmt.Errorf("mock error"))
 	raidy := &real{git: git, config: config.NewMock(), printer: printer}
 
-	err := raidy.Diff()
+	err := raidy.Diff("")
 
 	require.Error(t, err, "Expected error when printing diff")
-	assert.Equal(t, "failed to get diff: 'mock error'", err.Error(), "Expected error message to match")
+	assert.Contains(t, err.Error(), "failed to get diff:", "Expected error message to match")
+	assert.Contains(t, err.Error(), "mock error", "Expected error message to match")
 }
 
 func TestReal_PrintConfig_Successful(t *testing.T) {
@@ -353,6 +455,8 @@ func TestReal_PrintConfig_Successful(t *testing.T) {
 		"model: gpt-4o",
 		"AI API token: ******oken",
 		"GitHub API key: ***********-key",
+		"GitLab API key: ***********-key",
+		"GitLab URL: https://gitlab.com",
 	}, "\n")
 	assert.Equal(t, expected, res, "Expected configuration to match")
 }
@@ -401,6 +505,8 @@ func TestReal_PrintConfig_NoConfig(t *testing.T) {
 		"error retrieving model: no configuration found",
 		"error retrieving AI token: no configuration found",
 		"error retrieving GitHub API key: no configuration found",
+		"error retrieving GitLab API key: no configuration found",
+		"error retrieving GitLab URL: no configuration found",
 	}, "\n")
 	assert.Equal(t, expected, res, "Expected error message when no config is found")
 }
@@ -409,7 +515,7 @@ func TestReal_Append(t *testing.T) {
 	shell := executor.NewMock()
 	raidy := &real{git: git.NewMockWithShell(shell), logger: log.Default()}
 
-	raidy.Append()
+	raidy.Append(false)
 
 	expected := "git commit --amend --no-edit "
 	require.Equal(t, 1, len(shell.Commands), "Expected number of commands to match")
@@ -460,22 +566,45 @@ func TestReal_Heal_CantAmend(t *testing.T) {
 
 func TestReal_CleanCache(t *testing.T) {
 	tmp := t.TempDir()
-	cache := filepath.Join(tmp, ".aidy")
-	err := os.Mkdir(cache, 0755)
-	require.NoError(t, err, "Failed to create .aidy directory")
-	original, err := os.Getwd()
-	require.NoError(t, err, "Failed to get current working directory")
-	defer func() {
-		_ = os.Chdir(original)
-	}()
-	err = os.Chdir(tmp)
-	require.NoError(t, err, "Failed to change working directory")
-	raidy := &real{logger: log.Default()}
+	require.NoError(t, os.MkdirAll(filepath.Join(tmp, ".aidy", "prompts"), 0755), "Failed to create .aidy directory")
+	template := filepath.Join(tmp, ".aidy", "prompts", "commit.tmpl")
+	require.NoError(t, os.WriteFile(template, []byte("mine"), 0644), "Failed to create a template")
+	files := []string{"responses.js", "responses.js.lock", "summaries.js", "cache.js", "cache.js.lock"}
+	for _, name := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(tmp, ".aidy", name), []byte("{}"), 0644), "Failed to create %s", name)
+	}
+	raidy := &real{git: git.NewMockWithDir(tmp), logger: log.NewMock()}
+
+	err := raidy.Clean(false, false, false, "")
+
+	require.NoError(t, err, "Expected no error when clearing the cache")
+	for _, name := range files {
+		_, err = os.Stat(filepath.Join(tmp, ".aidy", name))
+		assert.True(t, os.IsNotExist(err), "%s should be removed", name)
+	}
+	_, err = os.Stat(template)
+	assert.NoError(t, err, "prompt templates should be kept")
+}
+
+func TestReal_Clean_ExpiredAnswersOfModel(t *testing.T) {
+	tmp := t.TempDir()
+	old := `{"answer":"old","at":"2020-01-01T00:00:00Z"}`
+	recent := fmt.Sprintf(`{"answer":"new","at":"%s"}`, time.Now().UTC().Format(time.RFC3339))
+	data, err := json.Marshal(map[string]string{"openai/gpt-4o/en/1": old, "openai/gpt-4o/en/2": recent, "deepseek/en/3": old})
+	require.NoError(t, err, "Failed to encode answers")
+	require.NoError(t, os.MkdirAll(filepath.Join(tmp, ".aidy"), 0755), "Failed to create .aidy directory")
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, ".aidy", "responses.js"), data, 0644), "Failed to create answers")
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, ".aidy", "summaries.js"), []byte("{}"), 0644), "Failed to create summaries")
+	raidy := &real{git: git.NewMockWithDir(tmp), logger: log.NewMock(), ttl: 24 * time.Hour}
 
-	raidy.Clean()
+	err = raidy.Clean(false, true, true, "openai/gpt-4o")
 
-	_, err = os.Stat(cache)
-	assert.True(t, os.IsNotExist(err), ".aidy directory should be removed")
+	require.NoError(t, err, "Expected no error when cleaning answers")
+	answers, err := cache.NewFileCache(filepath.Join(tmp, ".aidy", "responses.js"))
+	require.NoError(t, err, "Failed to open answers")
+	assert.Equal(t, []string{"deepseek/en/3", "openai/gpt-4o/en/2"}, answers.Keys(), "Expected only expired answers of the model to be removed")
+	_, err = os.Stat(filepath.Join(tmp, ".aidy", "summaries.js"))
+	assert.True(t, os.IsNotExist(err), "Expected summaries to be removed")
 }
 
 func TestReal_StartIssue(t *testing.T) {
@@ -494,6 +623,94 @@ func TestReal_StartIssue(t *testing.T) {
 	}
 }
 
+func TestReal_StartIssue_Jira(t *testing.T) {
+	shell := executor.NewMock()
+	raidy := &real{git: git.NewMockWithShell(shell), ai: ai.NewMockAI(), github: github.NewMock(), tracker: jira.NewMock(), cache: cache.NewMockAidyCache(), logger: log.NewMock()}
+
+	err := raidy.StartIssue("PROJ-42")
+
+	require.NoError(t, err, "Expected no error when starting a Jira issue")
+	require.Len(t, shell.Commands, 1, "Expected a single command to be run")
+	assert.Contains(t, shell.Commands[0], "git checkout -b PROJ-42-mock-branch-name")
+}
+
+func TestReal_Commit_JiraIssue(t *testing.T) {
+	shell := executor.NewMock()
+	j := jira.NewMock()
+	j.Error = fmt.Errorf("jira is down")
+	raidy := &real{git: git.NewMockWithShell(shell), ai: ai.NewMockAI(), github: github.NewMock(), tracker: j, cache: cache.NewMockAidyCache(), logger: log.NewMock()}
+
+	err := raidy.Commit(true, false, false, nil)
+
+	require.Error(t, err, "Expected the configured tracker to be used for the issue description")
+	assert.Contains(t, err.Error(), "jira is down")
+}
+
+func TestReal_PullRequest_Tracker(t *testing.T) {
+	out := output.NewMock()
+	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), github: github.NewMock(), tracker: jira.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.NewMock()}
+
+	err := raidy.PullRequest(PullRequestOptions{})
+
+	require.NoError(t, err, "expected no error when creating pull request")
+	assert.Contains(t, out.Last(), "mock description for issue '41'", "Expected the issue description to come from the configured tracker")
+}
+
+func TestReal_MergeRequest_Tracker(t *testing.T) {
+	out := output.NewMock()
+	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), gitlab: gitlab.NewMock(), tracker: jira.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.NewMock()}
+
+	err := raidy.MergeRequest(false, "", false, "", "")
+
+	require.NoError(t, err, "expected no error when creating merge request")
+	assert.Contains(t, out.Last(), "mock description for issue '41'", "Expected the issue description to come from the configured tracker")
+}
+
+func TestReal_NewTracker(t *testing.T) {
+	gh := github.NewMock()
+	gl := gitlab.NewMock()
+	tests := []struct {
+		name     string
+		expected tracker.Tracker
+	}{
+		{"", nil},
+		{"github", gh},
+		{"gitlab", gl},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf := config.NewMock()
+			conf.MockTracker = test.name
+
+			found, err := NewTracker(conf, gh, gl)
+
+			require.NoError(t, err, "Expected no error when creating the issue tracker")
+			assert.Equal(t, test.expected, found)
+		})
+	}
+}
+
+func TestReal_NewTracker_Jira(t *testing.T) {
+	conf := config.NewMock()
+	conf.MockTracker = "jira"
+	conf.MockJiraUrl = "https://example.atlassian.net"
+
+	found, err := NewTracker(conf, github.NewMock(), gitlab.NewMock())
+
+	require.NoError(t, err, "Expected no error when creating the Jira tracker")
+	assert.NotNil(t, found, "Expected the Jira tracker to be created")
+}
+
+func TestReal_NewTracker_Unknown(t *testing.T) {
+	conf := config.NewMock()
+	conf.MockTracker = "redmine"
+
+	_, err := NewTracker(conf, github.NewMock(), gitlab.NewMock())
+
+	require.Error(t, err, "Expected an error for an unknown issue tracker")
+	assert.Contains(t, err.Error(), "unknown issue tracker 'redmine'")
+}
+
 func TestReal_StartIssueNoNumber(t *testing.T) {
 	brain := ai.NewMockAI()
 	shell := executor.NewMock()
@@ -549,8 +766,10 @@ func TestReal_Squash(t *testing.T) {
 	raidy.Squash(true)
 
 	expected := []string{
-		"git reset --soft refs/heads/main",
+		"git merge-base HEAD main",
+		"git reset --soft main",
 		"git add --all",
+		"git diff --cached",
 		"git commit -m feat(#41): no files changed",
 		"git commit --amend -m feat(#41): current commit message",
 	}
@@ -563,7 +782,7 @@ func TestReal_PullRequest(t *testing.T) {
 	out := output.NewMock()
 	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), github: github.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.Default()}
 
-	err := raidy.PullRequest(false, "", false, "")
+	err := raidy.PullRequest(PullRequestOptions{})
 
 	require.NoError(t, err, "expected no error when creating pull request")
 	output := out.Last()
@@ -575,20 +794,66 @@ func TestReal_PullRequest_Target(t *testing.T) {
 	out := output.NewMock()
 	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), github: github.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.Default()}
 
-	err := raidy.PullRequest(false, "develop", false, "")
+	err := raidy.PullRequest(PullRequestOptions{Target: "develop"})
 
 	require.NoError(t, err, "expected no error when creating pull request with target branch")
 	output := out.Last()
 	assert.Contains(t, output, "--base develop", "Expected output to contain target branch")
 }
 
+func TestReal_PullRequest_DiffWithTarget(t *testing.T) {
+	out := output.NewMock()
+	repo := &scripted{Git: git.NewMock(), outputs: map[string]string{
+		"merge-base HEAD develop":        "abc123",
+		"diff abc123 HEAD":               "diff --git a/feature.go b/feature.go\n+func Feature() {}",
+		"diff --name-status abc123 HEAD": "M\tfeature.go",
+		"diff --stat abc123 HEAD":        " feature.go | 1 +",
+	}}
+	raidy := &real{git: repo, ai: ai.NewMockAI(), github: github.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.NewMock()}
+
+	err := raidy.PullRequest(PullRequestOptions{Target: "develop"})
+
+	require.NoError(t, err, "expected no error when creating pull request with target branch")
+	assert.Contains(t, out.Last(), "func Feature()", "Expected the diff since the fork from the target")
+}
+
+func TestReal_PullRequest_DiffsInConfiguredMode(t *testing.T) {
+	out := output.NewMock()
+	repo := &scripted{Git: git.NewMock(), outputs: map[string]string{
+		"merge-base HEAD develop":            "abc123",
+		"diff --cached abc123":               "diff --git a/staged.go b/staged.go\n+func Staged() {}",
+		"diff --name-status --cached abc123": "A\tstaged.go",
+		"diff --stat --cached abc123":        " staged.go | 1 +",
+	}}
+	raidy := &real{git: repo, ai: ai.NewMockAI(), github: github.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.NewMock(), settings: settings{mode: git.Staged}}
+
+	err := raidy.PullRequest(PullRequestOptions{Target: "develop"})
+
+	require.NoError(t, err, "expected no error when creating pull request with staged changes")
+	assert.Contains(t, out.Last(), "func Staged()", "Expected the staged changes since the fork from the target")
+}
+
+func TestReal_PullRequest_PassesCommitsOfBranch(t *testing.T) {
+	out := output.NewMock()
+	repo := &scripted{Git: git.NewMock(), outputs: map[string]string{
+		"merge-base HEAD develop":                                "abc123",
+		"log --reverse --no-merges --format=%B%x00 abc123..HEAD": "feat: add parser\n\nThe old one was too slow.\n\x00\nfix: handle empty input\n\x00\n",
+	}}
+	raidy := &real{git: repo, ai: ai.NewMockAI(), github: github.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.NewMock()}
+
+	err := raidy.PullRequest(PullRequestOptions{Target: "develop"})
+
+	require.NoError(t, err, "expected no error when creating pull request with commits")
+	assert.Contains(t, out.Last(), "- feat: add parser\n\n  The old one was too slow.\n- fix: handle empty input", "Expected the commit messages of the branch, oldest first")
+}
+
 func TestReal_PullRequest_IssueNotFound(t *testing.T) {
 	github := github.NewMock()
 	github.Error = fmt.Errorf("issue not found")
 	out := output.NewMock()
 	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), github: github, editor: out, cache: cache.NewMockAidyCache(), logger: log.NewMock()}
 
-	err := raidy.PullRequest(false, "", false, "")
+	err := raidy.PullRequest(PullRequestOptions{})
 
 	require.NoError(t, err, "Expected no error when creating pull request with issue not found")
 	output := out.Last()
@@ -603,7 +868,7 @@ func TestReal_PullRequest_Fixes(t *testing.T) {
 	out := output.NewMock()
 	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), github: github, editor: out, cache: cache.NewMockAidyCache(), logger: log.NewMock()}
 
-	err := raidy.PullRequest(true, "", false, "")
+	err := raidy.PullRequest(PullRequestOptions{Fixes: true})
 
 	require.NoError(t, err, "Expected no error when creating pull request with issue not found")
 	output := out.Last()
@@ -614,7 +879,7 @@ func TestReal_PullRequest_Duplicate(t *testing.T) {
 	out := output.NewMock()
 	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), github: github.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.Default()}
 
-	err := raidy.PullRequest(false, "develop", true, "")
+	err := raidy.PullRequest(PullRequestOptions{Target: "develop", Duplicate: true})
 
 	require.NoError(t, err, "expected no error when duplicating a pull request")
 	output := out.Last()
@@ -628,7 +893,7 @@ func TestReal_PullRequest_Duplicate_Source(t *testing.T) {
 	out := output.NewMock()
 	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), github: github.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.Default()}
 
-	err := raidy.PullRequest(false, "develop", true, "feature-x")
+	err := raidy.PullRequest(PullRequestOptions{Target: "develop", Duplicate: true, Source: "feature-x"})
 
 	require.NoError(t, err, "expected no error when duplicating a pull request from a given source branch")
 	output := out.Last()
@@ -641,7 +906,7 @@ func TestReal_PullRequest_Duplicate_NoTarget(t *testing.T) {
 	out := output.NewMock()
 	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), github: github.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.Default()}
 
-	err := raidy.PullRequest(false, "", true, "")
+	err := raidy.PullRequest(PullRequestOptions{Duplicate: true})
 
 	require.Error(t, err, "expected an error when duplicating a pull request without a target branch")
 	assert.Contains(t, err.Error(), "--duplicate requires --target")
@@ -653,17 +918,151 @@ func TestReal_PullRequest_Duplicate_NotFound(t *testing.T) {
 	out := output.NewMock()
 	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), github: github, editor: out, cache: cache.NewMockAidyCache(), logger: log.Default()}
 
-	err := raidy.PullRequest(false, "develop", true, "")
+	err := raidy.PullRequest(PullRequestOptions{Target: "develop", Duplicate: true})
 
 	require.Error(t, err, "expected an error when there is no pull request to duplicate")
 	assert.Contains(t, err.Error(), "error finding an existing pull request to duplicate")
 }
 
+func TestReal_PullRequest_DraftWithReviewersAndLabels(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".github"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".github", "CODEOWNERS"), []byte("*.go @backend\n/docs/ @org/writers\n"), 0644))
+	out := output.NewMock()
+	repo := &scripted{Git: git.NewMockWithDir(dir), outputs: map[string]string{
+		"merge-base HEAD develop":                     "abc123",
+		"diff --no-renames --name-status abc123 HEAD": "M\tmain.go\nA\tdocs/guide.md",
+	}}
+	raidy := &real{git: repo, ai: ai.NewMockAI(), github: github.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.NewMock()}
+
+	err := raidy.PullRequest(PullRequestOptions{Target: "develop", Draft: true, Reviewers: []string{"alice", "backend"}, Assignees: []string{"bob"}, Milestone: "v1.0"})
+
+	require.NoError(t, err, "expected no error when creating a draft pull request")
+	command := out.Last()
+	assert.Contains(t, command, "--draft")
+	assert.Contains(t, command, "--reviewer \"alice,backend,org/writers\"", "Expected the code owners of the changes after the given reviewers")
+	assert.Contains(t, command, "--assignee \"bob\"")
+	assert.Contains(t, command, "--milestone \"v1.0\"")
+	assert.Contains(t, command, "--label \"bug,documentation,question\"", "Expected the labels chosen by AI")
+}
+
+func TestReal_PullRequest_SkipsAuthorAmongCodeOwners(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "CODEOWNERS"), []byte("*.go @Mock-User @backend\n"), 0644))
+	out := output.NewMock()
+	repo := &scripted{Git: git.NewMockWithDir(dir), outputs: map[string]string{
+		"merge-base HEAD develop":                     "abc123",
+		"diff --no-renames --name-status abc123 HEAD": "M\tmain.go",
+	}}
+	raidy := &real{git: repo, ai: ai.NewMockAI(), github: github.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.NewMock()}
+
+	err := raidy.PullRequest(PullRequestOptions{Target: "develop"})
+
+	require.NoError(t, err, "expected no error when creating a pull request")
+	assert.Contains(t, out.Last(), "--reviewer \"backend\"", "Expected the authenticated user not to review their own pull request")
+}
+
+func TestReal_PullRequest_Submit(t *testing.T) {
+	out := output.NewMock()
+	out.Submitted = true
+	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), github: github.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.NewMock()}
+
+	err := raidy.PullRequest(PullRequestOptions{})
+
+	require.NoError(t, err, "expected no error when submitting a pull request")
+	assert.Equal(t, "https://github.com/mock/remote/pull/41_working_branch", out.Created, "expected the pull request to be created from the current branch")
+}
+
+func TestReal_PullRequest_Submit_Error(t *testing.T) {
+	gh := github.NewMock()
+	out := output.NewMock()
+	out.Submitted = true
+	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), github: gh, editor: out, cache: cache.NewMockAidyCache(), logger: log.NewMock()}
+	gh.Error = fmt.Errorf("validation failed")
+
+	err := raidy.PullRequest(PullRequestOptions{Target: "develop"})
+
+	require.Error(t, err, "expected an error when the pull request can't be created")
+	assert.Contains(t, err.Error(), "validation failed")
+}
+
+func TestReal_PullRequest_SubmitsFromFork(t *testing.T) {
+	out := output.NewMock()
+	out.Submitted = true
+	gh := github.NewMock()
+	repo := &scripted{Git: git.NewMock(), outputs: map[string]string{
+		"config --get branch.41_working_branch.remote": "fork\n",
+		"remote get-url fork":                          "git@github.com:alice/aidy.git\n",
+	}}
+	ch := cache.NewMockAidyCache()
+	require.NoError(t, ch.WithRemote("volodya-lombrozo/aidy"))
+	raidy := &real{git: repo, ai: ai.NewMockAI(), github: gh, editor: out, cache: ch, logger: log.NewMock()}
+
+	err := raidy.PullRequest(PullRequestOptions{Target: "develop", Draft: true, Milestone: "v1.0"})
+
+	require.NoError(t, err, "expected no error when submitting a pull request from a fork")
+	assert.Contains(t, out.Last(), "--head alice:41_working_branch --repo volodya-lombrozo/aidy")
+	assert.Equal(t, "volodya-lombrozo/aidy", gh.Created.Repo, "Expected the pull request to be opened in the target repository")
+	assert.Equal(t, "alice:41_working_branch", gh.Created.Head, "Expected the head to name the owner of the fork")
+	assert.Equal(t, "develop", gh.Created.Base)
+	assert.True(t, gh.Created.Draft)
+	assert.Equal(t, "v1.0", gh.Created.Milestone)
+	assert.Equal(t, []string{"bug", "documentation", "question"}, gh.Created.Labels)
+}
+
+func TestReal_PullRequest_SubmitsTextWithQuotesAndBackslashes(t *testing.T) {
+	out := output.NewMock()
+	out.Submitted = true
+	gh := github.NewMock()
+	brain := &quoting{AI: ai.NewMockAI(), body: "Read \"C:\\aidy\\config\" and run `aidy pr`"}
+	raidy := &real{git: git.NewMock(), ai: brain, github: gh, editor: out, cache: cache.NewMockAidyCache(), logger: log.NewMock()}
+
+	err := raidy.PullRequest(PullRequestOptions{Target: "develop"})
+
+	require.NoError(t, err, "expected no error when submitting a body with quotes")
+	assert.True(t, strings.HasPrefix(gh.Created.Body, "Read \"C:\\aidy\\config\" and run `aidy pr`"), "Expected the body to be sent as generated")
+}
+
+// quoting answers with the body that a shell command can't hold without escaping.
+type quoting struct {
+	ai.AI
+	body string
+}
+
+func (q *quoting) PrBody(diff, issue, summary, commits, form string) (string, error) {
+	return q.body, nil
+}
+
+func TestReal_PullRequest_FillsInTemplate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".github"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".github", "pull_request_template.md"), []byte("## Summary\n\n- [ ] Tests added\n"), 0644))
+	out := output.NewMock()
+	raidy := &real{git: git.NewMockWithDir(dir), ai: ai.NewMockAI(), github: github.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.NewMock()}
+
+	err := raidy.PullRequest(PullRequestOptions{})
+
+	require.NoError(t, err, "expected no error when creating pull request from a template")
+	assert.Contains(t, out.Last(), "template:\n## Summary\n\n- [ ] Tests added", "Expected the template to be passed to AI")
+}
+
+func TestReal_MergeRequest_UnknownTemplate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".gitlab", "merge_request_templates"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitlab", "merge_request_templates", "Bug.md"), []byte("## Bug"), 0644))
+	raidy := &real{git: git.NewMockWithDir(dir), ai: ai.NewMockAI(), gitlab: gitlab.NewMock(), editor: output.NewMock(), cache: cache.NewMockAidyCache(), logger: log.NewMock()}
+
+	err := raidy.MergeRequest(false, "", false, "", "feature")
+
+	require.Error(t, err, "expected an error for a template that doesn't exist")
+	assert.Contains(t, err.Error(), "template 'feature' not found, available templates: bug")
+}
+
 func TestReal_MergeRequest(t *testing.T) {
 	out := output.NewMock()
-	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), github: github.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.Default()}
+	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), github: github.NewMock(), gitlab: gitlab.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.Default()}
 
-	err := raidy.MergeRequest(false, "", false, "")
+	err := raidy.MergeRequest(false, "", false, "", "")
 
 	require.NoError(t, err, "expected no error when creating merge request")
 	result := out.Last()
@@ -671,11 +1070,71 @@ func TestReal_MergeRequest(t *testing.T) {
 	assert.Contains(t, result, "Related to #")
 }
 
+func TestReal_MergeRequest_Labels(t *testing.T) {
+	out := output.NewMock()
+	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), gitlab: gitlab.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.NewMock()}
+
+	err := raidy.MergeRequest(false, "develop", false, "", "")
+
+	require.NoError(t, err, "expected no error when creating merge request")
+	assert.Contains(t, out.Last(), "--label \"bug,documentation,question\" --target-branch develop", "Expected the labels chosen by AI")
+}
+
+func TestReal_MergeRequest_IssueDescription(t *testing.T) {
+	out := output.NewMock()
+	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), gitlab: gitlab.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.NewMock()}
+
+	err := raidy.MergeRequest(false, "", false, "", "")
+
+	require.NoError(t, err, "expected no error when creating merge request")
+	assert.Contains(t, out.Last(), "with issue #mock description for issue '#41'", "Expected the GitLab issue description to be used")
+}
+
+func TestReal_MergeRequest_IssueNotFound(t *testing.T) {
+	gl := gitlab.NewMock()
+	gl.Error = fmt.Errorf("issue not found")
+	out := output.NewMock()
+	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), gitlab: gl, editor: out, cache: cache.NewMockAidyCache(), logger: log.NewMock()}
+
+	err := raidy.MergeRequest(false, "", false, "", "")
+
+	require.NoError(t, err, "expected no error when the issue is not found")
+	assert.Contains(t, out.Last(), "with issue #not-found")
+}
+
+func TestReal_MergeRequest_Submit(t *testing.T) {
+	out := output.NewMock()
+	out.Submitted = true
+	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), gitlab: gitlab.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.NewMock()}
+
+	err := raidy.MergeRequest(false, "develop", false, "", "")
+
+	require.NoError(t, err, "expected no error when submitting a merge request")
+	assert.Equal(t, "https://gitlab.com/mock/project/-/merge_requests/41_working_branch", out.Created)
+}
+
+func TestReal_NewGitLab(t *testing.T) {
+	gl, err := NewGitLab(git.NewMock(), config.NewMock())
+
+	require.NoError(t, err, "Expected no error when creating new GitLab instance")
+	assert.NotNil(t, gl, "Expected GitLab instance to be initialized")
+}
+
+func TestReal_NewGitLab_ConfigError(t *testing.T) {
+	conf := config.NewMock()
+	conf.Error = fmt.Errorf("mock error")
+
+	_, err := NewGitLab(git.NewMock(), conf)
+
+	require.Error(t, err, "Expected an error when the configuration can't be read")
+	assert.Contains(t, err.Error(), "mock error")
+}
+
 func TestReal_MergeRequest_Target(t *testing.T) {
 	out := output.NewMock()
-	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), github: github.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.Default()}
+	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), github: github.NewMock(), gitlab: gitlab.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.Default()}
 
-	err := raidy.MergeRequest(false, "develop", false, "")
+	err := raidy.MergeRequest(false, "develop", false, "", "")
 
 	require.NoError(t, err, "expected no error when creating merge request with target branch")
 	result := out.Last()
@@ -684,9 +1143,9 @@ func TestReal_MergeRequest_Target(t *testing.T) {
 
 func TestReal_MergeRequest_Fixes(t *testing.T) {
 	out := output.NewMock()
-	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), github: github.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.NewMock()}
+	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), github: github.NewMock(), gitlab: gitlab.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.NewMock()}
 
-	err := raidy.MergeRequest(true, "", false, "")
+	err := raidy.MergeRequest(true, "", false, "", "")
 
 	require.NoError(t, err, "expected no error when creating merge request with fixes")
 	result := out.Last()
@@ -698,7 +1157,7 @@ func TestReal_MergeRequest_Duplicate(t *testing.T) {
 	out := output.NewMock()
 	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), github: github.NewMock(), gitlab: gitlab.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.Default()}
 
-	err := raidy.MergeRequest(false, "develop", true, "")
+	err := raidy.MergeRequest(false, "develop", true, "", "")
 
 	require.NoError(t, err, "expected no error when duplicating a merge request")
 	result := out.Last()
@@ -712,7 +1171,7 @@ func TestReal_MergeRequest_Duplicate_Source(t *testing.T) {
 	out := output.NewMock()
 	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), github: github.NewMock(), gitlab: gitlab.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.Default()}
 
-	err := raidy.MergeRequest(false, "develop", true, "feature-x")
+	err := raidy.MergeRequest(false, "develop", true, "feature-x", "")
 
 	require.NoError(t, err, "expected no error when duplicating a merge request from a given source branch")
 	result := out.Last()
@@ -725,7 +1184,7 @@ func TestReal_MergeRequest_Duplicate_NoTarget(t *testing.T) {
 	out := output.NewMock()
 	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), github: github.NewMock(), gitlab: gitlab.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.Default()}
 
-	err := raidy.MergeRequest(false, "", true, "")
+	err := raidy.MergeRequest(false, "", true, "", "")
 
 	require.Error(t, err, "expected an error when duplicating a merge request without a target branch")
 	assert.Contains(t, err.Error(), "--duplicate requires --target")
@@ -737,7 +1196,7 @@ func TestReal_MergeRequest_Duplicate_NotFound(t *testing.T) {
 	out := output.NewMock()
 	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), github: github.NewMock(), gitlab: gl, editor: out, cache: cache.NewMockAidyCache(), logger: log.Default()}
 
-	err := raidy.MergeRequest(false, "develop", true, "")
+	err := raidy.MergeRequest(false, "develop", true, "", "")
 
 	require.Error(t, err, "expected an error when there is no merge request to duplicate")
 	assert.Contains(t, err.Error(), "error finding an existing merge request to duplicate")
@@ -749,11 +1208,12 @@ func TestReal_Commit(t *testing.T) {
 	mgit := git.NewMockWithShell(shell)
 	raidy := &real{git: mgit, ai: brain, logger: log.Default(), github: github.NewMock(), cache: cache.NewMockAidyCache()}
 
-	err := raidy.Commit(true)
+	err := raidy.Commit(true, false, false, nil)
 
 	require.NoError(t, err, "expected no error when committing changes")
 	expected := []string{
 		"git add --all",
+		"git diff --cached",
 		"git commit -m feat(#41): no files changed",
 		"git commit --amend -m feat(#41): current commit message",
 	}
@@ -767,7 +1227,7 @@ func TestReal_Commit_CantGetCurrentBranch(t *testing.T) {
 
 	raidy := &real{git: mgit, ai: ai.NewMockAI(), logger: log.Default(), cache: cache.NewMockAidyCache()}
 
-	err := raidy.Commit(true)
+	err := raidy.Commit(true, false, false, nil)
 	require.Error(t, err, "expected error when unable to get current branch")
 	assert.Equal(t, "error getting branch name: CurrentBranch method fails", err.Error(), "Expected error message to match")
 }
@@ -776,7 +1236,7 @@ func TestReal_Commit_CantGetCurrentDiff(t *testing.T) {
 	mgit := git.NewMockWithError(fmt.Errorf("CurrentDiff method fails"))
 	raidy := &real{git: mgit, ai: ai.NewMockAI(), logger: log.Default(), cache: cache.NewMockAidyCache(), github: github.NewMock()}
 
-	err := raidy.Commit(true)
+	err := raidy.Commit(true, false, false, nil)
 
 	require.Error(t, err, "expected error when unable to get current diff")
 	assert.Equal(t, "error adding changes: CurrentDiff method fails", err.Error(), "Expected error message to match")
@@ -788,7 +1248,7 @@ func TestReal_Commit_CantRunGit(t *testing.T) {
 	mgit := git.NewMockWithShell(shell)
 	raidy := &real{git: mgit, ai: ai.NewMockAI(), logger: log.Default(), cache: cache.NewMockAidyCache(), github: github.NewMock()}
 
-	err := raidy.Commit(true)
+	err := raidy.Commit(true, false, false, nil)
 
 	require.Error(t, err, "expected error when git command fails")
 	assert.Equal(t, "error adding changes: git command failed", err.Error(), "Expected error message to match")
@@ -809,6 +1269,17 @@ func TestReal_Issue(t *testing.T) {
 	assert.Contains(t, output, "--label \"bug,documentation,question\"")
 }
 
+func TestReal_Issue_Submit(t *testing.T) {
+	out := output.NewMock()
+	out.Submitted = true
+	raidy := &real{ai: ai.NewMockAI(), github: github.NewMock(), editor: out, cache: cache.NewMockAidyCache(), logger: log.NewMock()}
+
+	err := raidy.Issue("test input")
+
+	require.NoError(t, err, "expected no error when submitting an issue")
+	assert.Equal(t, "https://github.com/mock/remote/issues/1", out.Created)
+}
+
 func TestReal_Release_Success(t *testing.T) {
 	mgit := git.NewMock()
 	nobrain := ai.NewMockAI()
@@ -1111,6 +1582,217 @@ func TestEscapeBackticks(t *testing.T) {
 	}
 }
 
+func TestReal_Commit_IssueInTrailer(t *testing.T) {
+	shell := executor.NewMock()
+	conv := convention.Convention{Issue: "trailer", Gitmoji: true}
+	raidy := &real{git: git.NewMockWithShell(shell), ai: ai.NewMockAI(), logger: log.Default(), github: github.NewMock(), cache: cache.NewMockAidyCache(), convention: conv}
+
+	err := raidy.Commit(false, false, false, nil)
+
+	require.NoError(t, err, "expected no error when committing changes")
+	assert.Contains(t, shell.Commands[2], "git commit -m ✨ feat: no files changed\n\nRefs: #41", "Expected the issue to be moved to the trailer")
+}
+
+func TestReal_Commit_RepairsMessage(t *testing.T) {
+	shell := executor.NewMock()
+	brain := &answers{AI: ai.NewMockAI(), messages: []string{"\"feat(#41): Added feature.\"", "feat(#41): add feature"}}
+	raidy := &real{git: git.NewMockWithShell(shell), ai: brain, logger: log.NewMock(), github: github.NewMock(), cache: cache.NewMockAidyCache()}
+
+	err := raidy.Commit(false, false, false, nil)
+
+	require.NoError(t, err, "expected no error when committing changes")
+	assert.Contains(t, shell.Commands[2], "git commit -m feat(#41): add feature")
+	require.Len(t, brain.hints, 2, "Expected AI to be asked twice")
+	assert.Empty(t, brain.hints[0], "Expected no hint for the first attempt")
+	assert.Contains(t, brain.hints[1], "must not end with a period")
+	assert.Contains(t, brain.hints[1], "must not be wrapped in quotes")
+	assert.Contains(t, brain.hints[1], "imperative mood")
+}
+
+func TestReal_Commit_ReviewsUnrepairedMessage(t *testing.T) {
+	shell := executor.NewMock()
+	brain := &answers{AI: ai.NewMockAI(), messages: []string{"feature(#41): add feature"}}
+	editor := output.NewMock()
+	editor.EditText = "feat(#41): add feature\n"
+	raidy := &real{git: git.NewMockWithShell(shell), ai: brain, logger: log.NewMock(), github: github.NewMock(), cache: cache.NewMockAidyCache(), texteditor: editor}
+
+	err := raidy.Commit(false, false, false, nil)
+
+	require.NoError(t, err, "expected no error when committing changes")
+	assert.Len(t, brain.hints, 3, "Expected AI to be asked to repair the message twice")
+	assert.Equal(t, "feature(#41): add feature", editor.Last(), "Expected the user to review the last message")
+	assert.Contains(t, shell.Commands[2], "git commit -m feat(#41): add feature")
+}
+
+func TestReal_Commit_CanceledReview(t *testing.T) {
+	shell := executor.NewMock()
+	zero := 0
+	brain := &answers{AI: ai.NewMockAI(), messages: []string{"feat(#41): adding feature"}}
+	editor := output.NewMock()
+	editor.EditErr = output.ErrCanceled
+	raidy := &real{git: git.NewMockWithShell(shell), ai: brain, logger: log.NewMock(), github: github.NewMock(), cache: cache.NewMockAidyCache(), texteditor: editor, convention: convention.Convention{Attempts: &zero}}
+
+	err := raidy.Commit(false, false, false, nil)
+
+	require.Error(t, err, "expected an error when the review is canceled")
+	assert.Contains(t, err.Error(), "commit was canceled")
+	assert.Len(t, brain.hints, 1, "Expected no repairs")
+	assert.Len(t, shell.Commands, 2, "Expected nothing to be committed")
+}
+
+func TestReal_Commit_RegeneratesOnReview(t *testing.T) {
+	shell := executor.NewMock()
+	brain := &answers{AI: ai.NewMockAI(), messages: []string{"feat(#41): add feature", "feat(#41): add cache"}}
+	editor := output.NewMock()
+	editor.Hints = []string{"mention the cache"}
+	raidy := &real{git: git.NewMockWithShell(shell), ai: brain, logger: log.NewMock(), github: github.NewMock(), cache: cache.NewMockAidyCache(), texteditor: editor}
+
+	err := raidy.Commit(false, true, false, nil)
+
+	require.NoError(t, err, "expected no error when committing changes")
+	assert.Equal(t, []string{"", "mention the cache"}, brain.hints, "Expected the hint to be passed to AI")
+	assert.Equal(t, "git write-tree", strings.TrimSpace(shell.Commands[0]), "Expected the index to be saved")
+	assert.Contains(t, shell.Commands[3], "git commit -m feat(#41): add cache")
+}
+
+func TestReal_Commit_RegeneratesBypassingCache(t *testing.T) {
+	shell := executor.NewMock()
+	brain := &answers{AI: ai.NewMockAI(), messages: []string{"feat(#41): add feature", "feat(#41): add cache"}}
+	responses, err := cache.NewFileCache(filepath.Join(t.TempDir(), "responses.js"))
+	require.NoError(t, err, "failed to create cache")
+	editor := output.NewMock()
+	editor.Hints = []string{""}
+	raidy := &real{git: git.NewMockWithShell(shell), ai: ai.NewCached(brain, responses, nil, "deepseek", "en", time.Hour, false), logger: log.NewMock(), github: github.NewMock(), cache: cache.NewMockAidyCache(), texteditor: editor}
+
+	err = raidy.Commit(false, true, false, nil)
+
+	require.NoError(t, err, "expected no error when committing changes")
+	assert.Len(t, brain.hints, 2, "Expected AI to be asked again without a hint")
+	assert.Contains(t, shell.Commands[3], "git commit -m feat(#41): add cache", "Expected the regenerated message")
+}
+
+func TestReal_Commit_CancelRestoresIndex(t *testing.T) {
+	shell := executor.NewMock()
+	shell.Output = "4b825dc642cb6eb9a060e54bf8d69288fbee4904\n"
+	editor := output.NewMock()
+	editor.EditErr = output.ErrCanceled
+	raidy := &real{git: git.NewMockWithShell(shell), ai: ai.NewMockAI(), logger: log.NewMock(), github: github.NewMock(), cache: cache.NewMockAidyCache(), texteditor: editor, review: true}
+
+	err := raidy.Commit(false, false, false, nil)
+
+	require.NoError(t, err, "expected no error when the commit is canceled")
+	require.Len(t, shell.Commands, 4, "Expected nothing to be committed")
+	assert.Equal(t, "git read-tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904", shell.Commands[3], "Expected the index to be restored")
+}
+
+func TestReal_Commit_AddsCoauthors(t *testing.T) {
+	shell := executor.NewMock()
+	shell.Output = "Jane Doe <jane@example.com>"
+	brain := &answers{AI: ai.NewMockAI(), messages: []string{"feat(#41): add cache\n\nAnswers are slow, so they are cached."}}
+	conv := convention.Convention{Body: true, Coauthors: true}
+	raidy := &real{git: git.NewMockWithShell(shell), ai: brain, logger: log.NewMock(), github: github.NewMock(), cache: cache.NewMockAidyCache(), convention: conv}
+
+	err := raidy.Commit(false, false, false, nil)
+
+	require.NoError(t, err, "expected no error when committing changes")
+	assert.Contains(t, shell.Commands, "git log main..HEAD --format=%an <%ae>")
+	assert.Contains(t, shell.Commands, "git commit -m feat(#41): add cache\n\nAnswers are slow, so they are cached.\n\nCo-authored-by: Jane Doe <jane@example.com>")
+}
+
+func TestReal_Heal_KeepsBody(t *testing.T) {
+	shell := executor.NewMock()
+	repo := &committed{Git: git.NewMockWithShell(shell), message: "feat(#7): add cache\n\nIt replaces the workaround from #12.\n\nRefs: #7"}
+	raidy := &real{git: repo, logger: log.NewMock()}
+
+	err := raidy.Heal()
+
+	require.NoError(t, err, "expected no error when healing the commit")
+	assert.Equal(t, "git commit --amend -m feat(#41): add cache\n\nIt replaces the workaround from #12.\n\nRefs: #41 ", shell.Commands[0])
+}
+
+// committed is a repository with the given last commit message.
+type committed struct {
+	git.Git
+	message string
+}
+
+func (c *committed) CommitMessage() (string, error) {
+	return c.message, nil
+}
+
+func TestReal_Commit_Staged(t *testing.T) {
+	shell := executor.NewMock()
+	shell.Output = "diff --git a/staged.go b/staged.go"
+	unchecked := false
+	raidy := &real{git: git.NewMockWithShell(shell), ai: ai.NewMockAI(), logger: log.NewMock(), github: github.NewMock(), cache: cache.NewMockAidyCache(), convention: convention.Convention{Check: &unchecked}}
+
+	err := raidy.Commit(false, false, true, nil)
+
+	require.NoError(t, err, "expected no error when committing staged changes")
+	assert.Equal(t, "git diff --cached", shell.Commands[0], "Expected the staged diff to be used")
+	assert.Equal(t, "git commit -m feat(#41): changed files: staged.go", shell.Commands[1], "Expected the index to be committed")
+	assert.NotContains(t, shell.Commands, "git add --all", "Expected nothing to be staged")
+}
+
+func TestReal_Commit_NothingStaged(t *testing.T) {
+	shell := executor.NewMock()
+	raidy := &real{git: git.NewMockWithShell(shell), ai: ai.NewMockAI(), logger: log.NewMock(), github: github.NewMock(), cache: cache.NewMockAidyCache(), staged: true}
+
+	err := raidy.Commit(false, false, false, nil)
+
+	require.Error(t, err, "expected an error when nothing is staged")
+	assert.Contains(t, err.Error(), "there are no staged changes to commit")
+}
+
+func TestReal_Commit_Paths(t *testing.T) {
+	shell := executor.NewMock()
+	shell.Output = "diff --git a/cmd/root.go b/cmd/root.go"
+	unchecked := false
+	raidy := &real{git: git.NewMockWithShell(shell), ai: ai.NewMockAI(), logger: log.NewMock(), github: github.NewMock(), cache: cache.NewMockAidyCache(), staged: true, convention: convention.Convention{Check: &unchecked}}
+
+	err := raidy.Commit(false, false, false, []string{"cmd", "README.md"})
+
+	require.NoError(t, err, "expected no error when committing paths")
+	assert.Equal(t, "git add --all -- cmd README.md", shell.Commands[0], "Expected only the paths to be staged")
+	assert.Equal(t, "git diff --cached -- cmd README.md", shell.Commands[1])
+	assert.Equal(t, "git commit -m feat(#41): changed files: cmd/root.go -- cmd README.md", shell.Commands[2], "Expected only the paths to be committed")
+}
+
+func TestReal_Commit_StagedPaths(t *testing.T) {
+	raidy := &real{git: git.NewMock(), ai: ai.NewMockAI(), logger: log.NewMock(), github: github.NewMock(), cache: cache.NewMockAidyCache()}
+
+	err := raidy.Commit(false, false, true, []string{"cmd"})
+
+	require.Error(t, err, "expected an error when staged changes are restricted to paths")
+}
+
+func TestReal_Append_Staged(t *testing.T) {
+	shell := executor.NewMock()
+	raidy := &real{git: git.NewMockWithShell(shell), logger: log.NewMock()}
+
+	raidy.Append(true)
+
+	assert.Equal(t, []string{"git commit --amend --no-edit"}, shell.Commands, "Expected only the staged changes to be appended")
+}
+
+// answers is a model that gives the commit messages one by one, repeating the last one.
+type answers struct {
+	ai.AI
+	messages []string
+	hints    []string
+}
+
+func (a *answers) CommitMessage(number, diff, descr, hint string) (string, error) {
+	a.hints = append(a.hints, hint)
+	return a.messages[min(len(a.hints), len(a.messages))-1], nil
+}
+
+func TestHealPRTitle_PlainConvention(t *testing.T) {
+	result := healPRTitle("feat(#7523): Add feature", "42", convention.Convention{Style: "plain"})
+
+	assert.Equal(t, "Add feature", result)
+}
+
 func TestHealPRTitle(t *testing.T) {
 	tests := []struct {
 		actual   string
@@ -1123,7 +1805,7 @@ func TestHealPRTitle(t *testing.T) {
 		{"test(#7523): Add clean command to clear cache", "test(#42): Add clean command to clear cache"},
 	}
 	for _, test := range tests {
-		result := healPRTitle(test.actual, "42")
+		result := healPRTitle(test.actual, "42", convention.Default())
 		assert.Equal(t, test.expected, result)
 	}
 
@@ -1137,7 +1819,7 @@ func TestHealPRTitle(t *testing.T) {
 		{"feat(#master): no change", "feat(#master): no change"},
 	}
 	for _, test := range alphanumericTests {
-		result := healPRTitle(test.actual, "PROJ-17")
+		result := healPRTitle(test.actual, "PROJ-17", convention.Default())
 		assert.Equal(t, test.expected, result)
 	}
 }
@@ -1195,3 +1877,125 @@ func TestInitLogger_DefaultMode(t *testing.T) {
 	assert.NotNil(t, logger, "Expected logger to be initialized")
 	assert.IsType(t, &log.Short{}, logger, "Expected logger to be of type Short")
 }
+
+func TestReal_Split(t *testing.T) {
+	repo := &scripted{Git: git.NewMock(), outputs: map[string]string{
+		"write-tree":                               "4b825dc\n",
+		"diff --no-renames --cached":               "diff --git a/a.go b/a.go\n@@ -1 +1 @@\n-a\n+b\ndiff --git a/b.go b/b.go\n@@ -1 +1 @@\n-c\n+d\n",
+		"diff --no-renames --name-status --cached": "M\ta.go\nM\tb.go\n",
+	}}
+	raidy := &real{git: repo, ai: ai.NewMockAI(), logger: log.NewMock(), github: github.NewMock(), cache: cache.NewMockAidyCache(), texteditor: output.NewMock()}
+
+	err := raidy.Split(false)
+
+	require.NoError(t, err, "expected no error when splitting changes")
+	assert.Equal(t, []string{
+		"write-tree",
+		"add --all",
+		"diff --no-renames --cached",
+		"diff --no-renames --name-status --cached",
+		"diff --no-renames --stat --cached",
+		"rev-parse --verify -q HEAD",
+		"reset -q",
+		"add --all -- a.go",
+		"add --all -- b.go",
+		"commit -m feat(#41): update 2 files",
+	}, repo.commands)
+}
+
+func TestReal_Split_RollsBackOnFailedCommit(t *testing.T) {
+	repo := &scripted{Git: git.NewMock(), outputs: map[string]string{
+		"write-tree":                               "4b825dc\n",
+		"rev-parse --verify -q HEAD":               "a1b2c3d\n",
+		"diff --no-renames --cached":               "diff --git a/a.go b/a.go\n@@ -1 +1 @@\n-a\n+b\ndiff --git a/b.go b/b.go\n@@ -1 +1 @@\n-c\n+d\n",
+		"diff --no-renames --name-status --cached": "M\ta.go\nM\tb.go\n",
+	}, failures: map[string]error{
+		"commit -m feat(#41): update b.go": fmt.Errorf("hook failed"),
+	}}
+	editor := output.NewMock()
+	editor.EditText = "feat(#41): update a.go\n  a.go\n\nfeat(#41): update b.go\n  b.go\n"
+	raidy := &real{git: repo, ai: ai.NewMockAI(), logger: log.NewMock(), github: github.NewMock(), cache: cache.NewMockAidyCache(), texteditor: editor}
+
+	err := raidy.Split(false)
+
+	require.Error(t, err, "expected an error when a commit fails")
+	assert.Contains(t, err.Error(), "hook failed")
+	assert.Equal(t, []string{
+		"commit -m feat(#41): update a.go",
+		"add --all -- b.go",
+		"commit -m feat(#41): update b.go",
+		"reset -q --soft a1b2c3d",
+		"read-tree 4b825dc",
+	}, repo.commands[len(repo.commands)-5:], "Expected the created commits to be undone and the index to be restored")
+}
+
+func TestReal_Split_CanceledPlan(t *testing.T) {
+	repo := &scripted{Git: git.NewMock(), outputs: map[string]string{
+		"write-tree":                               "4b825dc\n",
+		"diff --no-renames --cached":               "diff --git a/a.go b/a.go\n@@ -1 +1 @@\n-a\n+b\n",
+		"diff --no-renames --name-status --cached": "M\ta.go\n",
+	}}
+	editor := output.NewMock()
+	editor.EditErr = output.ErrCanceled
+	raidy := &real{git: repo, ai: ai.NewMockAI(), logger: log.NewMock(), github: github.NewMock(), cache: cache.NewMockAidyCache(), texteditor: editor}
+
+	err := raidy.Split(false)
+
+	require.NoError(t, err, "expected no error when the split is canceled")
+	assert.Equal(t, "read-tree 4b825dc", repo.commands[len(repo.commands)-1], "Expected the index to be restored")
+	assert.NotContains(t, repo.commands, "reset -q", "Expected nothing to be committed")
+}
+
+func TestReal_Split_NoChanges(t *testing.T) {
+	repo := &scripted{Git: git.NewMock(), outputs: map[string]string{"write-tree": "4b825dc\n"}}
+	raidy := &real{git: repo, ai: ai.NewMockAI(), logger: log.NewMock(), github: github.NewMock(), cache: cache.NewMockAidyCache(), texteditor: output.NewMock()}
+
+	err := raidy.Split(false)
+
+	require.Error(t, err, "expected an error when there is nothing to split")
+	assert.Contains(t, err.Error(), "there are no changes to split")
+	assert.Equal(t, "read-tree 4b825dc", repo.commands[len(repo.commands)-1], "Expected the index to be restored")
+}
+
+type scripted struct {
+	git.Git
+	outputs  map[string]string
+	failures map[string]error
+	commands []string
+}
+
+func (s *scripted) Run(args ...string) (string, error) {
+	command := strings.Join(args, " ")
+	s.commands = append(s.commands, command)
+	return s.outputs[command], s.failures[command]
+}
+
+func TestBudget_FitsContextWindow(t *testing.T) {
+	conf := config.NewMock()
+	conf.MockProvider = "ollama"
+	conf.MockWindow = 8000
+	conf.MockIgnore = []string{"*.snap"}
+
+	budget, err := Budget(conf)
+
+	require.NoError(t, err, "expected no error when sizing the diff")
+	assert.Equal(t, 4500, budget.Tokens, "Expected the diff to leave room for the answer and the rest of the prompt")
+	assert.Contains(t, budget.Ignore, "go.sum", "Expected lockfiles to be ignored by default")
+	assert.Contains(t, budget.Ignore, "*.snap", "Expected configured globs to be ignored")
+	assert.NotNil(t, budget.Tokenizer)
+}
+
+func TestBudget_FitsSmallestWindowOfChain(t *testing.T) {
+	conf := config.NewMock()
+	conf.MockProvider = "openai"
+	conf.MockModel = "gpt-4o"
+	fallback := config.NewMock()
+	fallback.MockProvider = "ollama"
+	fallback.MockWindow = 8000
+	conf.MockFallbacks = []config.Config{fallback}
+
+	budget, err := Budget(conf)
+
+	require.NoError(t, err, "expected no error when sizing the diff")
+	assert.Equal(t, 4500, budget.Tokens, "Expected the diff to fit the smallest window of the chain")
+}
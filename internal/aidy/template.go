@@ -0,0 +1,104 @@
+package aidy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// forms are the places of the pull and merge request templates of a repository.
+// A single template is used as is, a directory holds several of them to choose from by name.
+type forms struct {
+	single []string
+	dirs   []string
+}
+
+// githubForms are the places GitHub looks for pull request templates, in its order.
+var githubForms = forms{
+	single: []string{
+		".github/pull_request_template.md",
+		".github/PULL_REQUEST_TEMPLATE.md",
+		"pull_request_template.md",
+		"PULL_REQUEST_TEMPLATE.md",
+		"docs/pull_request_template.md",
+		"docs/PULL_REQUEST_TEMPLATE.md",
+	},
+	dirs: []string{".github/PULL_REQUEST_TEMPLATE", "PULL_REQUEST_TEMPLATE", "docs/PULL_REQUEST_TEMPLATE"},
+}
+
+// gitlabForms is the directory of GitLab merge request templates, where 'Default.md' is the default one.
+var gitlabForms = forms{dirs: []string{".gitlab/merge_request_templates"}}
+
+// form returns the content of the request template of the repository, or nothing if it has none.
+// The name picks one of several templates, e.g. 'bug' for '.gitlab/merge_request_templates/Bug.md'.
+// Without the name, the single template is used, then the only one or the default one of the directory.
+func (r *real) form(places forms, name string) (string, error) {
+	root, err := r.git.Root()
+	if err != nil {
+		return "", fmt.Errorf("error getting repository root: %v", err)
+	}
+	named := map[string]string{}
+	for _, dir := range places.dirs {
+		entries, err := os.ReadDir(filepath.Join(root, filepath.FromSlash(dir)))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".md") {
+				continue
+			}
+			key := strings.ToLower(strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())))
+			if _, ok := named[key]; !ok {
+				named[key] = filepath.Join(root, filepath.FromSlash(dir), entry.Name())
+			}
+		}
+	}
+	if name != "" {
+		path, ok := named[strings.ToLower(strings.TrimSuffix(name, filepath.Ext(name)))]
+		if !ok {
+			return "", fmt.Errorf("template '%s' not found, available templates: %s", name, names(named))
+		}
+		return read(path)
+	}
+	for _, single := range places.single {
+		if content, err := read(filepath.Join(root, filepath.FromSlash(single))); err == nil {
+			return content, nil
+		}
+	}
+	if path, ok := named["default"]; ok {
+		return read(path)
+	}
+	if len(named) == 1 {
+		for _, path := range named {
+			return read(path)
+		}
+	}
+	if len(named) > 1 {
+		r.logger.Warn("found several templates (%s), pick one with '--template'", names(named))
+	}
+	return "", nil
+}
+
+// names lists the template names in alphabetical order.
+func names(named map[string]string) string {
+	if len(named) == 0 {
+		return "none"
+	}
+	list := make([]string, 0, len(named))
+	for name := range named {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return strings.Join(list, ", ")
+}
+
+// read returns the content of the template without surrounding blank lines.
+func read(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(strings.ReplaceAll(string(content), "\r\n", "\n")), nil
+}
@@ -0,0 +1,76 @@
+package aidy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volodya-lombrozo/aidy/internal/git"
+	"github.com/volodya-lombrozo/aidy/internal/log"
+)
+
+func templates(t *testing.T, files map[string]string) *real {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return &real{git: git.NewMockWithDir(dir), logger: log.NewMock()}
+}
+
+func TestForm_ReadsSingleTemplate(t *testing.T) {
+	r := templates(t, map[string]string{".github/PULL_REQUEST_TEMPLATE.md": "\n## What\n\n- [ ] Docs\n\n"})
+
+	form, err := r.form(githubForms, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, "## What\n\n- [ ] Docs", form)
+}
+
+func TestForm_PicksTemplateByName(t *testing.T) {
+	r := templates(t, map[string]string{
+		".gitlab/merge_request_templates/Bug.md":     "## Bug",
+		".gitlab/merge_request_templates/Feature.md": "## Feature",
+	})
+
+	form, err := r.form(gitlabForms, "Feature")
+
+	require.NoError(t, err)
+	assert.Equal(t, "## Feature", form)
+}
+
+func TestForm_PrefersDefaultTemplate(t *testing.T) {
+	r := templates(t, map[string]string{
+		".gitlab/merge_request_templates/Bug.md":     "## Bug",
+		".gitlab/merge_request_templates/Default.md": "## Default",
+	})
+
+	form, err := r.form(gitlabForms, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, "## Default", form)
+}
+
+func TestForm_SkipsAmbiguousTemplates(t *testing.T) {
+	r := templates(t, map[string]string{
+		".github/PULL_REQUEST_TEMPLATE/bug.md":     "## Bug",
+		".github/PULL_REQUEST_TEMPLATE/feature.md": "## Feature",
+	})
+
+	form, err := r.form(githubForms, "")
+
+	require.NoError(t, err)
+	assert.Empty(t, form, "Expected no template when it's unclear which one to use")
+}
+
+func TestForm_NoTemplates(t *testing.T) {
+	r := templates(t, map[string]string{"README.md": "# Project"})
+
+	form, err := r.form(githubForms, "")
+
+	require.NoError(t, err)
+	assert.Empty(t, form)
+}
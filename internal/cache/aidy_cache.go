@@ -6,9 +6,9 @@ import (
 
 type AidyCache interface {
 	Remote() string
-	WithRemote(string)
+	WithRemote(string) error
 	Summary() (string, string)
-	WithSummary(string, string)
+	WithSummary(string, string) error
 }
 
 type aidyCache struct {
@@ -28,11 +28,11 @@ func (a *aidyCache) Remote() string {
 	}
 }
 
-func (a *aidyCache) WithRemote(remote string) {
-	err := a.ch.Set("target", remote)
-	if err != nil {
-		panic(fmt.Errorf("can't save the project remote address, because '%v'", err))
+func (a *aidyCache) WithRemote(remote string) error {
+	if err := a.ch.Set("target", remote); err != nil {
+		return fmt.Errorf("can't save the project remote address, because '%v'", err)
 	}
+	return nil
 }
 
 func (a *aidyCache) Summary() (string, string) {
@@ -47,15 +47,14 @@ func (a *aidyCache) Summary() (string, string) {
 	return summary, hash
 }
 
-func (a *aidyCache) WithSummary(summary string, hash string) {
-	err := a.ch.Set("summary", summary)
-	if err != nil {
-		panic(fmt.Errorf("can't save the project summary, because '%v'", err))
+func (a *aidyCache) WithSummary(summary string, hash string) error {
+	if err := a.ch.Set("summary", summary); err != nil {
+		return fmt.Errorf("can't save the project summary, because '%v'", err)
 	}
-	err = a.ch.Set("summary-hash", hash)
-	if err != nil {
-		panic(fmt.Errorf("can't save the project summary hash, because '%v'", err))
+	if err := a.ch.Set("summary-hash", hash); err != nil {
+		return fmt.Errorf("can't save the project summary hash, because '%v'", err)
 	}
+	return nil
 }
 
 type mockAidyCache struct {
@@ -74,15 +73,17 @@ func (a *mockAidyCache) Remote() string {
 	return a.inner["target"]
 }
 
-func (a *mockAidyCache) WithRemote(remote string) {
+func (a *mockAidyCache) WithRemote(remote string) error {
 	a.inner["target"] = remote
+	return nil
 }
 
 func (a *mockAidyCache) Summary() (string, string) {
 	return a.inner["summary"], a.inner["summary-hash"]
 }
 
-func (a *mockAidyCache) WithSummary(summary, hash string) {
+func (a *mockAidyCache) WithSummary(summary, hash string) error {
 	a.inner["summary"] = summary
 	a.inner["summary-hash"] = hash
+	return nil
 }
@@ -22,6 +22,19 @@ func (m *mapCache) Set(key, value string) error {
 	return nil
 }
 
+func (m *mapCache) Delete(key string) error {
+	delete(m.store, key)
+	return nil
+}
+
+func (m *mapCache) Keys() []string {
+	keys := make([]string, 0, len(m.store))
+	for key := range m.store {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
 type errorCache struct {
 }
 
@@ -33,12 +46,20 @@ func (e *errorCache) Set(key, value string) error {
 	return fmt.Errorf("simulated error")
 }
 
+func (e *errorCache) Delete(key string) error {
+	return fmt.Errorf("simulated error")
+}
+
+func (e *errorCache) Keys() []string {
+	return nil
+}
+
 func TestAidyCache_WithRemote_Error(t *testing.T) {
 	ac := NewAidyCache(&errorCache{})
 
-	assert.Panics(t, func() {
-		ac.WithRemote("https://example.com/repo.git")
-	}, "expected panic due to error in Set")
+	err := ac.WithRemote("https://example.com/repo.git")
+
+	assert.ErrorContains(t, err, "can't save the project remote address", "expected error due to error in Set")
 }
 
 func TestAidyCache_Summary_Error(t *testing.T) {
@@ -53,9 +74,9 @@ func TestAidyCache_Summary_Error(t *testing.T) {
 func TestAidyCache_WithSummary_Error(t *testing.T) {
 	ac := NewAidyCache(&errorCache{})
 
-	assert.Panics(t, func() {
-		ac.WithSummary("Project Summary", "Project Summary Hash")
-	}, "expected panic due to error in Set")
+	err := ac.WithSummary("Project Summary", "Project Summary Hash")
+
+	assert.ErrorContains(t, err, "can't save the project summary", "expected error due to error in Set")
 }
 
 func TestMockAidyCache_Remote(t *testing.T) {
@@ -66,7 +87,7 @@ func TestMockAidyCache_Remote(t *testing.T) {
 
 func TestMockAidyCache_WithRemote(t *testing.T) {
 	mc := NewMockAidyCache()
-	mc.WithRemote("new/remote")
+	require.NoError(t, mc.WithRemote("new/remote"))
 	remote := mc.Remote()
 	assert.Equal(t, "new/remote", remote, "expected remote to be 'mock/remote'")
 }
@@ -80,7 +101,7 @@ func TestMockAidyCache_Summary(t *testing.T) {
 
 func TestMockAidyCache_WithSummary(t *testing.T) {
 	mc := NewMockAidyCache()
-	mc.WithSummary("new summary", "new hash") // Should not change anything
+	require.NoError(t, mc.WithSummary("new summary", "new hash"))
 	summary, hash := mc.Summary()
 	assert.Equal(t, "new summary", summary, "expected summary to be 'new summary'")
 	assert.Equal(t, "new hash", hash, "expected hash to be 'new hash'")
@@ -105,7 +126,7 @@ func TestAidyCache_WithRemote(t *testing.T) {
 	mc := &mapCache{store: make(map[string]string)}
 	ac := NewAidyCache(mc)
 
-	ac.WithRemote("https://example.com/repo.git")
+	require.NoError(t, ac.WithRemote("https://example.com/repo.git"))
 	remote, ok := mc.Get("target")
 	assert.True(t, ok)
 	assert.Equal(t, "https://example.com/repo.git", remote, "expected remote to be 'https://example.com/repo.git'")
@@ -136,7 +157,7 @@ func TestAidyCache_WithSummary(t *testing.T) {
 	mc := &mapCache{store: make(map[string]string)}
 	ac := NewAidyCache(mc)
 
-	ac.WithSummary("Project Summary", "Project Summary Hash")
+	require.NoError(t, ac.WithSummary("Project Summary", "Project Summary Hash"))
 	summary, _ := mc.Get("summary")
 	assert.Equal(t, "Project Summary", summary, "expected summary to be 'Project Summary'")
 	hash, _ := mc.Get("summary-hash")
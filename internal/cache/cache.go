@@ -3,4 +3,6 @@ package cache
 type Cache interface {
 	Get(key string) (string, bool)
 	Set(key, value string) error
+	Delete(key string) error
+	Keys() []string
 }
@@ -1,12 +1,28 @@
 package cache
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 )
 
+// schema is the version of the cache file format written by this aidy.
+// Version 1 was a plain JSON object of keys and values, such files are migrated when read.
+const schema = 2
+
+// document is the content of a cache file.
+type document struct {
+	Version int               `json:"version"`
+	Entries map[string]string `json:"entries"`
+}
+
+// fileCache keeps entries in a JSON file shared by all aidy processes of the repository.
+// Each change locks the file, merges the entries written by others since it was read,
+// and replaces the file at once, so readers never see it half-written.
 type fileCache struct {
 	mu    sync.RWMutex
 	path  string
@@ -15,19 +31,11 @@ type fileCache struct {
 
 func NewFileCache(path string) (Cache, error) {
 	path = filepath.FromSlash(path)
-	fcache := &fileCache{path: path, store: map[string]string{}}
-	file, err := os.Open(path)
+	store, err := load(path)
 	if err != nil {
-		if !os.IsNotExist(err) {
-			return nil, err
-		}
-	} else {
-		_ = json.NewDecoder(file).Decode(&fcache.store)
-		if cerr := file.Close(); cerr != nil {
-			return nil, cerr
-		}
+		return nil, err
 	}
-	return fcache, nil
+	return &fileCache{path: path, store: store}, nil
 }
 
 func (c *fileCache) Get(key string) (string, bool) {
@@ -38,26 +46,128 @@ func (c *fileCache) Get(key string) (string, bool) {
 }
 
 func (c *fileCache) Set(key, value string) error {
+	return c.update(func(store map[string]string) {
+		store[key] = value
+	})
+}
+
+func (c *fileCache) Delete(key string) error {
+	return c.update(func(store map[string]string) {
+		delete(store, key)
+	})
+}
+
+// Keys returns the keys of all entries in alphabetical order.
+func (c *fileCache) Keys() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	keys := make([]string, 0, len(c.store))
+	for key := range c.store {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// update applies the change to the latest entries of the file while holding its lock.
+func (c *fileCache) update(change func(store map[string]string)) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.store[key] = value
-	return c.save()
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	lock, err := os.OpenFile(c.path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("can't open the lock of cache %s: %w", c.path, err)
+	}
+	defer func() { _ = lock.Close() }()
+	if err = acquire(lock); err != nil {
+		return fmt.Errorf("can't lock cache %s: %w", c.path, err)
+	}
+	defer func() { _ = release(lock) }()
+	store, err := load(c.path)
+	if err != nil {
+		return err
+	}
+	change(store)
+	if err = save(c.path, store); err != nil {
+		return err
+	}
+	c.store = store
+	return nil
 }
 
-func (c *fileCache) save() error {
-	dir := filepath.Dir(c.path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return err
+// load reads the entries of the cache file, an absent file has none.
+func load(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+	store, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("can't read cache %s, run 'aidy clean' to reset it: %w", path, err)
+	}
+	return store, nil
+}
+
+// decode parses the cache file of any known version.
+func decode(data []byte) (map[string]string, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return map[string]string{}, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	var doc document
+	if err := json.Unmarshal(fields["version"], &doc.Version); err != nil || doc.Version == 0 {
+		return migrate(data)
+	}
+	if doc.Version > schema {
+		return nil, fmt.Errorf("version %d is newer than %d supported by this aidy, please update it", doc.Version, schema)
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
 	}
-	f, err := os.Create(c.path)
+	if doc.Entries == nil {
+		doc.Entries = map[string]string{}
+	}
+	return doc.Entries, nil
+}
+
+// migrate reads the entries of the version 1 file, they are saved in the current version on the next change.
+func migrate(data []byte) (map[string]string, error) {
+	store := map[string]string{}
+	if err := json.Unmarshal(data, &store); err != nil {
+		return nil, fmt.Errorf("unknown format: %w", err)
+	}
+	return store, nil
+}
+
+// save writes the entries to a temporary file next to the cache and renames it to the cache.
+func save(path string, store map[string]string) error {
+	data, err := json.MarshalIndent(document{Version: schema, Entries: store}, "", "  ")
 	if err != nil {
 		return err
 	}
-	enc := json.NewEncoder(f)
-	enc.SetIndent("", "  ")
-	err = enc.Encode(c.store)
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
 	if err != nil {
 		return err
 	}
-	return f.Close()
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err = tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
 }
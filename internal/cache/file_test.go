@@ -1,9 +1,11 @@
 package cache
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -42,14 +44,15 @@ func TestFileCache_FileCreateError(t *testing.T) {
 }
 
 func TestFileCache_FileWriteError(t *testing.T) {
-	tmp := temp(t)
-	defer clean(t, tmp)
-
-	cache, err := NewFileCache(tmp)
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping permission test on Windows")
+	}
+	dir := t.TempDir()
+	cache, err := NewFileCache(filepath.Join(dir, "cache.js"))
 	require.NoError(t, err, "Failed to create cache")
 
-	chmod(t, tmp, 0444)
-	defer chmod(t, tmp, 0644)
+	chmod(t, dir, 0555)
+	defer chmod(t, dir, 0755)
 
 	err = cache.Set("key", "value")
 	assert.Error(t, err, "Expected an error when the file cannot be written to")
@@ -82,6 +85,81 @@ func TestFileCache_Overwrites(t *testing.T) {
 	assert.Equal(t, "v2", val, "Expected value to be overwritten to 'v2'")
 }
 
+func TestFileCache_DeletesKeys(t *testing.T) {
+	tmp := temp(t)
+	defer clean(t, tmp)
+	cache, _ := NewFileCache(tmp)
+	_ = cache.Set("b", "2")
+	_ = cache.Set("a", "1")
+
+	err := cache.Delete("b")
+
+	require.NoError(t, err, "Failed to delete key")
+	assert.Equal(t, []string{"a"}, cache.Keys(), "Expected only the remaining key")
+	reopened, _ := NewFileCache(tmp)
+	assert.Equal(t, []string{"a"}, reopened.Keys(), "Expected the deletion to be saved")
+}
+
+func TestFileCache_MigratesFirstVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.js")
+	require.NoError(t, os.WriteFile(path, []byte(`{"target": "a/b", "version": "v1"}`), 0644))
+
+	cache, err := NewFileCache(path)
+	require.NoError(t, err, "Expected the old cache to be read")
+	require.NoError(t, cache.Set("summary", "text"))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"version": 2, "entries": {"target": "a/b", "version": "v1", "summary": "text"}}`, string(content))
+}
+
+func TestFileCache_RejectsNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.js")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 3, "entries": {}}`), 0644))
+
+	_, err := NewFileCache(path)
+
+	assert.ErrorContains(t, err, "version 3 is newer than 2")
+}
+
+func TestFileCache_ReportsCorruptedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.js")
+	require.NoError(t, os.WriteFile(path, []byte(`{"target": `), 0644))
+
+	_, err := NewFileCache(path)
+
+	assert.ErrorContains(t, err, "run 'aidy clean' to reset it")
+}
+
+func TestFileCache_KeepsChangesOfOtherInstances(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.js")
+	first, err := NewFileCache(path)
+	require.NoError(t, err)
+	second, err := NewFileCache(path)
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			each := first
+			if i%2 == 1 {
+				each = second
+			}
+			assert.NoError(t, each.Set(fmt.Sprintf("key-%02d", i), "value"))
+		}()
+	}
+	wg.Wait()
+
+	reopened, err := NewFileCache(path)
+
+	require.NoError(t, err)
+	assert.Len(t, reopened.Keys(), 20, "Expected no change to be lost")
+	files, err := filepath.Glob(path + ".*.tmp")
+	require.NoError(t, err)
+	assert.Empty(t, files, "Expected no temporary files to be left")
+}
+
 func TestFileCache_DoesNotCreateFileWhenNoDataWritten(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "cache.json")
 
@@ -124,7 +202,9 @@ func chmod(t *testing.T, path string, mode os.FileMode) {
 
 func clean(t *testing.T, path string) {
 	t.Helper()
-	if err := os.RemoveAll(path); err != nil && !os.IsNotExist(err) {
-		t.Fatalf("Failed to remove file %s: %v", path, err)
+	for _, each := range []string{path, path + ".lock"} {
+		if err := os.RemoveAll(each); err != nil && !os.IsNotExist(err) {
+			t.Fatalf("Failed to remove file %s: %v", each, err)
+		}
 	}
 }
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"bufio"
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -40,18 +41,26 @@ func (c *gitCache) Set(key, value string) error {
 	return c.delegate.Set(key, value)
 }
 
-func ensureIgnored(gs git.Git) error {
+func (c *gitCache) Delete(key string) error {
+	return c.delegate.Delete(key)
+}
+
+func (c *gitCache) Keys() []string {
+	return c.delegate.Keys()
+}
+
+func ensureIgnored(gs git.Git) (err error) {
 	const entry = ".aidy/"
 	root, _ := gs.Root()
 	gitignore := filepath.Join(root, ".gitignore")
 	file, err := os.Open(gitignore)
-	defer fclose(file)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return os.WriteFile(gitignore, []byte(entry+"\n"), 0644)
 		}
 		return err
 	}
+	defer fclose(file, &err)
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		if scanner.Text() == entry {
@@ -63,20 +72,17 @@ func ensureIgnored(gs git.Git) error {
 		return err
 	}
 	f, err := os.OpenFile(gitignore, os.O_APPEND|os.O_WRONLY, 0644)
-	defer fclose(f)
 	if err != nil {
 		return err
 	}
+	defer fclose(f, &err)
 	_, err = f.WriteString(entry + "\n")
 	return err
 }
 
-func fclose(file *os.File) {
-	if file == nil {
-		return
-	}
-	err := file.Close()
-	if err != nil {
-		panic("Failed to close file: " + err.Error())
+// fclose closes the file and reports the failure in err, unless there is an error already.
+func fclose(file *os.File, err *error) {
+	if cerr := file.Close(); cerr != nil && *err == nil {
+		*err = fmt.Errorf("failed to close file: %w", cerr)
 	}
 }
@@ -0,0 +1,14 @@
+//go:build !unix && !windows
+
+package cache
+
+import "os"
+
+// acquire does nothing where files can't be locked, changes of parallel processes may be lost there.
+func acquire(file *os.File) error {
+	return nil
+}
+
+func release(file *os.File) error {
+	return nil
+}
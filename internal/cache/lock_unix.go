@@ -0,0 +1,18 @@
+//go:build unix
+
+package cache
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// acquire waits for an exclusive advisory lock of the file, held by other aidy processes.
+func acquire(file *os.File) error {
+	return unix.Flock(int(file.Fd()), unix.LOCK_EX)
+}
+
+func release(file *os.File) error {
+	return unix.Flock(int(file.Fd()), unix.LOCK_UN)
+}
@@ -14,3 +14,11 @@ func (c *mockCache) Get(key string) (string, bool) {
 func (c *mockCache) Set(key, value string) error {
 	return nil
 }
+
+func (c *mockCache) Delete(key string) error {
+	return nil
+}
+
+func (c *mockCache) Keys() []string {
+	return nil
+}
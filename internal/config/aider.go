@@ -1,8 +1,12 @@
 package config
 
 import (
-	"gopkg.in/yaml.v3"
 	"os"
+	"time"
+
+	"github.com/volodya-lombrozo/aidy/internal/convention"
+	"github.com/volodya-lombrozo/aidy/internal/git"
+	"gopkg.in/yaml.v3"
 )
 
 type AiderConfig struct {
@@ -27,10 +31,94 @@ func (c *AiderConfig) GithubKey() (string, error) {
 	return "", nil
 }
 
+func (c *AiderConfig) GitlabKey() (string, error) {
+	return "", nil
+}
+
+func (c *AiderConfig) GitlabUrl() (string, error) {
+	return gitlabDefaultUrl, nil
+}
+
+func (c *AiderConfig) IssueTracker() (string, error) {
+	return "", nil
+}
+
+func (c *AiderConfig) JiraUrl() (string, error) {
+	return "", nil
+}
+
+func (c *AiderConfig) JiraUser() (string, error) {
+	return "", nil
+}
+
+func (c *AiderConfig) JiraKey() (string, error) {
+	return "", nil
+}
+
+func (c *AiderConfig) Timeout() (time.Duration, error) {
+	return defaultTimeout, nil
+}
+
+func (c *AiderConfig) Retries() (int, error) {
+	return defaultRetries, nil
+}
+
+func (c *AiderConfig) Fallbacks() ([]Config, error) {
+	return nil, nil
+}
+
+func (c *AiderConfig) Convention() (convention.Convention, error) {
+	return convention.Default(), nil
+}
+
+func (c *AiderConfig) Review() (bool, error) {
+	return false, nil
+}
+
+func (c *AiderConfig) Staged() (bool, error) {
+	return false, nil
+}
+
+func (c *AiderConfig) Ignore() ([]string, error) {
+	return nil, nil
+}
+
+func (c *AiderConfig) ContextWindow() (int, error) {
+	return 0, nil
+}
+
+func (c *AiderConfig) Workers() (int, error) {
+	return 0, nil
+}
+
+func (c *AiderConfig) CacheTTL() (time.Duration, error) {
+	return 0, nil
+}
+
+func (c *AiderConfig) BaseBranch() (string, error) {
+	return "", nil
+}
+
+func (c *AiderConfig) DiffMode() (git.Mode, error) {
+	return git.Committed, nil
+}
+
 func (c *AiderConfig) Model() (string, error) {
 	return c.ModelYaml, nil
 }
 
+func (c *AiderConfig) BaseUrl() (string, error) {
+	return "", nil
+}
+
+func (c *AiderConfig) ApiVersion() (string, error) {
+	return "", nil
+}
+
+func (c *AiderConfig) Headers() (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
 func (c *AiderConfig) Provider() (string, error) {
 	return "openai", nil
 }
@@ -41,6 +41,18 @@ func TestAider_GithubKey(t *testing.T) {
 	assert.Equal(t, "", key, "Github key should match")
 }
 
+func TestAider_Gitlab(t *testing.T) {
+	config := &AiderConfig{}
+
+	key, err := config.GitlabKey()
+	require.NoError(t, err, "Error should be nil")
+	url, err := config.GitlabUrl()
+	require.NoError(t, err, "Error should be nil")
+
+	assert.Equal(t, "", key, "Gitlab key should be empty")
+	assert.Equal(t, "https://gitlab.com", url, "Gitlab URL should be the default one")
+}
+
 func TestAider_Provider(t *testing.T) {
 	tmp := t.TempDir()
 	path := tmp + "/config.yml"
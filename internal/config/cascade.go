@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/volodya-lombrozo/aidy/internal/convention"
 	"github.com/volodya-lombrozo/aidy/internal/git"
 )
 
@@ -31,10 +33,94 @@ func (c *CascadeConfig) GithubKey() (string, error) {
 	return c.original.GithubKey()
 }
 
+func (c *CascadeConfig) GitlabKey() (string, error) {
+	return c.original.GitlabKey()
+}
+
+func (c *CascadeConfig) GitlabUrl() (string, error) {
+	return c.original.GitlabUrl()
+}
+
+func (c *CascadeConfig) IssueTracker() (string, error) {
+	return c.original.IssueTracker()
+}
+
+func (c *CascadeConfig) JiraUrl() (string, error) {
+	return c.original.JiraUrl()
+}
+
+func (c *CascadeConfig) JiraUser() (string, error) {
+	return c.original.JiraUser()
+}
+
+func (c *CascadeConfig) JiraKey() (string, error) {
+	return c.original.JiraKey()
+}
+
+func (c *CascadeConfig) Timeout() (time.Duration, error) {
+	return c.original.Timeout()
+}
+
+func (c *CascadeConfig) Retries() (int, error) {
+	return c.original.Retries()
+}
+
+func (c *CascadeConfig) Fallbacks() ([]Config, error) {
+	return c.original.Fallbacks()
+}
+
+func (c *CascadeConfig) Convention() (convention.Convention, error) {
+	return c.original.Convention()
+}
+
+func (c *CascadeConfig) Review() (bool, error) {
+	return c.original.Review()
+}
+
+func (c *CascadeConfig) Staged() (bool, error) {
+	return c.original.Staged()
+}
+
+func (c *CascadeConfig) Ignore() ([]string, error) {
+	return c.original.Ignore()
+}
+
+func (c *CascadeConfig) ContextWindow() (int, error) {
+	return c.original.ContextWindow()
+}
+
+func (c *CascadeConfig) Workers() (int, error) {
+	return c.original.Workers()
+}
+
+func (c *CascadeConfig) CacheTTL() (time.Duration, error) {
+	return c.original.CacheTTL()
+}
+
+func (c *CascadeConfig) BaseBranch() (string, error) {
+	return c.original.BaseBranch()
+}
+
+func (c *CascadeConfig) DiffMode() (git.Mode, error) {
+	return c.original.DiffMode()
+}
+
 func (c *CascadeConfig) Model() (string, error) {
 	return c.original.Model()
 }
 
+func (c *CascadeConfig) BaseUrl() (string, error) {
+	return c.original.BaseUrl()
+}
+
+func (c *CascadeConfig) ApiVersion() (string, error) {
+	return c.original.ApiVersion()
+}
+
+func (c *CascadeConfig) Headers() (map[string]string, error) {
+	return c.original.Headers()
+}
+
 func (c *CascadeConfig) Provider() (string, error) {
 	return c.original.Provider()
 }
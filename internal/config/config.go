@@ -1,8 +1,36 @@
 package config
 
+import (
+	"time"
+
+	"github.com/volodya-lombrozo/aidy/internal/convention"
+	"github.com/volodya-lombrozo/aidy/internal/git"
+)
+
 type Config interface {
 	Provider() (string, error)
 	Model() (string, error)
+	BaseUrl() (string, error)
+	ApiVersion() (string, error)
+	Headers() (map[string]string, error)
 	Token() (string, error)
 	GithubKey() (string, error)
+	GitlabKey() (string, error)
+	GitlabUrl() (string, error)
+	IssueTracker() (string, error)
+	JiraUrl() (string, error)
+	JiraUser() (string, error)
+	JiraKey() (string, error)
+	Timeout() (time.Duration, error)
+	Retries() (int, error)
+	Fallbacks() ([]Config, error)
+	Convention() (convention.Convention, error)
+	Review() (bool, error)
+	Staged() (bool, error)
+	Ignore() ([]string, error)
+	ContextWindow() (int, error)
+	Workers() (int, error)
+	CacheTTL() (time.Duration, error)
+	BaseBranch() (string, error)
+	DiffMode() (git.Mode, error)
 }
@@ -1,19 +1,51 @@
 package config
 
+import (
+	"time"
+
+	"github.com/volodya-lombrozo/aidy/internal/convention"
+	"github.com/volodya-lombrozo/aidy/internal/git"
+)
+
 type MockConfig struct {
-	MockGithub   string
-	MockModel    string
-	Error        error
-	MockToken    string
-	MockProvider string
+	MockGithub     string
+	MockGitlab     string
+	MockGitlabUrl  string
+	MockTracker    string
+	MockJiraUrl    string
+	MockJiraUser   string
+	MockJira       string
+	MockTimeout    time.Duration
+	MockRetries    int
+	MockFallbacks  []Config
+	MockConvention convention.Convention
+	MockReview     bool
+	MockStaged     bool
+	MockIgnore     []string
+	MockWindow     int
+	MockWorkers    int
+	MockTTL        time.Duration
+	MockBase       string
+	MockMode       git.Mode
+	MockModel      string
+	MockBaseUrl    string
+	MockVersion    string
+	MockHeaders    map[string]string
+	Error          error
+	MockToken      string
+	MockProvider   string
 }
 
 func NewMock() *MockConfig {
 	return &MockConfig{
-		MockGithub:   "mock-github-key",
-		MockModel:    "gpt-4o",
-		MockToken:    "mock-token",
-		MockProvider: "openai",
+		MockGithub:    "mock-github-key",
+		MockGitlab:    "mock-gitlab-key",
+		MockGitlabUrl: "https://gitlab.com",
+		MockTimeout:   time.Minute,
+		MockRetries:   3,
+		MockModel:     "gpt-4o",
+		MockToken:     "mock-token",
+		MockProvider:  "openai",
 	}
 }
 
@@ -21,10 +53,94 @@ func (m *MockConfig) GithubKey() (string, error) {
 	return m.MockGithub, m.Error
 }
 
+func (m *MockConfig) GitlabKey() (string, error) {
+	return m.MockGitlab, m.Error
+}
+
+func (m *MockConfig) GitlabUrl() (string, error) {
+	return m.MockGitlabUrl, m.Error
+}
+
+func (m *MockConfig) IssueTracker() (string, error) {
+	return m.MockTracker, m.Error
+}
+
+func (m *MockConfig) JiraUrl() (string, error) {
+	return m.MockJiraUrl, m.Error
+}
+
+func (m *MockConfig) JiraUser() (string, error) {
+	return m.MockJiraUser, m.Error
+}
+
+func (m *MockConfig) JiraKey() (string, error) {
+	return m.MockJira, m.Error
+}
+
+func (m *MockConfig) Timeout() (time.Duration, error) {
+	return m.MockTimeout, m.Error
+}
+
+func (m *MockConfig) Retries() (int, error) {
+	return m.MockRetries, m.Error
+}
+
+func (m *MockConfig) Fallbacks() ([]Config, error) {
+	return m.MockFallbacks, m.Error
+}
+
+func (m *MockConfig) Convention() (convention.Convention, error) {
+	return m.MockConvention, m.Error
+}
+
+func (m *MockConfig) Review() (bool, error) {
+	return m.MockReview, m.Error
+}
+
+func (m *MockConfig) Staged() (bool, error) {
+	return m.MockStaged, m.Error
+}
+
+func (m *MockConfig) Ignore() ([]string, error) {
+	return m.MockIgnore, m.Error
+}
+
+func (m *MockConfig) ContextWindow() (int, error) {
+	return m.MockWindow, m.Error
+}
+
+func (m *MockConfig) Workers() (int, error) {
+	return m.MockWorkers, m.Error
+}
+
+func (m *MockConfig) CacheTTL() (time.Duration, error) {
+	return m.MockTTL, m.Error
+}
+
+func (m *MockConfig) BaseBranch() (string, error) {
+	return m.MockBase, m.Error
+}
+
+func (m *MockConfig) DiffMode() (git.Mode, error) {
+	return m.MockMode, m.Error
+}
+
 func (m *MockConfig) Model() (string, error) {
 	return m.MockModel, m.Error
 }
 
+func (m *MockConfig) BaseUrl() (string, error) {
+	return m.MockBaseUrl, m.Error
+}
+
+func (m *MockConfig) ApiVersion() (string, error) {
+	return m.MockVersion, m.Error
+}
+
+func (m *MockConfig) Headers() (map[string]string, error) {
+	return m.MockHeaders, m.Error
+}
+
 func (m *MockConfig) Provider() (string, error) {
 	return m.MockProvider, m.Error
 }
@@ -18,6 +18,34 @@ func TestMock_GithubKey(t *testing.T) {
 	assert.Equal(t, expected, key, "Expected GitHub API key to match the mock value")
 }
 
+func TestMock_Gitlab(t *testing.T) {
+	conf := NewMock()
+	conf.MockGitlab = "gitlab-api-key"
+	conf.MockGitlabUrl = "https://gitlab.example.com"
+
+	key, err := conf.GitlabKey()
+	require.NoError(t, err, "Expected no error when getting GitLab API key")
+	url, err := conf.GitlabUrl()
+	require.NoError(t, err, "Expected no error when getting GitLab URL")
+
+	assert.Equal(t, "gitlab-api-key", key, "Expected GitLab API key to match the mock value")
+	assert.Equal(t, "https://gitlab.example.com", url, "Expected GitLab URL to match the mock value")
+}
+
+func TestMock_Jira(t *testing.T) {
+	conf := NewMock()
+	conf.MockTracker = "jira"
+	conf.MockJiraUrl = "https://example.atlassian.net"
+
+	tracker, err := conf.IssueTracker()
+	require.NoError(t, err, "Expected no error when getting issue tracker")
+	url, err := conf.JiraUrl()
+	require.NoError(t, err, "Expected no error when getting Jira URL")
+
+	assert.Equal(t, "jira", tracker, "Expected issue tracker to match the mock value")
+	assert.Equal(t, "https://example.atlassian.net", url, "Expected Jira URL to match the mock value")
+}
+
 func TestMock_Model(t *testing.T) {
 	expected := "gpt-4o"
 	conf := NewMock()
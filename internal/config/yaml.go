@@ -1,17 +1,48 @@
 package config
 
 import (
-	"gopkg.in/yaml.v3"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/volodya-lombrozo/aidy/internal/convention"
+	"github.com/volodya-lombrozo/aidy/internal/git"
+	"gopkg.in/yaml.v3"
 )
 
 type YamlConfig struct {
-	DefaultModel string                       `yaml:"default-model"`
-	APIKeys      map[string]string            `yaml:"api-keys"`
-	Models       map[string]map[string]string `yaml:"models"`
-	Github       string                       `yaml:"github-api-key"`
+	DefaultModel     string                       `yaml:"default-model"`
+	APIKeys          map[string]string            `yaml:"api-keys"`
+	Models           map[string]map[string]string `yaml:"models"`
+	Github           string                       `yaml:"github-api-key"`
+	GitlabAddress    string                       `yaml:"gitlab-url,omitempty"`
+	TrackerName      string                       `yaml:"issue-tracker,omitempty"`
+	JiraAddress      string                       `yaml:"jira-url,omitempty"`
+	JiraUsername     string                       `yaml:"jira-user,omitempty"`
+	TimeoutValue     string                       `yaml:"timeout,omitempty"`
+	RetryCount       *int                         `yaml:"retries,omitempty"`
+	FallbackModels   []string                     `yaml:"fallback-models,omitempty"`
+	CommitConvention *convention.Convention       `yaml:"convention,omitempty"`
+	ReviewCommits    bool                         `yaml:"review,omitempty"`
+	StagedOnly       bool                         `yaml:"staged,omitempty"`
+	IgnoredFiles     []string                     `yaml:"ignore,omitempty"`
+	WorkerCount      int                          `yaml:"workers,omitempty"`
+	CacheTTLValue    string                       `yaml:"cache-ttl,omitempty"`
+	BaseBranchName   string                       `yaml:"base-branch,omitempty"`
+	DiffModeName     string                       `yaml:"diff-mode,omitempty"`
 }
 
+const gitlabDefaultUrl = "https://gitlab.com"
+
+const defaultTimeout = 2 * time.Minute
+
+const defaultRetries = 3
+
+// headerPrefix marks model keys that are sent as HTTP headers, e.g. 'header-X-Title: aidy'.
+const headerPrefix = "header-"
+
 func YamlConf(filepath string) (*YamlConfig, error) {
 	data, err := os.ReadFile(filepath)
 	if err != nil {
@@ -41,11 +72,190 @@ func (c *YamlConfig) GithubKey() (string, error) {
 	return c.APIKeys["github"], nil
 }
 
+func (c *YamlConfig) GitlabKey() (string, error) {
+	return c.APIKeys["gitlab"], nil
+}
+
+// GitlabUrl returns the base URL of the GitLab instance, e.g. a self-hosted one.
+// If it isn't configured, gitlab.com is used.
+func (c *YamlConfig) GitlabUrl() (string, error) {
+	if c.GitlabAddress == "" {
+		return gitlabDefaultUrl, nil
+	}
+	return strings.TrimRight(c.GitlabAddress, "/"), nil
+}
+
+// IssueTracker returns where issue descriptions are taken from: 'github', 'gitlab' or 'jira'.
+// An empty value means the tracker of the hosting service is used.
+func (c *YamlConfig) IssueTracker() (string, error) {
+	return c.TrackerName, nil
+}
+
+func (c *YamlConfig) JiraUrl() (string, error) {
+	return strings.TrimRight(c.JiraAddress, "/"), nil
+}
+
+func (c *YamlConfig) JiraUser() (string, error) {
+	return c.JiraUsername, nil
+}
+
+func (c *YamlConfig) JiraKey() (string, error) {
+	return c.APIKeys["jira"], nil
+}
+
+// Timeout returns how long a single AI request may wait for an answer, e.g. '90s'.
+func (c *YamlConfig) Timeout() (time.Duration, error) {
+	if c.TimeoutValue == "" {
+		return defaultTimeout, nil
+	}
+	timeout, err := time.ParseDuration(c.TimeoutValue)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout '%s': %w", c.TimeoutValue, err)
+	}
+	return timeout, nil
+}
+
+// Retries returns how many times a failed AI request is repeated.
+func (c *YamlConfig) Retries() (int, error) {
+	if c.RetryCount == nil {
+		return defaultRetries, nil
+	}
+	if *c.RetryCount < 0 {
+		return 0, fmt.Errorf("invalid number of retries: %d", *c.RetryCount)
+	}
+	return *c.RetryCount, nil
+}
+
+// Fallbacks returns the configurations of the models from 'fallback-models',
+// in order, to be tried when the default model fails.
+// The default model itself is skipped if it's listed.
+func (c *YamlConfig) Fallbacks() ([]Config, error) {
+	var res []Config
+	for _, name := range c.FallbackModels {
+		if name == c.DefaultModel {
+			continue
+		}
+		if _, ok := c.Models[name]; !ok {
+			return nil, fmt.Errorf("fallback model '%s' is not defined in 'models'", name)
+		}
+		fallback := *c
+		fallback.DefaultModel = name
+		fallback.FallbackModels = nil
+		res = append(res, &fallback)
+	}
+	return res, nil
+}
+
+// Convention returns the commit message convention from the 'convention' section,
+// or Conventional Commits with the issue in the scope if there is none.
+func (c *YamlConfig) Convention() (convention.Convention, error) {
+	if c.CommitConvention == nil {
+		return convention.Default(), nil
+	}
+	if err := c.CommitConvention.Validate(); err != nil {
+		return convention.Convention{}, fmt.Errorf("invalid convention: %w", err)
+	}
+	return *c.CommitConvention, nil
+}
+
+// Review tells whether generated commit messages are shown for review before committing.
+func (c *YamlConfig) Review() (bool, error) {
+	return c.ReviewCommits, nil
+}
+
+// Staged tells whether only staged changes are committed, instead of all changes.
+func (c *YamlConfig) Staged() (bool, error) {
+	return c.StagedOnly, nil
+}
+
+// Ignore returns the globs of files, like lockfiles or generated code, whose diffs aren't sent to AI.
+func (c *YamlConfig) Ignore() ([]string, error) {
+	return c.IgnoredFiles, nil
+}
+
+// ContextWindow returns the 'context-window' of the default model in tokens,
+// or zero when it isn't set and the known window of the model is used.
+func (c *YamlConfig) ContextWindow() (int, error) {
+	value, ok := c.Models[c.DefaultModel]["context-window"]
+	if !ok || value == "" {
+		return 0, nil
+	}
+	window, err := strconv.Atoi(value)
+	if err != nil || window <= 0 {
+		return 0, fmt.Errorf("invalid context-window '%s' of model '%s', expected a positive number of tokens", value, c.DefaultModel)
+	}
+	return window, nil
+}
+
+// Workers returns how many parts of a large diff AI summarizes at the same time, or zero for the default.
+func (c *YamlConfig) Workers() (int, error) {
+	if c.WorkerCount < 0 {
+		return 0, fmt.Errorf("invalid workers '%d', expected a positive number", c.WorkerCount)
+	}
+	return c.WorkerCount, nil
+}
+
+// CacheTTL returns how long AI answers are kept in the cache, zero if they aren't cached.
+func (c *YamlConfig) CacheTTL() (time.Duration, error) {
+	if c.CacheTTLValue == "" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(c.CacheTTLValue)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cache-ttl '%s': %w", c.CacheTTLValue, err)
+	}
+	if ttl < 0 {
+		return 0, fmt.Errorf("invalid cache-ttl '%s', expected a positive duration", c.CacheTTLValue)
+	}
+	return ttl, nil
+}
+
+// BaseBranch returns the branch changes are compared with, empty to find it in the repository.
+func (c *YamlConfig) BaseBranch() (string, error) {
+	return c.BaseBranchName, nil
+}
+
+// DiffMode returns which changes of the branch go to pull requests, only the commits by default.
+func (c *YamlConfig) DiffMode() (git.Mode, error) {
+	if c.DiffModeName == "" {
+		return git.Committed, nil
+	}
+	return git.ParseMode(c.DiffModeName)
+}
+
 func (c *YamlConfig) Model() (string, error) {
 	model := c.DefaultModel
 	return c.Models[model]["model-id"], nil
 }
 
+// BaseUrl returns the 'base-url' of the default model, e.g. the address of a local Ollama.
+// An empty value means the provider's own endpoint is used.
+func (c *YamlConfig) BaseUrl() (string, error) {
+	return strings.TrimRight(c.Models[c.DefaultModel]["base-url"], "/"), nil
+}
+
+// ApiVersion returns the 'api-version' of the default model, required by Azure OpenAI.
+// It needs the 'base-url' of the Azure resource.
+func (c *YamlConfig) ApiVersion() (string, error) {
+	version := c.Models[c.DefaultModel]["api-version"]
+	if version != "" && c.Models[c.DefaultModel]["base-url"] == "" {
+		return "", fmt.Errorf("api-version '%s' of model '%s' requires a base-url", version, c.DefaultModel)
+	}
+	return version, nil
+}
+
+// Headers returns the extra HTTP headers of the default model,
+// configured as keys with the 'header-' prefix.
+func (c *YamlConfig) Headers() (map[string]string, error) {
+	headers := make(map[string]string)
+	for key, value := range c.Models[c.DefaultModel] {
+		if name, ok := strings.CutPrefix(key, headerPrefix); ok && name != "" {
+			headers[name] = value
+		}
+	}
+	return headers, nil
+}
+
 func (c *YamlConfig) DeepseekKey() (string, error) {
 	return c.APIKeys["deepseek"], nil
 }
@@ -4,9 +4,12 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/volodya-lombrozo/aidy/internal/convention"
+	"github.com/volodya-lombrozo/aidy/internal/git"
 )
 
 const FULL = `
@@ -142,6 +145,248 @@ func TestGetGithubAPIKey(t *testing.T) {
 	assert.Equal(t, "gh-test", key, "API key should match")
 }
 
+func TestYaml_Gitlab(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "config.yml")
+	err := os.WriteFile(path, []byte("gitlab-url: https://gitlab.example.com/\napi-keys:\n  gitlab: gl-test\n"), 0644)
+	require.NoError(t, err, "Failed to write config file")
+	config, err := YamlConf(path)
+	require.NoError(t, err, "Failed to load config")
+
+	key, err := config.GitlabKey()
+	require.NoError(t, err, "Error should be nil")
+	url, err := config.GitlabUrl()
+	require.NoError(t, err, "Error should be nil")
+
+	assert.Equal(t, "gl-test", key, "API key should match")
+	assert.Equal(t, "https://gitlab.example.com", url, "URL should be trimmed")
+}
+
+func TestYaml_GitlabUrl_Default(t *testing.T) {
+	config := &YamlConfig{}
+
+	url, err := config.GitlabUrl()
+
+	require.NoError(t, err, "Error should be nil")
+	assert.Equal(t, "https://gitlab.com", url, "gitlab.com should be used by default")
+}
+
+func TestYaml_TimeoutAndRetries(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "config.yml")
+	err := os.WriteFile(path, []byte("timeout: 90s\nretries: 0\n"), 0644)
+	require.NoError(t, err, "Failed to write config file")
+	config, err := YamlConf(path)
+	require.NoError(t, err, "Failed to load config")
+
+	timeout, err := config.Timeout()
+	require.NoError(t, err, "Error should be nil")
+	retries, err := config.Retries()
+	require.NoError(t, err, "Error should be nil")
+
+	assert.Equal(t, 90*time.Second, timeout, "Timeout should match")
+	assert.Equal(t, 0, retries, "Retries can be disabled")
+}
+
+func TestYaml_TimeoutAndRetries_Default(t *testing.T) {
+	config := &YamlConfig{}
+
+	timeout, err := config.Timeout()
+	require.NoError(t, err, "Error should be nil")
+	retries, err := config.Retries()
+	require.NoError(t, err, "Error should be nil")
+
+	assert.Equal(t, 2*time.Minute, timeout, "Default timeout should be used")
+	assert.Equal(t, 3, retries, "Default retries should be used")
+}
+
+func TestYaml_Timeout_Invalid(t *testing.T) {
+	config := &YamlConfig{TimeoutValue: "forever"}
+
+	_, err := config.Timeout()
+
+	require.Error(t, err, "Expected an error for an invalid timeout")
+	assert.Contains(t, err.Error(), "invalid timeout 'forever'")
+}
+
+func TestYaml_Jira(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "config.yml")
+	content := "issue-tracker: jira\njira-url: https://example.atlassian.net/\njira-user: me@example.com\napi-keys:\n  jira: jira-token\n"
+	err := os.WriteFile(path, []byte(content), 0644)
+	require.NoError(t, err, "Failed to write config file")
+	config, err := YamlConf(path)
+	require.NoError(t, err, "Failed to load config")
+
+	tracker, _ := config.IssueTracker()
+	url, _ := config.JiraUrl()
+	user, _ := config.JiraUser()
+	key, _ := config.JiraKey()
+
+	assert.Equal(t, "jira", tracker)
+	assert.Equal(t, "https://example.atlassian.net", url, "URL should be trimmed")
+	assert.Equal(t, "me@example.com", user)
+	assert.Equal(t, "jira-token", key)
+}
+
+func TestYaml_BaseUrl(t *testing.T) {
+	config := &YamlConfig{
+		DefaultModel: "local",
+		Models: map[string]map[string]string{
+			"local": {"provider": "ollama", "model-id": "llama3.2", "base-url": "http://gpu-box:11434/"},
+		},
+	}
+
+	url, err := config.BaseUrl()
+
+	require.NoError(t, err, "Error should be nil")
+	assert.Equal(t, "http://gpu-box:11434", url, "URL should be trimmed")
+}
+
+func TestYaml_CompatibleEndpoint(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "config.yml")
+	content := `default-model: azure
+models:
+  azure:
+    provider: openai
+    model-id: gpt-4o
+    base-url: https://example.openai.azure.com
+    api-version: 2024-06-01
+    header-X-Title: aidy
+    header-: ignored
+`
+	err := os.WriteFile(path, []byte(content), 0644)
+	require.NoError(t, err, "Failed to write config file")
+	config, err := YamlConf(path)
+	require.NoError(t, err, "Failed to load config")
+
+	version, err := config.ApiVersion()
+	require.NoError(t, err, "Error should be nil")
+	headers, err := config.Headers()
+	require.NoError(t, err, "Error should be nil")
+
+	assert.Equal(t, "2024-06-01", version)
+	assert.Equal(t, map[string]string{"X-Title": "aidy"}, headers, "Only named headers should be taken")
+}
+
+func TestYaml_ApiVersionWithoutBaseUrl(t *testing.T) {
+	config := &YamlConfig{
+		DefaultModel: "azure",
+		Models: map[string]map[string]string{
+			"azure": {"provider": "openai", "model-id": "gpt-4o", "api-version": "2024-06-01"},
+		},
+	}
+
+	_, err := config.ApiVersion()
+
+	require.Error(t, err, "Expected an error when api-version is set without base-url")
+	assert.Contains(t, err.Error(), "requires a base-url")
+}
+
+func TestYaml_Fallbacks(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "config.yml")
+	content := `default-model: deepseek
+fallback-models: [deepseek, 4o]
+api-keys:
+  deepseek: ds-key
+  openai: oa-key
+models:
+  deepseek:
+    provider: deepseek
+    model-id: deepseek-chat
+  4o:
+    provider: openai
+    model-id: gpt-4o
+`
+	err := os.WriteFile(path, []byte(content), 0644)
+	require.NoError(t, err, "Failed to write config file")
+	config, err := YamlConf(path)
+	require.NoError(t, err, "Failed to load config")
+
+	fallbacks, err := config.Fallbacks()
+
+	require.NoError(t, err, "Error should be nil")
+	require.Len(t, fallbacks, 1, "The default model should not be repeated")
+	provider, _ := fallbacks[0].Provider()
+	model, _ := fallbacks[0].Model()
+	token, _ := fallbacks[0].Token()
+	assert.Equal(t, "openai", provider)
+	assert.Equal(t, "gpt-4o", model)
+	assert.Equal(t, "oa-key", token)
+}
+
+func TestYaml_Fallbacks_UnknownModel(t *testing.T) {
+	config := &YamlConfig{DefaultModel: "deepseek", FallbackModels: []string{"missing"}}
+
+	_, err := config.Fallbacks()
+
+	require.Error(t, err, "Expected an error for an undefined model")
+	assert.Contains(t, err.Error(), "fallback model 'missing' is not defined")
+}
+
+func TestYaml_Convention(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "config.yml")
+	content := `default-model: 4o
+convention:
+  types: [add, fix]
+  issue: trailer
+  trailer: Closes
+  max-length: 50
+  gitmoji: true
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644), "Failed to write config file")
+	config, err := YamlConf(path)
+	require.NoError(t, err, "Failed to load config")
+
+	conv, err := config.Convention()
+
+	require.NoError(t, err, "Error should be nil")
+	assert.Equal(t, convention.Convention{Types: []string{"add", "fix"}, Issue: "trailer", Trailer: "Closes", MaxLength: 50, Gitmoji: true}, conv)
+}
+
+func TestYaml_Convention_Default(t *testing.T) {
+	config := &YamlConfig{}
+
+	conv, err := config.Convention()
+
+	require.NoError(t, err, "Error should be nil")
+	assert.Equal(t, convention.Default(), conv)
+}
+
+func TestYaml_Convention_Invalid(t *testing.T) {
+	config := &YamlConfig{CommitConvention: &convention.Convention{Issue: "footer"}}
+
+	_, err := config.Convention()
+
+	require.Error(t, err, "Expected an error for an unknown issue placement")
+	assert.Contains(t, err.Error(), "invalid convention")
+}
+
+func TestYaml_Review(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte("default-model: 4o\nreview: true\n"), 0644), "Failed to write config file")
+	config, err := YamlConf(path)
+	require.NoError(t, err, "Failed to load config")
+
+	review, err := config.Review()
+
+	require.NoError(t, err, "Error should be nil")
+	assert.True(t, review, "Expected commit messages to be reviewed")
+}
+
+func TestYaml_Staged(t *testing.T) {
+	config := &YamlConfig{StagedOnly: true}
+
+	staged, err := config.Staged()
+
+	require.NoError(t, err, "Error should be nil")
+	assert.True(t, staged, "Expected only staged changes to be committed")
+}
+
 func TestYaml_Provider(t *testing.T) {
 	tmp := t.TempDir()
 	path := filepath.Join(tmp, "config.yml")
@@ -223,3 +468,86 @@ func clean(t *testing.T, tmp string) {
 		t.Fatalf("Error removing temp directory: %v", err)
 	}
 }
+
+func TestYaml_Ignore(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte("default-model: 4o\nignore:\n  - '*.snap'\n  - 'testdata/**'\n"), 0644), "Failed to write config file")
+	config, err := YamlConf(path)
+	require.NoError(t, err, "Failed to load config")
+
+	ignore, err := config.Ignore()
+
+	require.NoError(t, err, "Error should be nil")
+	assert.Equal(t, []string{"*.snap", "testdata/**"}, ignore)
+}
+
+func TestYaml_ContextWindow(t *testing.T) {
+	config := &YamlConfig{
+		DefaultModel: "local",
+		Models: map[string]map[string]string{
+			"local": {"provider": "ollama", "model-id": "llama3.2", "context-window": "32768"},
+			"cloud": {"provider": "openai", "model-id": "gpt-4o"},
+			"wrong": {"provider": "ollama", "context-window": "32k"},
+		},
+	}
+
+	window, err := config.ContextWindow()
+	require.NoError(t, err, "Error should be nil")
+	assert.Equal(t, 32768, window, "Expected the configured window")
+
+	config.DefaultModel = "cloud"
+	window, err = config.ContextWindow()
+	require.NoError(t, err, "Error should be nil")
+	assert.Zero(t, window, "Expected no window when it isn't configured")
+
+	config.DefaultModel = "wrong"
+	_, err = config.ContextWindow()
+	assert.ErrorContains(t, err, "invalid context-window '32k'")
+}
+
+func TestYaml_Workers(t *testing.T) {
+	config := &YamlConfig{WorkerCount: 8}
+
+	workers, err := config.Workers()
+	require.NoError(t, err, "Error should be nil")
+	assert.Equal(t, 8, workers, "Expected the configured workers")
+
+	config.WorkerCount = -1
+	_, err = config.Workers()
+	assert.ErrorContains(t, err, "invalid workers '-1'")
+}
+
+func TestYaml_CacheTTL(t *testing.T) {
+	config := &YamlConfig{}
+
+	ttl, err := config.CacheTTL()
+	require.NoError(t, err, "Error should be nil")
+	assert.Zero(t, ttl, "Expected answers not to be cached by default")
+
+	config.CacheTTLValue = "24h"
+	ttl, err = config.CacheTTL()
+	require.NoError(t, err, "Error should be nil")
+	assert.Equal(t, 24*time.Hour, ttl, "Expected the configured TTL")
+
+	config.CacheTTLValue = "a day"
+	_, err = config.CacheTTL()
+	assert.ErrorContains(t, err, "invalid cache-ttl 'a day'")
+}
+
+func TestYaml_DiffMode(t *testing.T) {
+	config := &YamlConfig{}
+
+	mode, err := config.DiffMode()
+	require.NoError(t, err, "Error should be nil")
+	assert.Equal(t, git.Committed, mode, "Expected only commits by default")
+
+	config.DiffModeName = "staged"
+	mode, err = config.DiffMode()
+	require.NoError(t, err, "Error should be nil")
+	assert.Equal(t, git.Staged, mode, "Expected the configured mode")
+
+	config.DiffModeName = "everything"
+	_, err = config.DiffMode()
+	assert.ErrorContains(t, err, "unknown diff mode 'everything'")
+}
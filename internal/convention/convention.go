@@ -0,0 +1,237 @@
+package convention
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Convention describes how commit messages and PR titles look.
+// The zero value is Conventional Commits with the issue reference as the scope,
+// e.g. 'feat(#42): add a feature'.
+type Convention struct {
+	// Style is 'angular' for '<type>(<scope>): <description>' or 'plain' for just a description.
+	Style string `yaml:"style,omitempty"`
+	// Types are the allowed values of <type>.
+	Types []string `yaml:"types,omitempty"`
+	// Issue tells where the issue reference goes: 'scope', 'trailer' or 'none'.
+	Issue string `yaml:"issue,omitempty"`
+	// Scope is 'component' to let AI name the changed part in the scope, or 'none'.
+	// It's used when the issue reference isn't the scope.
+	Scope string `yaml:"scope,omitempty"`
+	// Trailer is the key of the trailer with the issue reference, e.g. 'Refs: #42'.
+	Trailer string `yaml:"trailer,omitempty"`
+	// MaxLength limits the length of the first line.
+	MaxLength int `yaml:"max-length,omitempty"`
+	// Gitmoji prefixes the first line with the emoji of its type, e.g. '✨ feat: ...'.
+	Gitmoji bool `yaml:"gitmoji,omitempty"`
+	// Body asks for a body explaining why the change was made, after the subject line.
+	Body bool `yaml:"body,omitempty"`
+	// Coauthors adds a 'Co-authored-by' trailer for everyone else who committed to the branch.
+	Coauthors bool `yaml:"co-authors,omitempty"`
+	// Attempts limits how many times AI is asked to fix a message that breaks the convention.
+	Attempts *int `yaml:"repairs,omitempty"`
+	// Check turns off the checks of generated messages when it's false.
+	Check *bool `yaml:"lint,omitempty"`
+}
+
+const (
+	Angular   = "angular"
+	Plain     = "plain"
+	InScope   = "scope"
+	InTrailer = "trailer"
+	Nowhere   = "none"
+	Component = "component"
+)
+
+var defaultTypes = []string{"fix", "feat", "build", "chore", "ci", "docs", "style", "refactor", "perf", "test"}
+
+var gitmoji = map[string]string{
+	"feat":     "✨",
+	"fix":      "🐛",
+	"build":    "📦️",
+	"chore":    "🔧",
+	"ci":       "👷",
+	"docs":     "📝",
+	"style":    "🎨",
+	"refactor": "♻️",
+	"perf":     "⚡️",
+	"test":     "✅",
+	"revert":   "⏪️",
+}
+
+var breaking = regexp.MustCompile(`^(?:\S+ )?[A-Za-z]+(?:\([^)]*\))?!: (.+)$`)
+
+const issuePattern = `#?\d+|#?[A-Z][A-Z0-9]+-\d+`
+
+// Default returns the Conventional Commits convention aidy uses out of the box.
+func Default() Convention {
+	return Convention{}.normalized()
+}
+
+// Validate checks that all settings have known values.
+func (c Convention) Validate() error {
+	n := c.normalized()
+	if n.Style != Angular && n.Style != Plain {
+		return fmt.Errorf("unknown convention style '%s', expected '%s' or '%s'", c.Style, Angular, Plain)
+	}
+	if n.Issue != InScope && n.Issue != InTrailer && n.Issue != Nowhere {
+		return fmt.Errorf("unknown issue placement '%s', expected '%s', '%s' or '%s'", c.Issue, InScope, InTrailer, Nowhere)
+	}
+	if n.Style == Plain && n.Issue == InScope {
+		return fmt.Errorf("the issue can't be in the scope of the '%s' style, use '%s' or '%s'", Plain, InTrailer, Nowhere)
+	}
+	if n.Scope != Component && n.Scope != Nowhere {
+		return fmt.Errorf("unknown scope '%s', expected '%s' or '%s'", c.Scope, Component, Nowhere)
+	}
+	if c.MaxLength < 0 {
+		return fmt.Errorf("max length can't be negative: %d", c.MaxLength)
+	}
+	if c.Attempts != nil && *c.Attempts < 0 {
+		return fmt.Errorf("number of repairs can't be negative: %d", *c.Attempts)
+	}
+	return nil
+}
+
+// Format returns the shape of the first line for the given issue reference,
+// e.g. '<type>(#42): <description>'.
+func (c Convention) Format(ref string) string {
+	c = c.normalized()
+	switch {
+	case c.Style == Plain:
+		return "<description>"
+	case c.Issue == InScope && ref != "":
+		return fmt.Sprintf("<type>(%s): <description>", ref)
+	case c.Issue != InScope && c.Scope == Component:
+		return "<type>(<scope>): <description>"
+	default:
+		return "<type>: <description>"
+	}
+}
+
+// Rules explains the placeholders of the Format to AI, one rule per line.
+func (c Convention) Rules(ref string) string {
+	c = c.normalized()
+	var rules []string
+	if c.Style == Angular {
+		rules = append(rules, fmt.Sprintf("- <type> is one of: %s", strings.Join(c.Types, ", ")))
+		if c.Issue == InScope && ref != "" {
+			rules = append(rules, fmt.Sprintf("- %s is the issue reference (do not modify it)", ref))
+		} else if c.Scope == Component {
+			rules = append(rules, "- <scope> is a short name of the changed component")
+		}
+	}
+	if c.Issue != InScope || c.Style == Plain {
+		rules = append(rules, "- Do not mention the issue reference")
+	}
+	return strings.Join(rules, "\n")
+}
+
+// Limit returns the maximum length of the first line.
+func (c Convention) Limit() int {
+	return c.normalized().MaxLength
+}
+
+// Heal fixes the first line of an AI-generated message so it follows the convention:
+// the issue reference in the scope is replaced with ref, misplaced references are dropped,
+// type prefixes are removed for the plain style and gitmoji are added.
+func (c Convention) Heal(message, ref string) string {
+	c = c.normalized()
+	subject, rest, found := strings.Cut(message, "\n")
+	types := c.alternatives()
+	switch {
+	case c.Style == Plain:
+		prefix := regexp.MustCompile(`^\s*(?:` + types + `)(?:\([^)]*\))?!?:\s*`)
+		subject = prefix.ReplaceAllString(subject, "")
+	case c.Issue == InScope:
+		if ref != "" {
+			re := regexp.MustCompile(`(` + types + `)\((?:` + issuePattern + `)\)`)
+			subject = re.ReplaceAllString(subject, "${1}("+strings.ReplaceAll(ref, "$", "$$")+")")
+		}
+	default:
+		re := regexp.MustCompile(`^(\s*(?:` + types + `))\((?:` + issuePattern + `)\)`)
+		subject = re.ReplaceAllString(subject, "${1}")
+	}
+	if c.Gitmoji && c.Style == Angular {
+		subject = c.emoji(subject)
+	}
+	if found {
+		return subject + "\n" + rest
+	}
+	return subject
+}
+
+// Sign adds the trailers to a commit message: the issue reference, if the convention keeps the issue there,
+// the co-authors, if they are enabled, and 'BREAKING CHANGE', if the subject marks the change as breaking.
+func (c Convention) Sign(message, ref string, coauthors ...string) string {
+	c = c.normalized()
+	msg := Parse(message, c.Trailer)
+	changed := false
+	if match := breaking.FindStringSubmatch(msg.Subject); match != nil {
+		if _, ok := msg.Trailer("BREAKING CHANGE"); !ok {
+			msg = msg.With("BREAKING CHANGE", match[1])
+			changed = true
+		}
+	}
+	if c.Issue == InTrailer && ref != "" {
+		msg = msg.With(c.Trailer, ref)
+		changed = true
+	}
+	if c.Coauthors {
+		for _, author := range coauthors {
+			msg = msg.With("Co-authored-by", author)
+			changed = true
+		}
+	}
+	if !changed {
+		return message
+	}
+	return msg.String()
+}
+
+func (c Convention) emoji(subject string) string {
+	re := regexp.MustCompile(`^(` + c.alternatives() + `)[(!:]`)
+	match := re.FindStringSubmatch(subject)
+	if match == nil {
+		return subject
+	}
+	emoji, ok := gitmoji[match[1]]
+	if !ok {
+		return subject
+	}
+	return emoji + " " + subject
+}
+
+func (c Convention) alternatives() string {
+	quoted := make([]string, 0, len(c.Types))
+	for _, t := range c.Types {
+		quoted = append(quoted, regexp.QuoteMeta(t))
+	}
+	return strings.Join(quoted, "|")
+}
+
+func (c Convention) normalized() Convention {
+	if c.Style == "" {
+		c.Style = Angular
+	}
+	if len(c.Types) == 0 {
+		c.Types = defaultTypes
+	}
+	if c.Issue == "" {
+		if c.Style == Plain {
+			c.Issue = Nowhere
+		} else {
+			c.Issue = InScope
+		}
+	}
+	if c.Scope == "" {
+		c.Scope = Nowhere
+	}
+	if c.Trailer == "" {
+		c.Trailer = "Refs"
+	}
+	if c.MaxLength == 0 {
+		c.MaxLength = 72
+	}
+	return c
+}
@@ -0,0 +1,127 @@
+package convention
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvention_DefaultFormat(t *testing.T) {
+	conv := Convention{}
+
+	assert.Equal(t, "<type>(#42): <description>", conv.Format("#42"))
+	assert.Contains(t, conv.Rules("#42"), "<type> is one of: fix, feat, build, chore, ci, docs, style, refactor, perf, test")
+	assert.Contains(t, conv.Rules("#42"), "#42 is the issue reference (do not modify it)")
+	assert.Equal(t, 72, conv.Limit())
+}
+
+func TestConvention_Formats(t *testing.T) {
+	tests := []struct {
+		name     string
+		conv     Convention
+		expected string
+	}{
+		{"plain", Convention{Style: Plain}, "<description>"},
+		{"component scope", Convention{Issue: InTrailer, Scope: Component}, "<type>(<scope>): <description>"},
+		{"no scope", Convention{Issue: Nowhere}, "<type>: <description>"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.conv.Format("#42"))
+			assert.Contains(t, test.conv.Rules("#42"), "Do not mention the issue reference")
+		})
+	}
+}
+
+func TestConvention_Heal(t *testing.T) {
+	tests := []struct {
+		name     string
+		conv     Convention
+		message  string
+		expected string
+	}{
+		{"replaces issue in scope", Convention{}, "feat(#7): add feature", "feat(#42): add feature"},
+		{"keeps non-issue scope", Convention{}, "feat(#master): add feature", "feat(#master): add feature"},
+		{"custom types", Convention{Types: []string{"add"}}, "add(#7): add feature", "add(#42): add feature"},
+		{"drops issue scope", Convention{Issue: InTrailer}, "fix(PROJ-7): fix bug", "fix: fix bug"},
+		{"keeps component scope", Convention{Issue: Nowhere, Scope: Component}, "fix(cli): fix bug", "fix(cli): fix bug"},
+		{"strips prefix", Convention{Style: Plain}, "fix(#7)!: Fix bug", "Fix bug"},
+		{"adds gitmoji", Convention{Gitmoji: true}, "fix(#7): fix bug", "🐛 fix(#42): fix bug"},
+		{"keeps gitmoji", Convention{Gitmoji: true}, "🐛 fix(#42): fix bug", "🐛 fix(#42): fix bug"},
+		{"heals only subject", Convention{Issue: InTrailer}, "fix(#7): bug\n\nfix(#7): body", "fix: bug\n\nfix(#7): body"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.conv.Heal(test.message, "#42"))
+		})
+	}
+}
+
+func TestConvention_Sign(t *testing.T) {
+	conv := Convention{Issue: InTrailer, Trailer: "Closes"}
+
+	signed := conv.Sign("fix: bug\n", "#42")
+
+	assert.Equal(t, "fix: bug\n\nCloses: #42", signed)
+	assert.Equal(t, signed, conv.Sign(signed, "#42"), "Expected the trailer not to be repeated")
+	assert.Equal(t, "fix(#42): bug", Convention{}.Sign("fix(#42): bug", "#42"), "Expected no trailer for the issue in scope")
+}
+
+func TestConvention_Validate(t *testing.T) {
+	assert.NoError(t, Convention{}.Validate())
+	assert.NoError(t, Convention{Style: Plain, Issue: InTrailer}.Validate())
+	assert.Error(t, Convention{Style: "emoji"}.Validate())
+	assert.Error(t, Convention{Issue: "footer"}.Validate())
+	assert.Error(t, Convention{Style: Plain, Issue: InScope}.Validate())
+	assert.Error(t, Convention{Scope: "module"}.Validate())
+	assert.Error(t, Convention{MaxLength: -1}.Validate())
+}
+
+func TestConvention_Lint(t *testing.T) {
+	tests := []struct {
+		name      string
+		conv      Convention
+		message   string
+		violation string
+	}{
+		{"empty", Convention{}, " \n", "the message is empty"},
+		{"too long", Convention{MaxLength: 20}, "feat(#42): add a very long feature", "34 characters long, but must not exceed 20"},
+		{"period", Convention{}, "feat(#42): add feature.", "must not end with a period"},
+		{"quotes", Convention{}, "`feat(#42): add feature`", "must not be wrapped in quotes or backticks"},
+		{"no type", Convention{}, "add feature", "must follow the format '<type>: <description>'"},
+		{"unknown type", Convention{}, "feature(#42): add feature", "'feature' is not an allowed type"},
+		{"past tense", Convention{}, "fix(#42): fixed bug", "imperative mood"},
+		{"continuous", Convention{Style: Plain}, "Adding feature", "imperative mood"},
+		{"third person", Convention{}, "feat: adds feature", "imperative mood"},
+		{"unexpected emoji", Convention{}, "✨ feat: add feature", "must follow the format"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			violations := test.conv.Lint(test.message)
+
+			require.Len(t, violations, 1, "Expected a single violation, got %v", violations)
+			assert.Contains(t, violations[0], test.violation)
+		})
+	}
+}
+
+func TestConvention_Lint_AcceptsValidMessages(t *testing.T) {
+	assert.Empty(t, Convention{}.Lint("feat(#42): add feature"))
+	assert.Empty(t, Convention{}.Lint("fix(#42): embed the string in bug report\n\nmore details."))
+	assert.Empty(t, Convention{Gitmoji: true}.Lint("✨ feat(#42)!: add feature"))
+	assert.Empty(t, Convention{Style: Plain}.Lint("Speed up the build"))
+}
+
+func TestConvention_Lint_TurnedOff(t *testing.T) {
+	off := false
+
+	assert.Empty(t, Convention{Check: &off}.Lint("feat(#42): changed files."))
+}
+
+func TestConvention_Repairs(t *testing.T) {
+	none := 0
+
+	assert.Equal(t, 2, Convention{}.Repairs())
+	assert.Equal(t, 0, Convention{Attempts: &none}.Repairs())
+}
@@ -0,0 +1,103 @@
+package convention
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// defaultRepairs is how many times AI is asked to fix a message that breaks the convention.
+const defaultRepairs = 2
+
+var header = regexp.MustCompile(`^(?:(\S+) )?([A-Za-z]+)(?:\([^)]*\))?!?: (.*)$`)
+
+// thirdPerson are common verbs in the third person that are used instead of the imperative mood.
+var thirdPerson = []string{
+	"adds", "fixes", "updates", "removes", "changes", "implements", "improves", "refactors",
+	"moves", "renames", "uses", "makes", "introduces", "deletes", "creates", "bumps", "replaces",
+}
+
+// imperative are verbs in the imperative mood that look like past or continuous forms.
+var imperative = []string{"bring", "embed", "feed", "need", "seed", "shed", "speed", "string", "ping"}
+
+// Repairs returns how many times AI should be asked to fix a message that breaks the convention.
+func (c Convention) Repairs() int {
+	if c.Attempts == nil {
+		return defaultRepairs
+	}
+	return *c.Attempts
+}
+
+// Lint checks the first line of a commit message or a PR title and explains every broken rule.
+// A message that follows the convention has no violations,
+// and neither has any message when the checks are turned off.
+func (c Convention) Lint(message string) []string {
+	if c.Check != nil && !*c.Check {
+		return nil
+	}
+	c = c.normalized()
+	var violations []string
+	trimmed := strings.TrimSpace(message)
+	if trimmed == "" {
+		return []string{"the message is empty"}
+	}
+	if quoted(trimmed) {
+		violations = append(violations, "the message must not be wrapped in quotes or backticks")
+		trimmed = strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+	}
+	subject, _, _ := strings.Cut(trimmed, "\n")
+	subject = strings.TrimSpace(subject)
+	if length := len([]rune(subject)); length > c.MaxLength {
+		violations = append(violations, fmt.Sprintf("the first line is %d characters long, but must not exceed %d", length, c.MaxLength))
+	}
+	if strings.HasSuffix(subject, ".") {
+		violations = append(violations, "the first line must not end with a period")
+	}
+	description := subject
+	if c.Style == Angular {
+		match := header.FindStringSubmatch(subject)
+		if match == nil || (match[1] != "" && !c.Gitmoji) {
+			violations = append(violations, fmt.Sprintf("the first line must follow the format '%s'", c.Format("")))
+			return violations
+		}
+		if !slices.Contains(c.Types, match[2]) {
+			violations = append(violations, fmt.Sprintf("'%s' is not an allowed type, use one of: %s", match[2], strings.Join(c.Types, ", ")))
+		}
+		description = match[3]
+	}
+	if word := firstWord(description); !imperativeMood(word) {
+		violations = append(violations, fmt.Sprintf("the description must use the imperative mood, e.g. 'add' instead of 'added', 'adding' or 'adds', but starts with '%s'", word))
+	}
+	return violations
+}
+
+func quoted(text string) bool {
+	if len(text) < 2 {
+		return false
+	}
+	first, last := text[0], text[len(text)-1]
+	return first == last && (first == '"' || first == '\'' || first == '`')
+}
+
+func firstWord(text string) string {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(strings.Trim(fields[0], "`'\",:"))
+}
+
+// imperativeMood guesses whether the verb is in the imperative mood by its ending.
+func imperativeMood(word string) bool {
+	if slices.Contains(imperative, word) {
+		return true
+	}
+	if slices.Contains(thirdPerson, word) {
+		return false
+	}
+	if len(word) > 4 && strings.HasSuffix(word, "ed") && !strings.HasSuffix(word, "eed") {
+		return false
+	}
+	return !(len(word) > 5 && strings.HasSuffix(word, "ing"))
+}
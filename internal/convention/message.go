@@ -0,0 +1,129 @@
+package convention
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// width is the column at which lines of the body are wrapped.
+const width = 72
+
+var trailer = regexp.MustCompile(`^(?:[A-Za-z][A-Za-z0-9-]*|BREAKING CHANGE): \S`)
+
+// known are the trailer keys that tell a paragraph of trailers from a body that ends with 'Key: value' lines.
+var known = []string{"Refs", "Co-authored-by", "BREAKING CHANGE", "Signed-off-by"}
+
+// Message is a commit message split into its parts:
+// the subject line, the body and the trailers, e.g. 'Refs: #42'.
+type Message struct {
+	Subject  string
+	Body     string
+	Trailers []string
+}
+
+// Parse splits a commit message into its parts.
+// The last paragraph is taken as trailers if each of its lines looks like 'Key: value'
+// and at least one of them has a known key, like 'Refs', or one of the given keys.
+func Parse(text string, keys ...string) Message {
+	text = strings.Trim(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	subject, rest, _ := strings.Cut(text, "\n")
+	msg := Message{Subject: subject}
+	rest = strings.Trim(rest, "\n")
+	if rest == "" {
+		return msg
+	}
+	paragraphs := strings.Split(rest, "\n\n")
+	last := strings.Split(paragraphs[len(paragraphs)-1], "\n")
+	if trailers(last, slices.Concat(keys, known)) {
+		msg.Trailers = last
+		paragraphs = paragraphs[:len(paragraphs)-1]
+	}
+	msg.Body = strings.Trim(strings.Join(paragraphs, "\n\n"), "\n")
+	return msg
+}
+
+// Trailer returns the value of the first trailer with the given key.
+func (m Message) Trailer(key string) (string, bool) {
+	for _, line := range m.Trailers {
+		k, v, _ := strings.Cut(line, ": ")
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// With returns the message with the trailer added, unless it's already there.
+func (m Message) With(key, value string) Message {
+	line := key + ": " + value
+	for _, existing := range m.Trailers {
+		if strings.EqualFold(existing, line) {
+			return m
+		}
+	}
+	m.Trailers = append(append([]string{}, m.Trailers...), line)
+	return m
+}
+
+// Wrap returns the message with long lines of the body broken at 72 characters.
+func (m Message) Wrap() Message {
+	m.Body = wrap(m.Body, width)
+	return m
+}
+
+// String joins the parts back.
+func (m Message) String() string {
+	parts := []string{m.Subject}
+	if m.Body != "" {
+		parts = append(parts, m.Body)
+	}
+	if len(m.Trailers) > 0 {
+		parts = append(parts, strings.Join(m.Trailers, "\n"))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func trailers(lines []string, keys []string) bool {
+	found := false
+	for _, line := range lines {
+		if !trailer.MatchString(line) {
+			return false
+		}
+		key, _, _ := strings.Cut(line, ": ")
+		for _, k := range keys {
+			found = found || strings.EqualFold(key, k)
+		}
+	}
+	return found
+}
+
+// wrap breaks the lines longer than limit at spaces.
+// Indented lines, like code, are kept as they are.
+func wrap(text string, limit int) string {
+	var res []string
+	for _, line := range strings.Split(text, "\n") {
+		if len([]rune(line)) <= limit || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			res = append(res, line)
+			continue
+		}
+		indent := ""
+		if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") {
+			indent = "  "
+		}
+		current := ""
+		for _, word := range strings.Fields(line) {
+			switch {
+			case current == "":
+				current = word
+			case len([]rune(current))+1+len([]rune(word)) > limit:
+				res = append(res, current)
+				current = indent + word
+			default:
+				current += " " + word
+			}
+		}
+		res = append(res, current)
+	}
+	return strings.Join(res, "\n")
+}
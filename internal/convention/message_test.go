@@ -0,0 +1,95 @@
+package convention
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_SplitsMessage(t *testing.T) {
+	msg := Parse("feat(#42): add cache\n\nCache answers.\n\nThey are slow.\n\nRefs: #42\nCo-authored-by: Jane <jane@example.com>\n")
+
+	assert.Equal(t, "feat(#42): add cache", msg.Subject)
+	assert.Equal(t, "Cache answers.\n\nThey are slow.", msg.Body)
+	assert.Equal(t, []string{"Refs: #42", "Co-authored-by: Jane <jane@example.com>"}, msg.Trailers)
+	ref, ok := msg.Trailer("refs")
+	assert.True(t, ok, "Expected the trailer to be found")
+	assert.Equal(t, "#42", ref)
+}
+
+func TestParse_KeepsBodyWithoutTrailers(t *testing.T) {
+	msg := Parse("fix: bug\n\nThe cache was broken: it never expired.\nNow it does.")
+
+	assert.Equal(t, "The cache was broken: it never expired.\nNow it does.", msg.Body)
+	assert.Empty(t, msg.Trailers)
+}
+
+func TestParse_KeepsBodyThatLooksLikeTrailers(t *testing.T) {
+	msg := Parse("refactor: simplify cache\n\nThe lookup is faster now.\n\nBefore: 40ms\nAfter: 5ms")
+
+	assert.Equal(t, "The lookup is faster now.\n\nBefore: 40ms\nAfter: 5ms", msg.Body)
+	assert.Empty(t, msg.Trailers, "Expected no trailers without a known key")
+}
+
+func TestParse_TakesGivenKeys(t *testing.T) {
+	msg := Parse("fix: bug\n\nCloses: #42", "Closes")
+
+	assert.Empty(t, msg.Body)
+	assert.Equal(t, []string{"Closes: #42"}, msg.Trailers)
+}
+
+func TestParse_SingleLine(t *testing.T) {
+	msg := Parse("fix: bug")
+
+	assert.Equal(t, Message{Subject: "fix: bug"}, msg)
+	assert.Equal(t, "fix: bug", msg.String())
+}
+
+func TestMessage_RoundTrip(t *testing.T) {
+	text := "feat!: drop v1\n\nOld clients are gone.\n\nBREAKING CHANGE: v1 API is removed\nRefs: #7"
+
+	assert.Equal(t, text, Parse(text).String())
+}
+
+func TestMessage_With(t *testing.T) {
+	msg := Parse("fix: bug").With("Refs", "#1").With("Refs", "#1")
+
+	assert.Equal(t, "fix: bug\n\nRefs: #1", msg.String(), "Expected the trailer to be added once")
+}
+
+func TestMessage_Wrap(t *testing.T) {
+	long := strings.Repeat("word ", 20)
+	msg := Message{Subject: "fix: bug", Body: long + "\n    " + long + "\n- " + long}.Wrap()
+
+	for _, line := range strings.Split(msg.Body, "\n") {
+		if strings.HasPrefix(line, "    ") {
+			continue
+		}
+		assert.LessOrEqual(t, len(line), 72, "Expected line '%s' to be wrapped", line)
+	}
+	assert.Contains(t, msg.Body, "\n    "+long, "Expected indented lines to be kept")
+	assert.Contains(t, msg.Body, "\n  word", "Expected list items to be indented")
+}
+
+func TestConvention_SignAddsTrailers(t *testing.T) {
+	conv := Convention{Issue: InTrailer, Coauthors: true}
+
+	signed := conv.Sign("feat(api)!: drop v1\n\nOld clients are gone.", "#7", "Jane <jane@example.com>")
+
+	assert.Equal(t, "feat(api)!: drop v1\n\nOld clients are gone.\n\nBREAKING CHANGE: drop v1\nRefs: #7\nCo-authored-by: Jane <jane@example.com>", signed)
+}
+
+func TestConvention_SignKeepsBodyEndingWithValues(t *testing.T) {
+	conv := Convention{Issue: InTrailer}
+
+	signed := conv.Sign("perf: cache lookups\n\nBefore: 40ms\nAfter: 5ms", "#7")
+
+	assert.Equal(t, "perf: cache lookups\n\nBefore: 40ms\nAfter: 5ms\n\nRefs: #7", signed)
+}
+
+func TestConvention_SignKeepsBreakingChange(t *testing.T) {
+	message := "feat!: drop v1\n\nBREAKING CHANGE: v1 API is removed"
+
+	assert.Equal(t, message, Convention{}.Sign(message, "#7", "Jane <jane@example.com>"), "Expected co-authors to be disabled by default")
+}
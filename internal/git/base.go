@@ -0,0 +1,162 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mode selects which changes of the branch a diff shows, all of them counted from the merge-base.
+type Mode int
+
+const (
+	// Committed shows the commits of the branch, what a pull request gets when they are pushed.
+	Committed Mode = iota
+	// Staged adds the changes in the index to the commits.
+	Staged
+	// Worktree adds all changes of the working tree, staged or not.
+	Worktree
+)
+
+var modes = map[string]Mode{"committed": Committed, "staged": Staged, "worktree": Worktree}
+
+// ParseMode returns the mode by its name: 'committed', 'staged' or 'worktree'.
+func ParseMode(name string) (Mode, error) {
+	mode, ok := modes[name]
+	if !ok {
+		return Committed, fmt.Errorf("unknown diff mode '%s', expected 'committed', 'staged' or 'worktree'", name)
+	}
+	return mode, nil
+}
+
+// Args returns the arguments of 'git diff' that compare the commit with the state of the mode.
+func (m Mode) Args(from string) []string {
+	switch m {
+	case Staged:
+		return []string{"--cached", from}
+	case Worktree:
+		return []string{from}
+	default:
+		return []string{from, "HEAD"}
+	}
+}
+
+// BaseBranch returns the branch the current one is compared with, the first of:
+// the default branch of 'origin' ('refs/remotes/origin/HEAD'),
+// the target of the pull request opened with 'gh' ('branch.<name>.gh-merge-base'),
+// the upstream branch, unless it's the remote copy of the current one,
+// and finally a local 'main' or 'master'.
+func (r *real) BaseBranch() (string, error) {
+	if head, err := r.Run("symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD"); err == nil && strings.TrimSpace(head) != "" {
+		return strings.TrimSpace(head), nil
+	}
+	current, _ := r.CurrentBranch()
+	if current != "" {
+		if target, err := r.Run("config", "--get", "branch."+current+".gh-merge-base"); err == nil && strings.TrimSpace(target) != "" {
+			return strings.TrimSpace(target), nil
+		}
+	}
+	if upstream, err := r.Run("rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}"); err == nil {
+		upstream = strings.TrimSpace(upstream)
+		if upstream != "" && Trunk(r, upstream) != current {
+			return upstream, nil
+		}
+	}
+	for _, branch := range []string{"main", "master"} {
+		if _, err := r.Run("show-ref", "--verify", "--quiet", "refs/heads/"+branch); err == nil {
+			return branch, nil
+		}
+	}
+	return "", fmt.Errorf("neither 'main' nor 'master' branch exists, set the base branch with '--base' or 'base-branch' in the configuration")
+}
+
+// MergeBase returns the commit where HEAD forked from the branch,
+// or the branch itself if they have no common history.
+func MergeBase(g Git, branch string) string {
+	out, err := g.Run("merge-base", "HEAD", branch)
+	if commit := strings.TrimSpace(out); err == nil && commit != "" {
+		return commit
+	}
+	return branch
+}
+
+// Trunk returns the name of the branch without its remote, e.g. 'main' for 'origin/main'.
+func Trunk(g Git, branch string) string {
+	remote, name, found := strings.Cut(branch, "/")
+	if !found {
+		return branch
+	}
+	out, err := g.Run("remote")
+	if err != nil {
+		return branch
+	}
+	for _, each := range strings.Fields(out) {
+		if each == remote {
+			return name
+		}
+	}
+	return branch
+}
+
+// DiffWith renders the changes of the branch since it forked from the given one within the budget.
+func DiffWith(g Git, branch string, m Mode, b Budget) (string, error) {
+	args := m.Args(MergeBase(g, branch))
+	diff, err := g.Run(append([]string{"diff"}, args...)...)
+	if err != nil {
+		return "", fmt.Errorf("can't get a diff with '%s': %w", branch, err)
+	}
+	names, err := g.Run(append([]string{"diff", "--name-status"}, args...)...)
+	if err != nil {
+		return "", fmt.Errorf("can't get a files status diff: %w", err)
+	}
+	stat, err := g.Run(append([]string{"diff", "--stat"}, args...)...)
+	if err != nil {
+		return "", fmt.Errorf("can't get a stat diff: %w", err)
+	}
+	return NewSummary(diff, stat, names).Render(b), nil
+}
+
+// History returns the messages of the commits made on the branch since it forked from the given one, oldest first,
+// taking at most the tokens of the budget.
+// Each message is an item of a list, its body is indented under the subject.
+// When the messages don't fit, only the subjects of the latest commits are kept.
+// Merge commits are skipped, they tell nothing about the intent.
+func History(g Git, branch string, b Budget) (string, error) {
+	out, err := g.Run("log", "--reverse", "--no-merges", "--format=%B%x00", MergeBase(g, branch)+"..HEAD")
+	if err != nil {
+		return "", fmt.Errorf("can't get commits since '%s': %w", branch, err)
+	}
+	var messages, subjects []string
+	for _, message := range strings.Split(out, "\x00") {
+		if message = strings.TrimSpace(message); message != "" {
+			lines := strings.Split(message, "\n")
+			subjects = append(subjects, "- "+lines[0])
+			for i := 1; i < len(lines); i++ {
+				if lines[i] != "" {
+					lines[i] = "  " + lines[i]
+				}
+			}
+			messages = append(messages, "- "+strings.Join(lines, "\n"))
+		}
+	}
+	if full := strings.Join(messages, "\n"); b.count(full) <= b.Tokens {
+		return full, nil
+	}
+	kept := len(subjects)
+	for kept > 0 && b.count(strings.Join(subjects[len(subjects)-kept:], "\n")) > b.Tokens {
+		kept--
+	}
+	return strings.Join(subjects[len(subjects)-kept:], "\n"), nil
+}
+
+// ChangedFiles returns the paths of the files the branch changed since it forked from the given one, sorted.
+func ChangedFiles(g Git, branch string, m Mode) ([]string, error) {
+	names, err := g.Run(append([]string{"diff", "--no-renames", "--name-status"}, m.Args(MergeBase(g, branch))...)...)
+	if err != nil {
+		return nil, fmt.Errorf("can't get changed files since '%s': %w", branch, err)
+	}
+	var files []string
+	for _, change := range NewSummary("", "", names).Changes() {
+		files = append(files, change.Name)
+	}
+	return files, nil
+}
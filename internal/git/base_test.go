@@ -0,0 +1,178 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volodya-lombrozo/aidy/internal/executor"
+)
+
+func TestBaseBranch_UsesDefaultBranchOfOrigin(t *testing.T) {
+	repo, cleanup := setup(t)
+	defer cleanup()
+	run(t, repo, "branch", "master")
+	run(t, repo, "remote", "add", "origin", "https://example.com/repo.git")
+	run(t, repo, "update-ref", "refs/remotes/origin/trunk", "HEAD")
+	run(t, repo, "symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/trunk")
+	gs, err := NewGit(executor.NewReal(), repo)
+	require.NoError(t, err)
+
+	base, err := gs.BaseBranch()
+
+	require.NoError(t, err)
+	assert.Equal(t, "origin/trunk", base)
+	assert.Equal(t, "trunk", Trunk(gs, base), "Expected the remote to be stripped")
+}
+
+func TestBaseBranch_UsesPullRequestTarget(t *testing.T) {
+	repo, cleanup := setup(t)
+	defer cleanup()
+	run(t, repo, "branch", "develop")
+	run(t, repo, "config", "branch.main-branch.gh-merge-base", "develop")
+	gs, err := NewGit(executor.NewReal(), repo)
+	require.NoError(t, err)
+
+	base, err := gs.BaseBranch()
+
+	require.NoError(t, err)
+	assert.Equal(t, "develop", base)
+}
+
+func TestBaseBranch_PrefersMainWhenMasterExistsToo(t *testing.T) {
+	repo, cleanup := setup(t)
+	defer cleanup()
+	run(t, repo, "branch", "master")
+	gs, err := NewGit(executor.NewReal(), repo)
+	require.NoError(t, err)
+
+	base, err := gs.BaseBranch()
+
+	require.NoError(t, err, "Expected no error when both 'main' and 'master' exist")
+	assert.Equal(t, "main", base)
+}
+
+func TestDiff_ComparesWithMergeBase(t *testing.T) {
+	repo, cleanup := setup(t)
+	defer cleanup()
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "feature.txt"), []byte("feature\n"), 0644))
+	run(t, repo, "add", "feature.txt")
+	run(t, repo, "commit", "-m", "feature")
+	run(t, repo, "checkout", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "upstream.txt"), []byte("upstream\n"), 0644))
+	run(t, repo, "add", "upstream.txt")
+	run(t, repo, "commit", "-m", "upstream")
+	run(t, repo, "checkout", "main-branch")
+	gs, err := NewGit(executor.NewReal(), repo)
+	require.NoError(t, err)
+
+	diff, err := gs.Diff()
+
+	require.NoError(t, err)
+	assert.Contains(t, diff, "feature.txt", "Expected changes of the branch")
+	assert.NotContains(t, diff, "upstream.txt", "Expected changes made on the base branch after the fork to be skipped")
+}
+
+func TestDiffWith_ShowsChangesOfMode(t *testing.T) {
+	repo, cleanup := setup(t)
+	defer cleanup()
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "committed.txt"), []byte("committed\n"), 0644))
+	run(t, repo, "add", "committed.txt")
+	run(t, repo, "commit", "-m", "committed")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "staged.txt"), []byte("staged\n"), 0644))
+	run(t, repo, "add", "staged.txt")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "committed.txt"), []byte("changed\n"), 0644))
+	gs, err := NewGit(executor.NewReal(), repo)
+	require.NoError(t, err)
+
+	committed, err := DiffWith(gs, "main", Committed, DefaultBudget())
+	require.NoError(t, err)
+	staged, err := DiffWith(gs, "main", Staged, DefaultBudget())
+	require.NoError(t, err)
+	worktree, err := DiffWith(gs, "main", Worktree, DefaultBudget())
+	require.NoError(t, err)
+
+	assert.Contains(t, committed, "+committed")
+	assert.NotContains(t, committed, "staged.txt", "Expected staged changes to be skipped")
+	assert.Contains(t, staged, "staged.txt")
+	assert.NotContains(t, staged, "+changed", "Expected unstaged changes to be skipped")
+	assert.Contains(t, worktree, "staged.txt")
+	assert.Contains(t, worktree, "+changed")
+}
+
+func TestChangedFiles_ListsFilesSinceMergeBase(t *testing.T) {
+	repo, cleanup := setup(t)
+	defer cleanup()
+	run(t, repo, "checkout", "-b", "feature")
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "docs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "docs", "guide.md"), []byte("guide\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "app.go"), []byte("package app\n"), 0644))
+	run(t, repo, "add", ".")
+	run(t, repo, "commit", "-m", "feature")
+	gs, err := NewGit(executor.NewReal(), repo)
+	require.NoError(t, err)
+
+	files, err := ChangedFiles(gs, "main", Committed)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app.go", "docs/guide.md"}, files)
+}
+
+func TestHistory_ListsCommitsSinceMergeBase(t *testing.T) {
+	repo, cleanup := setup(t)
+	defer cleanup()
+	run(t, repo, "checkout", "-b", "feature")
+	run(t, repo, "commit", "--allow-empty", "-m", "feat: add parser", "-m", "The old one was too slow.")
+	run(t, repo, "commit", "--allow-empty", "-m", "fix: handle empty input")
+	gs, err := NewGit(executor.NewReal(), repo)
+	require.NoError(t, err)
+
+	history, err := History(gs, "main-branch", Budget{Tokens: 100})
+
+	require.NoError(t, err)
+	assert.Equal(t, "- feat: add parser\n\n  The old one was too slow.\n- fix: handle empty input", history)
+}
+
+func TestHistory_KeepsSubjectsOfLatestCommitsThatFit(t *testing.T) {
+	repo, cleanup := setup(t)
+	defer cleanup()
+	run(t, repo, "checkout", "-b", "feature")
+	run(t, repo, "commit", "--allow-empty", "-m", "feat: add parser", "-m", "The old one was too slow.")
+	run(t, repo, "commit", "--allow-empty", "-m", "fix: handle empty input")
+	gs, err := NewGit(executor.NewReal(), repo)
+	require.NoError(t, err)
+
+	history, err := History(gs, "main-branch", Budget{Tokens: 7})
+
+	require.NoError(t, err)
+	assert.Equal(t, "- fix: handle empty input", history, "Expected only the latest subject to fit")
+}
+
+func TestParseMode(t *testing.T) {
+	mode, err := ParseMode("worktree")
+	require.NoError(t, err)
+	assert.Equal(t, Worktree, mode)
+
+	_, err = ParseMode("all")
+	assert.ErrorContains(t, err, "unknown diff mode 'all'")
+}
+
+func TestTrunk_KeepsLocalBranch(t *testing.T) {
+	repo, cleanup := setup(t)
+	defer cleanup()
+	gs, err := NewGit(executor.NewReal(), repo)
+	require.NoError(t, err)
+
+	assert.Equal(t, "release/1.x", Trunk(gs, "release/1.x"), "Expected a branch with a slash to be kept without such remote")
+}
+
+func run(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %v failed: %s", args, out)
+}
@@ -0,0 +1,399 @@
+package git
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/volodya-lombrozo/aidy/internal/tokens"
+)
+
+// DefaultTokens is the number of tokens a rendered diff may take by default.
+const DefaultTokens = 12000
+
+// Budget limits how much of a diff is sent to AI.
+// Files matching the Ignore globs, e.g. 'go.sum' or '**/vendor/**', keep only their headers;
+// the rest share the Tokens according to their relevance.
+// Tokens are counted by the Tokenizer of the model, or estimated when it's nil.
+type Budget struct {
+	Tokens    int
+	Ignore    []string
+	Tokenizer tokens.Tokenizer
+}
+
+// DefaultIgnore returns the globs of lockfiles, generated and vendored files
+// whose diffs are never worth sending to AI.
+func DefaultIgnore() []string {
+	return []string{
+		"go.sum", "package-lock.json", "yarn.lock", "pnpm-lock.yaml", "Cargo.lock",
+		"poetry.lock", "Gemfile.lock", "composer.lock", "*.min.js", "*.min.css",
+		"*.pb.go", "*_generated.go", "*.generated.*", "**/vendor/**", "**/node_modules/**",
+	}
+}
+
+// DefaultBudget returns the budget of DefaultTokens that ignores the DefaultIgnore files.
+func DefaultBudget() Budget {
+	return Budget{Tokens: DefaultTokens, Ignore: DefaultIgnore()}
+}
+
+// estimate approximates the number of tokens in the text, about four characters per token.
+func estimate(text string) int {
+	return (len(text) + 3) / 4
+}
+
+func (b Budget) count(text string) int {
+	if b.Tokenizer == nil {
+		return estimate(text)
+	}
+	return b.Tokenizer.Count(text)
+}
+
+// Spend returns the budget that is left after the text takes its tokens.
+func (b Budget) Spend(text string) Budget {
+	b.Tokens = max(b.Tokens-b.count(text), 0)
+	return b
+}
+
+// ignored returns the first glob that matches the file, if any.
+// A glob without a slash matches the file name in any directory,
+// a glob with a slash matches the whole path, where '**' stands for any number of directories.
+func ignored(name string, globs []string) (string, bool) {
+	for _, glob := range globs {
+		if strings.Contains(glob, "/") {
+			if matchPath(strings.Split(glob, "/"), strings.Split(name, "/")) {
+				return glob, true
+			}
+		} else if ok, _ := path.Match(glob, path.Base(name)); ok {
+			return glob, true
+		}
+	}
+	return "", false
+}
+
+func matchPath(glob []string, parts []string) bool {
+	if len(glob) == 0 {
+		return len(parts) == 0
+	}
+	if glob[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchPath(glob[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(glob[0], parts[0]); !ok {
+		return false
+	}
+	return matchPath(glob[1:], parts[1:])
+}
+
+// relevance weighs how much of the budget a file deserves:
+// source code comes first, then tests, docs and configs, removed files come last.
+func relevance(c Change) int {
+	name := strings.ToLower(c.Name)
+	ext := path.Ext(name)
+	switch {
+	case c.Status == "D":
+		return 1
+	case strings.Contains(name, "_test.") || strings.Contains(name, ".test.") || strings.Contains(name, ".spec.") ||
+		strings.HasPrefix(name, "test/") || strings.HasPrefix(name, "tests/") || strings.Contains(name, "/test/") || strings.Contains(name, "/tests/"):
+		return 2
+	case ext == ".md" || ext == ".txt" || ext == ".rst" || strings.HasPrefix(name, "docs/"):
+		return 2
+	case ext == ".json" || ext == ".yml" || ext == ".yaml" || ext == ".toml" || ext == ".xml" || ext == ".ini" || ext == ".lock":
+		return 2
+	default:
+		return 4
+	}
+}
+
+// whitespaceOnly tells whether the hunk only changes spaces, tabs or line breaks.
+func whitespaceOnly(hunk string) bool {
+	var removed, added strings.Builder
+	changed := false
+	for _, line := range strings.Split(hunk, "\n")[1:] {
+		switch {
+		case strings.HasPrefix(line, "-"):
+			removed.WriteString(strings.Join(strings.Fields(line[1:]), ""))
+			changed = true
+		case strings.HasPrefix(line, "+"):
+			added.WriteString(strings.Join(strings.Fields(line[1:]), ""))
+			changed = true
+		}
+	}
+	return changed && removed.String() == added.String()
+}
+
+// compressed is a file of the diff with the hunks that fit the budget.
+type compressed struct {
+	change     Change
+	header     []string
+	hunks      []string
+	glob       string
+	collapsed  int
+	omitted    int
+	allowance  int
+	needed     int
+	importance int
+}
+
+// compress renders the changes so that they fit the budget.
+// Ignored files and hunks that don't fit keep only their headers,
+// whitespace-only hunks are collapsed to their '@@' lines,
+// and a report at the end tells AI what was left out.
+func compress(changes []Change, total int, b Budget) string {
+	files := make([]*compressed, 0, len(changes))
+	left := b.Tokens
+	for _, change := range changes {
+		file := &compressed{change: change, importance: relevance(change)}
+		file.header, file.hunks = change.split()
+		left -= b.count(title(change)) + b.count(strings.Join(file.header, "\n"))
+		if glob, ok := ignored(change.Name, b.Ignore); ok {
+			file.glob = glob
+			file.omitted = len(file.hunks)
+			file.hunks = nil
+		}
+		for i, hunk := range file.hunks {
+			if whitespaceOnly(hunk) {
+				file.hunks[i] = strings.SplitN(hunk, "\n", 2)[0] + "\n(whitespace-only changes)"
+				file.collapsed++
+			}
+			file.needed += b.count(file.hunks[i]) + 1
+		}
+		files = append(files, file)
+	}
+	allocate(files, left)
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Total lines changed: %d\n", total))
+	var report []string
+	for _, file := range files {
+		sb.WriteString("\n\n")
+		sb.WriteString(title(file.change))
+		sb.WriteString(file.render(b))
+		if line := file.report(); line != "" {
+			report = append(report, line)
+		}
+	}
+	if len(report) > 0 {
+		sb.WriteString(elided)
+		sb.WriteString(strings.Join(report, "\n"))
+	}
+	return sb.String()
+}
+
+// elided starts the report of the changes left out of a rendered diff.
+const elided = "\n\nSome changes were left out of this diff:\n"
+
+// omitted marks the files of the report whose hunks didn't fit the budget.
+const omitted = "hunks are omitted to fit the limit"
+
+// Render returns the unified diff as a list of files with their diff blocks that fits the budget.
+func (b Budget) Render(diff string) string {
+	return parse(diff).pretty(b)
+}
+
+// Truncated tells whether hunks of the rendered diff were omitted to fit the budget.
+// Ignored files and collapsed whitespace don't count.
+func Truncated(rendered string) bool {
+	i := strings.LastIndex(rendered, elided)
+	return i >= 0 && strings.Contains(rendered[i:], omitted)
+}
+
+// Chunks splits the changes into rendered parts that fit the budget each.
+// Files of a directory stay together, unless the directory doesn't fit,
+// and a file that doesn't fit alone is cut as usual.
+func (b Budget) Chunks(changes []Change) []string {
+	var groups [][]Change
+	for _, change := range changes {
+		last := len(groups) - 1
+		if last >= 0 && path.Dir(groups[last][0].Name) == path.Dir(change.Name) {
+			groups[last] = append(groups[last], change)
+		} else {
+			groups = append(groups, []Change{change})
+		}
+	}
+	var items [][]Change
+	for _, group := range groups {
+		if b.size(group) <= b.Tokens {
+			items = append(items, group)
+			continue
+		}
+		for _, change := range group {
+			items = append(items, []Change{change})
+		}
+	}
+	var chunks []string
+	var current []Change
+	for _, item := range items {
+		if len(current) > 0 && b.size(append(append([]Change{}, current...), item...)) > b.Tokens {
+			chunks = append(chunks, b.render(current))
+			current = nil
+		}
+		current = append(current, item...)
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, b.render(current))
+	}
+	return chunks
+}
+
+// Hunks lists the changes with their hunks numbered from 1, as commit plans refer to them, so that the list fits the budget.
+// Every file and hunk is listed, but hunks of ignored files, whitespace-only hunks
+// and hunks that don't fit keep only their '@@' lines; the hunks of a file share the budget by relevance as in Render.
+func (b Budget) Hunks(changes []Change) string {
+	files := make([]*compressed, 0, len(changes))
+	left := b.Tokens
+	for _, change := range changes {
+		file := &compressed{change: change, importance: relevance(change)}
+		_, file.hunks = change.split()
+		file.glob, _ = ignored(change.Name, b.Ignore)
+		left -= b.count(listed(change))
+		for i, hunk := range file.hunks {
+			head, body, _ := strings.Cut(hunk, "\n")
+			left -= b.count(fmt.Sprintf("Hunk %d:\n%s\n", i+1, head)) + b.count(skipped)
+			if file.glob == "" && !whitespaceOnly(hunk) {
+				file.needed += b.count(body) + 1
+			}
+		}
+		files = append(files, file)
+	}
+	allocate(files, left)
+	var sb strings.Builder
+	for _, file := range files {
+		sb.WriteString(listed(file.change))
+		spent := 0
+		for i, hunk := range file.hunks {
+			head, body, _ := strings.Cut(hunk, "\n")
+			sb.WriteString(fmt.Sprintf("Hunk %d:\n%s\n", i+1, head))
+			switch {
+			case file.glob != "":
+				sb.WriteString(fmt.Sprintf("(diff is ignored by the '%s' pattern)\n", file.glob))
+			case whitespaceOnly(hunk):
+				sb.WriteString("(whitespace-only changes)\n")
+			case spent+b.count(body)+1 <= file.allowance:
+				spent += b.count(body) + 1
+				sb.WriteString(body + "\n")
+			default:
+				sb.WriteString(skipped)
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// skipped replaces the lines of a hunk that doesn't fit the budget of Hunks.
+const skipped = "(lines are omitted to fit the limit)\n"
+
+// listed is the heading of a file in the list of Hunks.
+func listed(c Change) string {
+	var status string
+	switch c.Status {
+	case "A":
+		status = "added"
+	case "D":
+		status = "removed"
+	case "M":
+		status = "modified"
+	default:
+		status = fmt.Sprintf("status '%s'", c.Status)
+	}
+	return fmt.Sprintf("File: %s (%s)\n", c.Name, status)
+}
+
+// size is the number of tokens the changes take when rendered in full.
+func (b Budget) size(changes []Change) int {
+	total := 0
+	for _, change := range changes {
+		total += b.count(title(change))
+		if _, ok := ignored(change.Name, b.Ignore); ok {
+			continue
+		}
+		total += b.count(change.DiffBlock)
+	}
+	return total
+}
+
+func (b Budget) render(changes []Change) string {
+	total := 0
+	for _, change := range changes {
+		total += change.LineChanges
+	}
+	return compress(changes, total, b)
+}
+
+// allocate shares the tokens between the files by their relevance.
+// Files that need less than their share give the rest to the others.
+func allocate(files []*compressed, left int) {
+	if left <= 0 {
+		return
+	}
+	order := append([]*compressed{}, files...)
+	sort.SliceStable(order, func(i, j int) bool {
+		return order[i].needed*order[j].importance < order[j].needed*order[i].importance
+	})
+	weights := 0
+	for _, file := range order {
+		weights += file.importance
+	}
+	for _, file := range order {
+		share := left * file.importance / weights
+		file.allowance = min(file.needed, share)
+		left -= file.allowance
+		weights -= file.importance
+	}
+}
+
+func title(c Change) string {
+	var status string
+	switch c.Status {
+	case "A":
+		status = " was added"
+	case "D":
+		status = " was removed"
+	case "M":
+		status = " was modified"
+	default:
+		status = fmt.Sprintf(" has '%s' status", c.Status)
+	}
+	return fmt.Sprintf("File: %s%s\nLines changed: %d\nDiff block:\n", c.Name, status, c.LineChanges)
+}
+
+// render returns the diff block of the file with the hunks that fit its allowance.
+func (f *compressed) render(b Budget) string {
+	if f.collapsed == 0 && f.omitted == 0 && f.needed <= f.allowance {
+		return f.change.DiffBlock
+	}
+	lines := append([]string{}, f.header...)
+	spent := 0
+	for i, hunk := range f.hunks {
+		spent += b.count(hunk) + 1
+		if spent > f.allowance {
+			f.omitted += len(f.hunks) - i
+			break
+		}
+		lines = append(lines, hunk)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (f *compressed) report() string {
+	var notes []string
+	if f.glob != "" {
+		notes = append(notes, fmt.Sprintf("diff is ignored by the '%s' pattern", f.glob))
+	} else if f.omitted > 0 {
+		notes = append(notes, fmt.Sprintf("%d of %d %s", f.omitted, len(f.hunks), omitted))
+	}
+	if f.collapsed > 0 {
+		notes = append(notes, fmt.Sprintf("%d whitespace-only hunks are collapsed", f.collapsed))
+	}
+	if len(notes) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("- %s: %s", f.change.Name, strings.Join(notes, ", "))
+}
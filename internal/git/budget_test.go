@@ -0,0 +1,141 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIgnored(t *testing.T) {
+	tests := []struct {
+		name    string
+		glob    string
+		ignored bool
+	}{
+		{"go.sum", "go.sum", true},
+		{"tools/go.sum", "go.sum", true},
+		{"web/dist/app.min.js", "*.min.js", true},
+		{"vendor/github.com/lib/lib.go", "**/vendor/**", true},
+		{"web/node_modules/left-pad/index.js", "**/node_modules/**", true},
+		{"internal/git/vendor.go", "**/vendor/**", false},
+		{"docs/api.md", "api/*.md", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ok := ignored(test.name, []string{test.glob})
+
+			assert.Equal(t, test.ignored, ok)
+		})
+	}
+}
+
+func TestCompress_IgnoresLockfiles(t *testing.T) {
+	changes := parse(block("main.go", 1, 1) + "\n" + block("go.sum", 50, 1)).Files
+
+	res := compress(changes, 51, Budget{Tokens: DefaultTokens, Ignore: DefaultIgnore()})
+
+	assert.Contains(t, res, "File: go.sum was modified", "Expected the header of the ignored file to be kept")
+	assert.NotContains(t, res, "+go.sum line", "Expected the diff of the ignored file to be dropped")
+	assert.Contains(t, res, "+main.go line 0", "Expected other files to be kept")
+	assert.Contains(t, res, "- go.sum: diff is ignored by the 'go.sum' pattern")
+}
+
+func TestCompress_SharesBudgetByRelevance(t *testing.T) {
+	changes := parse(block("main.go", 20, 10) + "\n" + block("main_test.go", 20, 10) + "\n" + block("small.go", 1, 1)).Files
+
+	res := compress(changes, 401, Budget{Tokens: 1000})
+
+	assert.Contains(t, res, "+small.go line 0", "Expected a small file to be kept whole")
+	assert.Contains(t, res, "+main.go line 0", "Expected the first hunks of a large file to be kept")
+	assert.Contains(t, res, "File: main_test.go was modified", "Expected headers of all files to be kept")
+	assert.Less(t, strings.Count(res, "+main_test.go line"), strings.Count(res, "+main.go line"), "Expected tests to get less budget than sources")
+	assert.Regexp(t, `- main.go: \d+ of 10 hunks are omitted to fit the limit`, res)
+	assert.LessOrEqual(t, estimate(res), 1000, "Expected the diff to fit the budget")
+}
+
+func TestCompress_CollapsesWhitespaceOnlyHunks(t *testing.T) {
+	diff := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1,2 +1,2 @@\n-func main() {\n+func main()  {\n\tfmt.Println()\n@@ -9 +9 @@\n-a := 1\n+a := 2"
+
+	res := compress(parse(diff).Files, 4, Budget{Tokens: DefaultTokens})
+
+	assert.Contains(t, res, "@@ -1,2 +1,2 @@\n(whitespace-only changes)\n@@ -9 +9 @@\n-a := 1\n+a := 2")
+	assert.Contains(t, res, "- main.go: 1 whitespace-only hunks are collapsed")
+}
+
+func TestCompress_KeepsSmallDiffsIntact(t *testing.T) {
+	diff := block("main.go", 3, 2)
+
+	res := compress(parse(diff).Files, 6, Budget{Tokens: DefaultTokens, Ignore: DefaultIgnore()})
+
+	assert.Equal(t, "Total lines changed: 6\n\n\nFile: main.go was modified\nLines changed: 6\nDiff block:\n"+diff, res)
+}
+
+func TestParse_MergesBlocksOfTheSameFile(t *testing.T) {
+	rep := parse("diff --git a/new.go b/new.go\nnew file mode 100644\n--- /dev/null\n+++ b/new.go\n@@ -0,0 +1 @@\n+package main\n\n" + block("new.go", 1, 1))
+
+	assert.Len(t, rep.Files, 1)
+	assert.Equal(t, "A", rep.Files[0].Status)
+	assert.Equal(t, 2, rep.TotalLines)
+	assert.Len(t, rep.Files[0].Hunks(), 2)
+}
+
+// block builds a diff of the file with the given number of hunks, each changing the given number of lines.
+func block(name string, lines, hunks int) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("diff --git a/%s b/%s\n--- a/%s\n+++ b/%s", name, name, name, name))
+	for h := 0; h < hunks; h++ {
+		sb.WriteString(fmt.Sprintf("\n@@ -%d,%d +%d,%d @@", h*100+1, lines, h*100+1, lines))
+		for l := 0; l < lines; l++ {
+			sb.WriteString(fmt.Sprintf("\n+%s line %d of hunk %d", name, l, h))
+		}
+	}
+	return sb.String()
+}
+
+func TestTruncated(t *testing.T) {
+	changes := parse(block("main.go", 20, 10) + "\n" + block("go.sum", 5, 1)).Files
+
+	assert.True(t, Truncated(compress(changes, 205, Budget{Tokens: 500, Ignore: DefaultIgnore()})), "Expected omitted hunks to be noticed")
+	assert.False(t, Truncated(compress(changes, 205, Budget{Tokens: DefaultTokens, Ignore: DefaultIgnore()})), "Expected ignored files not to count")
+}
+
+func TestHunks_ListsNumberedHunks(t *testing.T) {
+	changes := []Change{
+		{Name: "main.go", Status: "M", DiffBlock: "diff --git a/main.go b/main.go\n@@ -1 +1 @@\n-a\n+b"},
+		{Name: "old.go", Status: "D"},
+	}
+
+	listed := DefaultBudget().Hunks(changes)
+
+	assert.Equal(t, "File: main.go (modified)\nHunk 1:\n@@ -1 +1 @@\n-a\n+b\n\nFile: old.go (removed)\n\n", listed)
+}
+
+func TestHunks_KeepsEveryHunkWithinBudget(t *testing.T) {
+	changes := parse(block("main.go", 20, 10) + "\n" + block("go.sum", 5, 1) + "\n" + block("small.go", 1, 1)).Files
+
+	listed := Budget{Tokens: 800, Ignore: DefaultIgnore()}.Hunks(changes)
+
+	assert.Equal(t, 12, strings.Count(listed, "\nHunk "), "Expected every hunk to be listed")
+	assert.Contains(t, listed, "Hunk 10:\n@@ -901,20 +901,20 @@\n(lines are omitted to fit the limit)", "Expected the last hunks to keep only their headers")
+	assert.Contains(t, listed, "+main.go line 0 of hunk 0", "Expected the first hunks to be kept")
+	assert.Contains(t, listed, "(diff is ignored by the 'go.sum' pattern)")
+	assert.Contains(t, listed, "+small.go line 0 of hunk 0", "Expected a small file to be kept")
+	assert.LessOrEqual(t, estimate(listed), 800, "Expected the list to fit the budget")
+}
+
+func TestChunks_KeepsDirectoriesTogether(t *testing.T) {
+	changes := parse(block("a/x.go", 1, 1) + "\n" + block("a/y.go", 1, 1) + "\n" + block("b/one.go", 20, 1) + "\n" + block("b/two.go", 20, 1)).Files
+
+	chunks := Budget{Tokens: 300}.Chunks(changes)
+
+	assert.Len(t, chunks, 2)
+	assert.Contains(t, chunks[0], "File: a/x.go")
+	assert.Contains(t, chunks[0], "File: a/y.go")
+	assert.Contains(t, chunks[0], "File: b/one.go", "Expected a file of a large directory to be packed with others")
+	assert.Contains(t, chunks[1], "File: b/two.go")
+	for _, chunk := range chunks {
+		assert.False(t, Truncated(chunk), "Expected every chunk to fit the budget")
+	}
+}
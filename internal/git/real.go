@@ -78,42 +78,12 @@ func (r *real) CurrentBranch() (string, error) {
 	return branchName, nil
 }
 
-func (r *real) BaseBranch() (string, error) {
-	_, errMain := r.Run("show-ref", "--verify", "--quiet", "refs/heads/main")
-	_, errMaster := r.Run("show-ref", "--verify", "--quiet", "refs/heads/master")
-	if errMain == nil && errMaster == nil {
-		return "", fmt.Errorf("both 'main' and 'master' branches exist")
-	} else if errMain == nil {
-		return "main", nil
-	} else if errMaster == nil {
-		return "master", nil
-	} else {
-		return "", fmt.Errorf("neither 'main' nor 'master' branch exists")
-	}
-}
-
 func (r *real) Diff() (string, error) {
 	base, err := r.BaseBranch()
 	if err != nil {
 		return "", fmt.Errorf("error determining base branch: %v", err)
 	}
-	out, diffErr := r.Run("diff", base, "--cached")
-	if diffErr != nil {
-		return "", err
-	} else {
-		diff := out
-		names, err := r.Run("diff", base, "--cached", "--name-status")
-		if err != nil {
-			r.log.Error("Can't run get a files status diff: '%v'", err)
-			os.Exit(1)
-		}
-		stat, err := r.Run("diff", base, "--cached", "--stat")
-		if err != nil {
-			r.log.Error("Can't run get a stat diff: '%v'", err)
-			os.Exit(1)
-		}
-		return NewSummary(diff, stat, names).Render(), nil
-	}
+	return DiffWith(r, base, Committed, DefaultBudget())
 }
 
 func (r *real) CurrentDiff() (string, error) {
@@ -125,8 +95,7 @@ func (r *real) CurrentDiff() (string, error) {
 	if stErr != nil {
 		return "", stErr
 	}
-	diff := unstaged + "\n" + staged
-	return firstNLines(firstNChars(diff, 120*400), 400), nil
+	return DefaultBudget().Render(unstaged + "\n" + staged), nil
 }
 
 func (r *real) CommitMessage() (string, error) {
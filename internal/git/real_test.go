@@ -481,6 +481,22 @@ func TestRealGit_CantRetrieveDiff(t *testing.T) {
 	assert.Contains(t, err.Error(), "neither 'main' nor 'master' branch exists")
 }
 
+func TestRealGit_RendersCurrentDiffWithinDefaultBudget(t *testing.T) {
+	repo, cleanup := setup(t)
+	defer cleanup()
+	err := os.WriteFile(filepath.Join(repo, "go.sum"), []byte("Hello, World!"), 0644)
+	require.NoError(t, err, "Error writing to file")
+	git, err := NewGit(executor.NewReal(), repo)
+	require.NoError(t, err, "git should be createad without any problems")
+	require.NoError(t, git.AddAll(), "Expected no error during adding all changes")
+
+	diff, err := git.CurrentDiff()
+
+	require.NoError(t, err, "Expected no error during current diff retrieval")
+	assert.NotContains(t, diff, "Hello, World!", "Expected the ignored file to keep only its header")
+	assert.Contains(t, diff, "diff is ignored by the 'go.sum' pattern")
+}
+
 func TestRealGit_RetrieveCurrentDiff(t *testing.T) {
 	repo, cleanup := setup(t)
 	defer cleanup()
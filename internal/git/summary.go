@@ -12,7 +12,8 @@ type summary struct {
 	names string
 }
 
-type change struct {
+// Change is a changed file parsed from a diff.
+type Change struct {
 	Name        string
 	Status      string // A, M, D, etc.
 	StatLine    string
@@ -21,7 +22,7 @@ type change struct {
 }
 
 type report struct {
-	Files      []change
+	Files      []Change
 	TotalLines int
 }
 
@@ -29,85 +30,116 @@ func NewSummary(diff string, stat string, names string) *summary {
 	return &summary{diff: diff, stat: stat, names: names}
 }
 
-func (s *summary) Render() string {
+// Changes returns the changed files ordered by name.
+func (s *summary) Changes() []Change {
 	rep := combine(s.names, s.stat, s.diff)
-	return rep.pretty()
+	sort.Slice(rep.Files, func(i, j int) bool {
+		return rep.Files[i].Name < rep.Files[j].Name
+	})
+	return rep.Files
 }
 
-func (report *report) pretty() string {
-	sort.Slice(report.Files, func(i, j int) bool {
-		return report.Files[i].Name < report.Files[j].Name
-	})
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("Total lines changed: %d\n", report.TotalLines))
-	if report.large() {
-		for _, file := range report.Files {
-			sb.WriteString("\n\n")
-			sb.WriteString(fmt.Sprintf("File: %s", file.Name))
-			status := file.Status
-			switch status {
-			case "A":
-				sb.WriteString(" was added")
-				sb.WriteString(fmt.Sprintf("\nLines changed: %d\n", file.LineChanges))
-				sb.WriteString("Diff block:\n")
-				sb.WriteString(file.DiffBlock)
-			case "D":
-				sb.WriteString(" was removed")
-			case "M":
-				sb.WriteString(" was modified")
-				sb.WriteString(fmt.Sprintf("\nLines changed: %d\n", file.LineChanges))
-				sb.WriteString("Diff block:\n")
-				sb.WriteString(file.DiffBlock)
-			default:
-				sb.WriteString(fmt.Sprintf(" has '%s' status", status))
-			}
+// Hunks returns the hunks of the diff block, each starting with its '@@' line.
+func (c Change) Hunks() []string {
+	_, hunks := c.split()
+	return hunks
+}
+
+// Patch returns a patch with the file header and only the given hunks, numbered from 1.
+// The patch can be applied with 'git apply'.
+func (c Change) Patch(hunks ...int) (string, error) {
+	header, all := c.split()
+	patch := header
+	for _, n := range hunks {
+		if n < 1 || n > len(all) {
+			return "", fmt.Errorf("file '%s' has no hunk %d, it has %d", c.Name, n, len(all))
 		}
-	} else {
-		for _, file := range report.Files {
-			sb.WriteString("\n\n")
-			sb.WriteString(fmt.Sprintf("File: %s", file.Name))
-			status := file.Status
-			switch status {
-			case "A":
-				sb.WriteString(" was added")
-			case "D":
-				sb.WriteString(" was removed")
-			case "M":
-				sb.WriteString(" was modified")
-			default:
-				sb.WriteString(fmt.Sprintf(" has '%s' status", status))
+		patch = append(patch, all[n-1])
+	}
+	return strings.Join(patch, "\n") + "\n", nil
+}
+
+func (c Change) split() ([]string, []string) {
+	var header []string
+	var hunks []string
+	var current []string
+	for _, line := range strings.Split(strings.TrimRight(c.DiffBlock, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			if current != nil {
+				hunks = append(hunks, strings.Join(current, "\n"))
 			}
-			sb.WriteString(fmt.Sprintf("\nLines changed: %d\n", file.LineChanges))
-			sb.WriteString("Diff block:\n")
-			sb.WriteString(file.DiffBlock)
+			current = []string{line}
+		case current != nil:
+			current = append(current, line)
+		default:
+			header = append(header, line)
 		}
 	}
-	return firstNLines(firstNChars(sb.String(), 120*400), 400)
+	if current != nil {
+		hunks = append(hunks, strings.Join(current, "\n"))
+	}
+	return header, hunks
 }
 
-func (report *report) large() bool {
-	return report.TotalLines > 400
+// Render returns the changes as a list of files with their diff blocks that fits the budget.
+func (s *summary) Render(b Budget) string {
+	rep := combine(s.names, s.stat, s.diff)
+	return rep.pretty(b)
 }
 
-// Return only the first N lines of the report
-func firstNLines(s string, n int) string {
-	lines := strings.Split(s, "\n")
-	if len(lines) > n {
-		lines = lines[:n]
-	}
-	return strings.Join(lines, "\n")
+func (report *report) pretty(b Budget) string {
+	sort.Slice(report.Files, func(i, j int) bool {
+		return report.Files[i].Name < report.Files[j].Name
+	})
+	return compress(report.Files, report.TotalLines, b)
 }
 
-// Return only the first N charactes of the report
-func firstNChars(s string, n int) string {
-	if n <= 0 {
-		return ""
+// Build the report from a unified diff alone,
+// taking the status of each file from its header and counting the changed lines.
+// A file that appears several times, e.g. with staged and unstaged changes, gets all its blocks.
+func parse(diff string) *report {
+	blocks := make(map[string][]string)
+	var names []string
+	var current string
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git") {
+			parts := strings.Fields(line)
+			if len(parts) < 4 {
+				current = ""
+				continue
+			}
+			current = strings.TrimPrefix(parts[3], "b/")
+			if _, ok := blocks[current]; !ok {
+				names = append(names, current)
+			}
+		}
+		if current != "" && (len(blocks[current]) > 0 || line != "") {
+			blocks[current] = append(blocks[current], line)
+		}
 	}
-	runes := []rune(s)
-	if len(runes) > n {
-		return string(runes[:n])
+	report := &report{}
+	for _, name := range names {
+		entry := Change{Name: name, Status: "M", DiffBlock: strings.TrimRight(strings.Join(blocks[name], "\n"), "\n")}
+		hunk := false
+		for _, line := range blocks[name] {
+			switch {
+			case strings.HasPrefix(line, "@@"):
+				hunk = true
+			case !hunk && strings.HasPrefix(line, "new file mode"):
+				entry.Status = "A"
+			case !hunk && strings.HasPrefix(line, "deleted file mode"):
+				entry.Status = "D"
+			case hunk && (strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-")):
+				entry.LineChanges++
+			case strings.HasPrefix(line, "diff --git"):
+				hunk = false
+			}
+		}
+		report.Files = append(report.Files, entry)
+		report.TotalLines += entry.LineChanges
 	}
-	return s
+	return report
 }
 
 // Combine everything
@@ -117,7 +149,7 @@ func combine(names, stat, diff string) *report {
 	diffMap := parseDiff(diff)
 	report := &report{}
 	for file, files := range statusMap {
-		entry := change{
+		entry := Change{
 			Name:   file,
 			Status: files,
 		}
@@ -159,9 +191,9 @@ func parseNames(input string) map[string]string {
 // simple.txt | 7 +++++++
 // u1.txt     | 7 +++++++
 // 3 files changed, 15 insertions(+)
-func parseStat(input string) map[string]*change {
+func parseStat(input string) map[string]*Change {
 	lines := strings.Split(strings.TrimSpace(input), "\n")
-	result := make(map[string]*change)
+	result := make(map[string]*Change)
 	for _, line := range lines {
 		if !strings.Contains(line, "|") {
 			continue
@@ -170,7 +202,7 @@ func parseStat(input string) map[string]*change {
 		file := strings.TrimSpace(parts[0])
 		meta := strings.TrimSpace(parts[1])
 		count := strings.Count(meta, "+") + strings.Count(meta, "-")
-		result[file] = &change{
+		result[file] = &Change{
 			Name:        file,
 			StatLine:    line,
 			LineChanges: count,
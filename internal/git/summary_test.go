@@ -1,10 +1,10 @@
 package git
 
 import (
-	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -101,34 +101,42 @@ func TestSummary_Render(t *testing.T) {
         A	simple.txt
         A	u1.txt`,
 	)
-	res := summary.Render()
+	res := summary.Render(DefaultBudget())
 
 	assert.Equal(t, Pretty, res)
 }
 
-func TestTrimToNChars_MoreThan400Lines(t *testing.T) {
-	input := generateLongReport(500, 10)
-	trimLength := 400
+func TestSummary_Changes(t *testing.T) {
+	summary := NewSummary(Diff, "diff.txt | 1 +\nu1.txt | 7 +++++++", "A\tu1.txt\nA\tdiff.txt")
 
-	result := firstNLines(input, trimLength)
+	changes := summary.Changes()
 
-	assert.Equal(t, 400, lines(result))
+	require.Len(t, changes, 2)
+	assert.Equal(t, "diff.txt", changes[0].Name, "Expected changes to be ordered by name")
+	assert.Equal(t, "A", changes[0].Status)
+	assert.Equal(t, 1, changes[0].LineChanges)
+	assert.Equal(t, "u1.txt", changes[1].Name)
 }
 
-func TestTrimToNChars_MoreThan120x400chars(t *testing.T) {
-	input := generateLongReport(50, 100)
-	trimLength := 10
-
-	result := firstNChars(input, trimLength)
-
-	assert.Equal(t, 10, len(result))
-}
-
-func generateLongReport(lines int, charsPerLine int) string {
-	line := strings.Repeat("a", charsPerLine)
-	return strings.Repeat(line+"\n", lines)
-}
-
-func lines(s string) int {
-	return strings.Count(s, "\n") + 1
+func TestChange_Patch(t *testing.T) {
+	change := Change{Name: "main.go", DiffBlock: `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,2 +1,2 @@
+-one
++uno
+ two
+@@ -10,2 +10,2 @@ func main() {
+-ten
++diez
+ eleven`}
+
+	patch, err := change.Patch(2)
+
+	require.NoError(t, err, "Expected the patch to be built")
+	assert.Len(t, change.Hunks(), 2)
+	assert.Equal(t, "diff --git a/main.go b/main.go\nindex 1111111..2222222 100644\n--- a/main.go\n+++ b/main.go\n@@ -10,2 +10,2 @@ func main() {\n-ten\n+diez\n eleven\n", patch)
+	_, err = change.Patch(3)
+	assert.Error(t, err, "Expected an error for a missing hunk")
 }
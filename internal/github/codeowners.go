@@ -0,0 +1,113 @@
+package github
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// CodeownersPaths are the places GitHub looks for the CODEOWNERS file, in its order.
+var CodeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Codeowners are the rules of a CODEOWNERS file, the last rule matching a file wins.
+type Codeowners struct {
+	rules []rule
+}
+
+// rule is a line of CODEOWNERS: a pattern of paths and their owners, which may be none.
+type rule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// ParseCodeowners reads the content of a CODEOWNERS file.
+// Lines with patterns that can't be read are skipped.
+func ParseCodeowners(content string) Codeowners {
+	var owners Codeowners
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		pattern, err := compile(fields[0])
+		if err != nil {
+			continue
+		}
+		var names []string
+		for _, name := range fields[1:] {
+			if strings.HasPrefix(name, "#") {
+				break
+			}
+			names = append(names, name)
+		}
+		owners.rules = append(owners.rules, rule{pattern: pattern, owners: names})
+	}
+	return owners
+}
+
+// Reviewers returns the owners of the files that can be requested for a review, in alphabetical order:
+// logins of users and 'org/team' names without '@'. Owners given by email are skipped.
+func (c Codeowners) Reviewers(files []string) []string {
+	seen := map[string]bool{}
+	var res []string
+	for _, file := range files {
+		for _, owner := range c.owners(file) {
+			if !strings.HasPrefix(owner, "@") {
+				continue
+			}
+			name := strings.TrimPrefix(owner, "@")
+			if !seen[name] {
+				seen[name] = true
+				res = append(res, name)
+			}
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// owners returns the owners of the last rule that matches the file.
+func (c Codeowners) owners(file string) []string {
+	file = strings.TrimPrefix(file, "/")
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].pattern.MatchString(file) {
+			return c.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// compile turns a gitignore-like pattern into a regular expression of paths.
+// A pattern with a slash at the beginning or in the middle is relative to the root,
+// others match at any depth, and a matched directory owns everything inside it,
+// except for patterns like 'docs/*' that own only the files directly in it.
+func compile(pattern string) (*regexp.Regexp, error) {
+	trimmed := strings.Trim(pattern, "/")
+	var expr strings.Builder
+	if strings.HasPrefix(pattern, "/") || strings.Contains(trimmed, "/") {
+		expr.WriteString("^")
+	} else {
+		expr.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(trimmed); i++ {
+		switch {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case trimmed[i] == '*':
+			expr.WriteString("[^/]*")
+		case trimmed[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(trimmed[i : i+1]))
+		}
+	}
+	if !strings.HasSuffix(trimmed, "/*") {
+		expr.WriteString("(?:/.*)?")
+	}
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
@@ -0,0 +1,32 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodeowners_LastRuleWins(t *testing.T) {
+	owners := ParseCodeowners("# owners\n*       @everyone\n*.go    @gophers # go code\n/internal/cache/\n")
+
+	assert.Equal(t, []string{"gophers"}, owners.Reviewers([]string{"cmd/main.go"}))
+	assert.Equal(t, []string{"everyone"}, owners.Reviewers([]string{"README.md"}))
+	assert.Empty(t, owners.Reviewers([]string{"internal/cache/file.go"}), "Expected a rule without owners to drop them")
+}
+
+func TestCodeowners_MatchesLikeGitHub(t *testing.T) {
+	owners := ParseCodeowners("docs/*  @writers\napps/  @apps\n**/logs @loggers\n/build/logs/ @ops\n")
+
+	assert.Equal(t, []string{"writers"}, owners.Reviewers([]string{"docs/guide.md"}))
+	assert.Empty(t, owners.Reviewers([]string{"docs/api/index.md"}), "Expected 'docs/*' to skip nested files")
+	assert.Equal(t, []string{"apps"}, owners.Reviewers([]string{"src/apps/main.go"}))
+	assert.Equal(t, []string{"ops"}, owners.Reviewers([]string{"build/logs/today.txt"}))
+	assert.Equal(t, []string{"loggers"}, owners.Reviewers([]string{"deep/inside/logs/file.txt"}))
+	assert.Equal(t, []string{"loggers"}, owners.Reviewers([]string{"src/build/logs/today.txt"}), "Expected '/build/logs/' to match only at the root")
+}
+
+func TestCodeowners_SkipsEmails(t *testing.T) {
+	owners := ParseCodeowners("* dev@example.com @org/team @alice @alice\n")
+
+	assert.Equal(t, []string{"alice", "org/team"}, owners.Reviewers([]string{"main.go", "go.mod"}))
+}
@@ -5,4 +5,33 @@ type Github interface {
 	Labels() ([]string, error)
 	Remotes() ([]string, error)
 	PullRequestByBranch(branch string) (title string, body string, err error)
+	CreatePullRequest(pr PullRequest) (url string, err error)
+	CreateIssue(issue Issue) (url string, err error)
+	User() (login string, err error)
+}
+
+// PullRequest describes a pull request to be opened on GitHub.
+// Repo is the 'owner/name' repository to open it in, the target repository when empty.
+// Head is the branch with the changes, prefixed with 'owner:' when it's in a fork, Base is the branch to merge them into.
+// Reviewers are logins of users or 'org/team' names, Milestone is the title or the number of a milestone.
+type PullRequest struct {
+	Repo      string
+	Title     string
+	Body      string
+	Labels    []string
+	Base      string
+	Head      string
+	Draft     bool
+	Reviewers []string
+	Assignees []string
+	Milestone string
+}
+
+// Issue describes an issue to be opened on GitHub.
+// Repo is the 'owner/name' repository to open it in, the target repository when empty.
+type Issue struct {
+	Repo   string
+	Title  string
+	Body   string
+	Labels []string
 }
@@ -4,6 +4,8 @@ import "fmt"
 
 type MockGithub struct {
 	Error error
+	// Created is the last pull request passed to CreatePullRequest.
+	Created PullRequest
 }
 
 func NewMock() *MockGithub {
@@ -25,3 +27,22 @@ func (m *MockGithub) Remotes() ([]string, error) {
 func (m *MockGithub) PullRequestByBranch(branch string) (string, string, error) {
 	return fmt.Sprintf("mock title for branch '%s'", branch), fmt.Sprintf("mock body for branch '%s'", branch), m.Error
 }
+
+func (m *MockGithub) CreatePullRequest(pr PullRequest) (string, error) {
+	if m.Error != nil {
+		return "", m.Error
+	}
+	m.Created = pr
+	return fmt.Sprintf("https://github.com/mock/remote/pull/%s", pr.Head), nil
+}
+
+func (m *MockGithub) CreateIssue(issue Issue) (string, error) {
+	if m.Error != nil {
+		return "", m.Error
+	}
+	return "https://github.com/mock/remote/issues/1", nil
+}
+
+func (m *MockGithub) User() (string, error) {
+	return "mock-user", m.Error
+}
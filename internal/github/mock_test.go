@@ -39,3 +39,23 @@ func TestMockGithub_Remotes(t *testing.T) {
 	require.NoError(t, err, "mock object should not return errors")
 	assert.Equal(t, expected, acutal)
 }
+
+func TestMockGithub_CreatePullRequest(t *testing.T) {
+	gh := NewMock()
+
+	url, err := gh.CreatePullRequest(PullRequest{Title: "title", Head: "feature", Base: "main"})
+
+	require.NoError(t, err, "mock object should not return errors")
+	assert.Equal(t, "https://github.com/mock/remote/pull/feature", url)
+	assert.Equal(t, "feature", gh.Created.Head, "mock should remember the pull request")
+}
+
+func TestMockGithub_CreateIssue_Error(t *testing.T) {
+	gh := NewMock()
+	gh.Error = fmt.Errorf("mock error")
+
+	url, err := gh.CreateIssue(Issue{Title: "title"})
+
+	require.Error(t, err, "mock object should return the configured error")
+	assert.Empty(t, url)
+}
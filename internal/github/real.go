@@ -1,7 +1,9 @@
 package github
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -44,6 +46,51 @@ type pullRequest struct {
 	Body   string `json:"body"`
 }
 
+type newPullRequest struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Draft bool   `json:"draft"`
+}
+
+type newIssue struct {
+	Title  string   `json:"title"`
+	Body   string   `json:"body"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+type newLabels struct {
+	Labels []string `json:"labels"`
+}
+
+type newReviewers struct {
+	Reviewers []string `json:"reviewers,omitempty"`
+	Teams     []string `json:"team_reviewers,omitempty"`
+}
+
+type newAssignees struct {
+	Assignees []string `json:"assignees"`
+}
+
+type newMilestone struct {
+	Milestone int `json:"milestone"`
+}
+
+type milestone struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+}
+
+type user struct {
+	Login string `json:"login"`
+}
+
+type created struct {
+	Number int    `json:"number"`
+	Url    string `json:"html_url"`
+}
+
 func NewGithub(url string, gs git.Git, token string, ch cache.AidyCache) *github {
 	return &github{
 		client: &http.Client{},
@@ -161,6 +208,172 @@ func (r *github) PullRequestByBranch(branch string) (string, string, error) {
 	return prs[0].Title, prs[0].Body, nil
 }
 
+// CreatePullRequest opens the pull request and then applies its labels, reviewers, assignees and milestone.
+// Each of them is applied even if another one fails, the URL is returned along with all their errors.
+func (r *github) CreatePullRequest(pr PullRequest) (string, error) {
+	target := pr.Repo
+	if target == "" {
+		target = r.ch.Remote()
+	}
+	if target == "" {
+		return "", fmt.Errorf("cannot determine where to create a pull request, please set the target repository")
+	}
+	if pr.Head == "" || pr.Base == "" {
+		return "", fmt.Errorf("both head and base branches are required to create a pull request, got head '%s' and base '%s'", pr.Head, pr.Base)
+	}
+	url := fmt.Sprintf("%s/repos/%s/pulls", r.url, target)
+	r.log.Debug("creating a pull request from '%s' into '%s' using the following url: %s", pr.Head, pr.Base, url)
+	var res created
+	err := r.post(url, newPullRequest{Title: pr.Title, Body: pr.Body, Head: pr.Head, Base: pr.Base, Draft: pr.Draft}, &res)
+	if err != nil {
+		return "", fmt.Errorf("cannot create a pull request: %w", err)
+	}
+	r.log.Debug("pull request #%d was created: %s", res.Number, res.Url)
+	var errs []error
+	if len(pr.Labels) > 0 {
+		// Pull requests don't accept labels on creation,
+		// they are assigned through the issues API instead.
+		url = fmt.Sprintf("%s/repos/%s/issues/%d/labels", r.url, target, res.Number)
+		if err := r.post(url, newLabels{Labels: pr.Labels}, nil); err != nil {
+			errs = append(errs, fmt.Errorf("labels were not applied: %w", err))
+		}
+	}
+	if len(pr.Reviewers) > 0 {
+		url = fmt.Sprintf("%s/repos/%s/pulls/%d/requested_reviewers", r.url, target, res.Number)
+		if err := r.post(url, reviewers(pr.Reviewers), nil); err != nil {
+			errs = append(errs, fmt.Errorf("reviewers were not requested: %w", err))
+		}
+	}
+	if len(pr.Assignees) > 0 {
+		url = fmt.Sprintf("%s/repos/%s/issues/%d/assignees", r.url, target, res.Number)
+		if err := r.post(url, newAssignees{Assignees: pr.Assignees}, nil); err != nil {
+			errs = append(errs, fmt.Errorf("assignees were not added: %w", err))
+		}
+	}
+	if pr.Milestone != "" {
+		number, err := r.milestone(target, pr.Milestone)
+		if err == nil {
+			url = fmt.Sprintf("%s/repos/%s/issues/%d", r.url, target, res.Number)
+			err = r.send("PATCH", url, newMilestone{Milestone: number}, nil)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("the milestone was not set: %w", err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return res.Url, fmt.Errorf("pull request %s was created, but:\n%w", res.Url, err)
+	}
+	return res.Url, nil
+}
+
+// reviewers splits the reviewers into users and teams, GitHub requests them separately.
+// Teams are named like 'org/team', the API expects only the 'team' part.
+func reviewers(names []string) newReviewers {
+	var res newReviewers
+	for _, name := range names {
+		name = strings.TrimPrefix(name, "@")
+		if _, team, found := strings.Cut(name, "/"); found {
+			res.Teams = append(res.Teams, team)
+		} else {
+			res.Reviewers = append(res.Reviewers, name)
+		}
+	}
+	return res
+}
+
+// milestone finds the number of the open milestone by its title, a number is used as is.
+func (r *github) milestone(target, name string) (int, error) {
+	if number, err := strconv.Atoi(name); err == nil {
+		return number, nil
+	}
+	var milestones []milestone
+	url := fmt.Sprintf("%s/repos/%s/milestones?state=open&per_page=100", r.url, target)
+	if err := r.send("GET", url, nil, &milestones); err != nil {
+		return 0, err
+	}
+	for _, each := range milestones {
+		if strings.EqualFold(each.Title, name) {
+			return each.Number, nil
+		}
+	}
+	return 0, fmt.Errorf("milestone '%s' not found in '%s'", name, target)
+}
+
+func (r *github) CreateIssue(issue Issue) (string, error) {
+	target := issue.Repo
+	if target == "" {
+		target = r.ch.Remote()
+	}
+	if target == "" {
+		return "", fmt.Errorf("cannot determine where to create an issue, please set the target repository")
+	}
+	url := fmt.Sprintf("%s/repos/%s/issues", r.url, target)
+	r.log.Debug("creating an issue using the following url: %s", url)
+	var res created
+	if err := r.post(url, newIssue{Title: issue.Title, Body: issue.Body, Labels: issue.Labels}, &res); err != nil {
+		return "", fmt.Errorf("cannot create an issue: %w", err)
+	}
+	r.log.Debug("issue #%d was created: %s", res.Number, res.Url)
+	return res.Url, nil
+}
+
+// User returns the login of the user the token belongs to.
+func (r *github) User() (string, error) {
+	var res user
+	if err := r.send("GET", r.url+"/user", nil, &res); err != nil {
+		return "", fmt.Errorf("cannot get the authenticated user: %w", err)
+	}
+	return res.Login, nil
+}
+
+// post sends the payload as JSON to the given url and decodes the response into out.
+// If out is nil, the response body is ignored.
+func (r *github) post(url string, payload any, out any) error {
+	return r.send("POST", url, payload, out)
+}
+
+// send makes a request with the payload as JSON, if there is one, and reads the JSON response into out.
+func (r *github) send(method, url string, payload any, out any) error {
+	var content io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("error marshaling request json: %w", err)
+		}
+		content = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, url, content)
+	if err != nil {
+		return fmt.Errorf("cannot create a new %s request: %w", method, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+r.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request to '%s': %w", url, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			r.log.Error("error closing response body: %v", err)
+		}
+	}()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response from '%s': '%s', %s", url, resp.Status, strings.TrimSpace(string(body)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("error unmarshaling response json: %w", err)
+	}
+	return nil
+}
+
 func (r *github) Remotes() ([]string, error) {
 	lines, err := r.git.Remotes()
 	if err != nil {
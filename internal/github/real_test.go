@@ -1,6 +1,7 @@
 package github
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -95,7 +96,7 @@ func TestRealGithub_Remotes(t *testing.T) {
 
 func TestRealGithub_Description_NoRemote(t *testing.T) {
 	gh := NewGithub("http://example.com", git.NewMock(), "", cache.NewMockAidyCache())
-	gh.ch.WithRemote("")
+	_ = gh.ch.WithRemote("")
 
 	description, err := gh.Description("123")
 
@@ -106,7 +107,7 @@ func TestRealGithub_Description_NoRemote(t *testing.T) {
 
 func TestRealGithub_Labels_NoRemote(t *testing.T) {
 	gh := NewGithub("http://example.com", git.NewMock(), "", cache.NewMockAidyCache())
-	gh.ch.WithRemote("")
+	_ = gh.ch.WithRemote("")
 
 	labels, err := gh.Labels()
 
@@ -117,7 +118,7 @@ func TestRealGithub_Labels_NoRemote(t *testing.T) {
 
 func TestRealGithub_Labels_UrlParsingError(t *testing.T) {
 	gh := NewGithub("\\invalid://url", git.NewMock(), "", cache.NewMockAidyCache())
-	gh.ch.WithRemote("invalid-url")
+	_ = gh.ch.WithRemote("invalid-url")
 
 	labels, err := gh.Labels()
 
@@ -171,7 +172,7 @@ func TestRealGithub_PullRequestByBranch_NotFound(t *testing.T) {
 
 func TestRealGithub_PullRequestByBranch_NoRemote(t *testing.T) {
 	gh := NewGithub("http://example.com", git.NewMock(), "", cache.NewMockAidyCache())
-	gh.ch.WithRemote("")
+	_ = gh.ch.WithRemote("")
 
 	title, body, err := gh.PullRequestByBranch("feature-branch")
 
@@ -183,7 +184,7 @@ func TestRealGithub_PullRequestByBranch_NoRemote(t *testing.T) {
 
 func TestRealGithub_Labels_InvalidProtocol(t *testing.T) {
 	gh := NewGithub("invalid://protocol", git.NewMock(), "", cache.NewMockAidyCache())
-	gh.ch.WithRemote("invalid-protocol")
+	_ = gh.ch.WithRemote("invalid-protocol")
 
 	labels, err := gh.Labels()
 
@@ -195,7 +196,7 @@ func TestRealGithub_Labels_InvalidProtocol(t *testing.T) {
 
 func TestRealGithub_Labels_DoRequestError(t *testing.T) {
 	gh := NewGithub("http://example.com", git.NewMock(), "", cache.NewMockAidyCache())
-	gh.ch.WithRemote("valid-repo")
+	_ = gh.ch.WithRemote("valid-repo")
 	gh.client = &http.Client{
 		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
 			return nil, fmt.Errorf("simulated request error")
@@ -222,7 +223,7 @@ func TestRealGithub_Labels_500Response(t *testing.T) {
 	}))
 	defer ts.Close()
 	gh := NewGithub(ts.URL, git.NewMock(), "", cache.NewMockAidyCache())
-	gh.ch.WithRemote("valid-repo")
+	_ = gh.ch.WithRemote("valid-repo")
 
 	labels, err := gh.Labels()
 
@@ -238,7 +239,7 @@ func TestRealGithub_Labels_Non404Response(t *testing.T) {
 	}))
 	defer ts.Close()
 	gh := NewGithub(ts.URL, git.NewMock(), "", cache.NewMockAidyCache())
-	gh.ch.WithRemote("valid-repo")
+	_ = gh.ch.WithRemote("valid-repo")
 
 	labels, err := gh.Labels()
 
@@ -255,7 +256,7 @@ func TestRealGithub_Labels_UnmarshalError(t *testing.T) {
 	}))
 	defer ts.Close()
 	gh := NewGithub(ts.URL, git.NewMock(), "", cache.NewMockAidyCache())
-	gh.ch.WithRemote("valid-repo")
+	_ = gh.ch.WithRemote("valid-repo")
 
 	labels, err := gh.Labels()
 
@@ -275,3 +276,234 @@ func TestRealGithub_Remotes_Error(t *testing.T) {
 	assert.Contains(t, err.Error(), "simulated git error")
 	assert.Nil(t, remotes, "Remotes should be nil on error")
 }
+
+func TestRealGithub_CreatePullRequest(t *testing.T) {
+	var requests []string
+	var payload map[string]any
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.URL.Path == "/repos/mock/remote/pulls" {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		}
+		w.WriteHeader(http.StatusCreated)
+		if _, err := w.Write([]byte(`{"number": 7, "html_url": "https://github.com/mock/remote/pull/7"}`)); err != nil {
+			t.Errorf("Error writing response: %v", err)
+		}
+	}))
+	defer ts.Close()
+	gh := NewGithub(ts.URL, git.NewMock(), "", cache.NewMockAidyCache())
+
+	url, err := gh.CreatePullRequest(PullRequest{Title: "Title", Body: "Body", Labels: []string{"bug"}, Base: "main", Head: "42-feature", Draft: true})
+
+	require.NoError(t, err, "CreatePullRequest should not return an error")
+	assert.Equal(t, "https://github.com/mock/remote/pull/7", url)
+	assert.Equal(t, []string{"POST /repos/mock/remote/pulls", "POST /repos/mock/remote/issues/7/labels"}, requests)
+	assert.Equal(t, "Title", payload["title"])
+	assert.Equal(t, "Body", payload["body"])
+	assert.Equal(t, "main", payload["base"])
+	assert.Equal(t, "42-feature", payload["head"])
+	assert.Equal(t, true, payload["draft"])
+}
+
+func TestRealGithub_CreatePullRequest_ReviewersAssigneesMilestone(t *testing.T) {
+	var requests []string
+	payloads := map[string]map[string]any{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == "GET" {
+			_, _ = w.Write([]byte(`[{"number": 2, "title": "v0.9"}, {"number": 3, "title": "v1.0"}]`))
+			return
+		}
+		var payload map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		payloads[r.URL.Path] = payload
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"number": 7, "html_url": "https://github.com/mock/remote/pull/7"}`))
+	}))
+	defer ts.Close()
+	gh := NewGithub(ts.URL, git.NewMock(), "", cache.NewMockAidyCache())
+
+	_, err := gh.CreatePullRequest(PullRequest{Title: "Title", Base: "main", Head: "feature", Reviewers: []string{"alice", "@org/writers"}, Assignees: []string{"bob"}, Milestone: "v1.0"})
+
+	require.NoError(t, err, "CreatePullRequest should not return an error")
+	assert.Equal(t, []string{
+		"POST /repos/mock/remote/pulls",
+		"POST /repos/mock/remote/pulls/7/requested_reviewers",
+		"POST /repos/mock/remote/issues/7/assignees",
+		"GET /repos/mock/remote/milestones",
+		"PATCH /repos/mock/remote/issues/7",
+	}, requests)
+	assert.Equal(t, []any{"alice"}, payloads["/repos/mock/remote/pulls/7/requested_reviewers"]["reviewers"])
+	assert.Equal(t, []any{"writers"}, payloads["/repos/mock/remote/pulls/7/requested_reviewers"]["team_reviewers"])
+	assert.Equal(t, []any{"bob"}, payloads["/repos/mock/remote/issues/7/assignees"]["assignees"])
+	assert.Equal(t, float64(3), payloads["/repos/mock/remote/issues/7"]["milestone"])
+}
+
+func TestRealGithub_CreatePullRequest_UnknownMilestone(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"number": 7, "html_url": "https://github.com/mock/remote/pull/7"}`))
+	}))
+	defer ts.Close()
+	gh := NewGithub(ts.URL, git.NewMock(), "", cache.NewMockAidyCache())
+
+	url, err := gh.CreatePullRequest(PullRequest{Title: "Title", Base: "main", Head: "feature", Milestone: "v2.0"})
+
+	require.Error(t, err, "CreatePullRequest should report the milestone that doesn't exist")
+	assert.Contains(t, err.Error(), "milestone 'v2.0' not found")
+	assert.Equal(t, "https://github.com/mock/remote/pull/7", url, "Expected the created pull request anyway")
+}
+
+func TestRealGithub_CreatePullRequest_AppliesEverythingDespiteFailures(t *testing.T) {
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/repos/mock/remote/pulls":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"number": 7, "html_url": "https://github.com/mock/remote/pull/7"}`))
+		case "/repos/mock/remote/issues/7/labels", "/repos/mock/remote/pulls/7/requested_reviewers":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"message": "Validation Failed"}`))
+		default:
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer ts.Close()
+	gh := NewGithub(ts.URL, git.NewMock(), "", cache.NewMockAidyCache())
+
+	url, err := gh.CreatePullRequest(PullRequest{Title: "Title", Base: "main", Head: "feature", Labels: []string{"bug"}, Reviewers: []string{"alice"}, Assignees: []string{"bob"}, Milestone: "3"})
+
+	require.Error(t, err, "CreatePullRequest should report what was not applied")
+	assert.Contains(t, err.Error(), "labels were not applied")
+	assert.Contains(t, err.Error(), "reviewers were not requested")
+	assert.Equal(t, "https://github.com/mock/remote/pull/7", url, "Expected the created pull request anyway")
+	assert.Contains(t, requests, "POST /repos/mock/remote/issues/7/assignees", "Expected assignees to be added after the failures")
+	assert.Contains(t, requests, "PATCH /repos/mock/remote/issues/7", "Expected the milestone to be set after the failures")
+}
+
+func TestRealGithub_CreatePullRequest_NoLabels(t *testing.T) {
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusCreated)
+		if _, err := w.Write([]byte(`{"number": 7, "html_url": "https://github.com/mock/remote/pull/7"}`)); err != nil {
+			t.Errorf("Error writing response: %v", err)
+		}
+	}))
+	defer ts.Close()
+	gh := NewGithub(ts.URL, git.NewMock(), "", cache.NewMockAidyCache())
+
+	_, err := gh.CreatePullRequest(PullRequest{Title: "Title", Base: "main", Head: "feature"})
+
+	require.NoError(t, err, "CreatePullRequest should not return an error")
+	assert.Equal(t, []string{"POST /repos/mock/remote/pulls"}, requests, "labels should not be requested when there are none")
+}
+
+func TestRealGithub_CreatePullRequest_Rejected(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		if _, err := w.Write([]byte(`{"message": "Validation Failed"}`)); err != nil {
+			t.Errorf("Error writing response: %v", err)
+		}
+	}))
+	defer ts.Close()
+	gh := NewGithub(ts.URL, git.NewMock(), "", cache.NewMockAidyCache())
+
+	url, err := gh.CreatePullRequest(PullRequest{Title: "Title", Base: "main", Head: "feature"})
+
+	require.Error(t, err, "CreatePullRequest should return an error for non-201 response")
+	assert.Contains(t, err.Error(), "422 Unprocessable Entity")
+	assert.Contains(t, err.Error(), "Validation Failed")
+	assert.Empty(t, url)
+}
+
+func TestRealGithub_CreatePullRequest_NoBranches(t *testing.T) {
+	gh := NewGithub("http://example.com", git.NewMock(), "", cache.NewMockAidyCache())
+
+	_, err := gh.CreatePullRequest(PullRequest{Title: "Title"})
+
+	require.Error(t, err, "CreatePullRequest should return an error without head and base")
+	assert.Contains(t, err.Error(), "both head and base branches are required")
+}
+
+func TestRealGithub_CreatePullRequest_NoRemote(t *testing.T) {
+	gh := NewGithub("http://example.com", git.NewMock(), "", cache.NewMockAidyCache())
+	_ = gh.ch.WithRemote("")
+
+	_, err := gh.CreatePullRequest(PullRequest{Title: "Title", Base: "main", Head: "feature"})
+
+	require.Error(t, err, "CreatePullRequest should return an error when no remote is set")
+	assert.Contains(t, err.Error(), "please set the target repository")
+}
+
+func TestRealGithub_User(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/user", r.URL.Path)
+		_, _ = w.Write([]byte(`{"login": "alice"}`))
+	}))
+	defer ts.Close()
+	gh := NewGithub(ts.URL, git.NewMock(), "", cache.NewMockAidyCache())
+
+	login, err := gh.User()
+
+	require.NoError(t, err, "User should not return an error")
+	assert.Equal(t, "alice", login)
+}
+
+func TestRealGithub_CreateIssue(t *testing.T) {
+	var payload map[string]any
+	var path string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusCreated)
+		if _, err := w.Write([]byte(`{"number": 3, "html_url": "https://github.com/mock/remote/issues/3"}`)); err != nil {
+			t.Errorf("Error writing response: %v", err)
+		}
+	}))
+	defer ts.Close()
+	gh := NewGithub(ts.URL, git.NewMock(), "token", cache.NewMockAidyCache())
+
+	url, err := gh.CreateIssue(Issue{Title: "Title", Body: "Body", Labels: []string{"bug", "question"}})
+
+	require.NoError(t, err, "CreateIssue should not return an error")
+	assert.Equal(t, "https://github.com/mock/remote/issues/3", url)
+	assert.Equal(t, "/repos/mock/remote/issues", path)
+	assert.Equal(t, "Title", payload["title"])
+	assert.Equal(t, []any{"bug", "question"}, payload["labels"])
+}
+
+func TestRealGithub_CreateIssue_InGivenRepo(t *testing.T) {
+	var path string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.WriteHeader(http.StatusCreated)
+		if _, err := w.Write([]byte(`{"number": 3, "html_url": "https://github.com/upstream/project/issues/3"}`)); err != nil {
+			t.Errorf("Error writing response: %v", err)
+		}
+	}))
+	defer ts.Close()
+	gh := NewGithub(ts.URL, git.NewMock(), "", cache.NewMockAidyCache())
+
+	_, err := gh.CreateIssue(Issue{Repo: "upstream/project", Title: "Title"})
+
+	require.NoError(t, err, "CreateIssue should not return an error")
+	assert.Equal(t, "/repos/upstream/project/issues", path, "Expected the given repository instead of the target one")
+}
+
+func TestRealGithub_CreateIssue_NoRemote(t *testing.T) {
+	gh := NewGithub("http://example.com", git.NewMock(), "", cache.NewMockAidyCache())
+	_ = gh.ch.WithRemote("")
+
+	_, err := gh.CreateIssue(Issue{Title: "Title"})
+
+	require.Error(t, err, "CreateIssue should return an error when no remote is set")
+	assert.Contains(t, err.Error(), "please set the target repository")
+}
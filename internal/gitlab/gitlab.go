@@ -1,5 +1,20 @@
 package gitlab
 
 type Gitlab interface {
+	Description(number string) (string, error)
+	Labels() ([]string, error)
+	Project() (string, error)
 	MergeRequestByBranch(branch string) (title string, body string, err error)
+	CreateMergeRequest(mr MergeRequest) (url string, err error)
+}
+
+// MergeRequest describes a merge request to be opened on GitLab.
+// Source is the branch with the changes, Target is the branch to merge them into.
+type MergeRequest struct {
+	Title  string
+	Body   string
+	Labels []string
+	Source string
+	Target string
+	Draft  bool
 }
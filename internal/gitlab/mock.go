@@ -10,6 +10,25 @@ func NewMock() *MockGitlab {
 	return &MockGitlab{Error: nil}
 }
 
+func (m *MockGitlab) Description(number string) (string, error) {
+	return fmt.Sprintf("mock description for issue '#%s'", number), m.Error
+}
+
+func (m *MockGitlab) Labels() ([]string, error) {
+	return []string{"bug", "documentation", "question"}, m.Error
+}
+
+func (m *MockGitlab) Project() (string, error) {
+	return "mock/project", m.Error
+}
+
 func (m *MockGitlab) MergeRequestByBranch(branch string) (string, string, error) {
 	return fmt.Sprintf("mock title for branch '%s'", branch), fmt.Sprintf("mock body for branch '%s'", branch), m.Error
 }
+
+func (m *MockGitlab) CreateMergeRequest(mr MergeRequest) (string, error) {
+	if m.Error != nil {
+		return "", m.Error
+	}
+	return fmt.Sprintf("https://gitlab.com/mock/project/-/merge_requests/%s", mr.Source), nil
+}
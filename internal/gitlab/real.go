@@ -1,35 +1,108 @@
 package gitlab
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
 
-	"github.com/volodya-lombrozo/aidy/internal/executor"
+	"github.com/volodya-lombrozo/aidy/internal/git"
 	"github.com/volodya-lombrozo/aidy/internal/log"
 )
 
 type real struct {
-	shell executor.Executor
-	log   log.Logger
+	client *http.Client
+	url    string
+	git    git.Git
+	token  string
+	log    log.Logger
+}
+
+type issue struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+type label struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
 }
 
 type mergeRequest struct {
+	Iid         int    `json:"iid"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
+	Url         string `json:"web_url"`
+}
+
+type newMergeRequest struct {
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	SourceBranch string `json:"source_branch"`
+	TargetBranch string `json:"target_branch"`
+	Labels       string `json:"labels,omitempty"`
+}
+
+// NewGitlab creates a client for the GitLab v4 API of the instance at url,
+// e.g. "https://gitlab.com" or a self-hosted one.
+// The project is found among the git remotes that point to the same host.
+func NewGitlab(url string, gs git.Git, token string) *real {
+	return &real{
+		client: &http.Client{},
+		url:    strings.TrimRight(url, "/"),
+		git:    gs,
+		token:  token,
+		log:    log.Default(),
+	}
+}
+
+func (r *real) Description(number string) (string, error) {
+	if _, err := strconv.Atoi(number); err != nil {
+		return fmt.Sprintf("invalid issue number: '%s'", number), nil
+	}
+	project, err := r.Project()
+	if err != nil {
+		return "", err
+	}
+	var task issue
+	if err := r.get(fmt.Sprintf("%s/issues/%s", r.api(project), number), &task); err != nil {
+		return "", fmt.Errorf("cannot retrieve issue #%s: %w", number, err)
+	}
+	r.log.Debug("retrieved issue description for #%s", number)
+	r.log.Debug("issue title: '%s'", task.Title)
+	r.log.Debug("issue body: '%s'", task.Description)
+	return fmt.Sprintf("Title: '%s'\nBody: '%s'", task.Title, task.Description), nil
 }
 
-func NewGitlab(shell executor.Executor) *real {
-	return &real{shell: shell, log: log.Default()}
+func (r *real) Labels() ([]string, error) {
+	project, err := r.Project()
+	if err != nil {
+		return nil, err
+	}
+	var labels []label
+	if err := r.get(fmt.Sprintf("%s/labels?per_page=100", r.api(project)), &labels); err != nil {
+		return nil, fmt.Errorf("cannot retrieve labels: %w", err)
+	}
+	var res []string
+	for _, label := range labels {
+		res = append(res, label.Name)
+	}
+	return res, nil
 }
 
 func (r *real) MergeRequestByBranch(branch string) (string, string, error) {
-	out, err := r.shell.RunCommand("glab", "mr", "list", "--source-branch", branch, "--all", "--output", "json")
+	project, err := r.Project()
 	if err != nil {
-		return "", "", fmt.Errorf("error fetching merge requests for branch '%s': %w", branch, err)
+		return "", "", err
 	}
 	var mrs []mergeRequest
-	if err := json.Unmarshal([]byte(out), &mrs); err != nil {
-		return "", "", fmt.Errorf("error parsing merge request json for branch '%s': %w", branch, err)
+	url := fmt.Sprintf("%s/merge_requests?source_branch=%s&state=all", r.api(project), neturl.QueryEscape(branch))
+	if err := r.get(url, &mrs); err != nil {
+		return "", "", fmt.Errorf("error fetching merge requests for branch '%s': %w", branch, err)
 	}
 	if len(mrs) == 0 {
 		return "", "", fmt.Errorf("no merge request found for branch '%s'", branch)
@@ -41,3 +114,138 @@ func (r *real) MergeRequestByBranch(branch string) (string, string, error) {
 	r.log.Debug("found a merge request '%s' for branch '%s'", mr.Title, branch)
 	return mr.Title, mr.Description, nil
 }
+
+func (r *real) CreateMergeRequest(mr MergeRequest) (string, error) {
+	if mr.Source == "" || mr.Target == "" {
+		return "", fmt.Errorf("both source and target branches are required to create a merge request, got source '%s' and target '%s'", mr.Source, mr.Target)
+	}
+	project, err := r.Project()
+	if err != nil {
+		return "", err
+	}
+	title := mr.Title
+	if mr.Draft && !strings.HasPrefix(title, "Draft:") {
+		title = "Draft: " + title
+	}
+	payload := newMergeRequest{
+		Title:        title,
+		Description:  mr.Body,
+		SourceBranch: mr.Source,
+		TargetBranch: mr.Target,
+		Labels:       strings.Join(mr.Labels, ","),
+	}
+	var res mergeRequest
+	if err := r.send("POST", fmt.Sprintf("%s/merge_requests", r.api(project)), payload, &res); err != nil {
+		return "", fmt.Errorf("cannot create a merge request: %w", err)
+	}
+	r.log.Debug("merge request !%d was created: %s", res.Iid, res.Url)
+	return res.Url, nil
+}
+
+// Project finds the path of the GitLab project (e.g. "group/subgroup/project")
+// among the git remotes that point to the configured GitLab host.
+func (r *real) Project() (string, error) {
+	host, err := neturl.Parse(r.url)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse gitlab url '%s': %w", r.url, err)
+	}
+	remotes, err := r.git.Remotes()
+	if err != nil {
+		return "", fmt.Errorf("cannot retrieve git remotes: %w", err)
+	}
+	var found []string
+	for _, remote := range remotes {
+		if path, ok := project(remote, host.Hostname()); ok {
+			found = append(found, path)
+		}
+	}
+	if len(found) == 0 {
+		return "", fmt.Errorf("cannot find a git remote pointing to '%s', remotes: %v", host.Hostname(), remotes)
+	}
+	if len(found) > 1 {
+		r.log.Warn("found %d gitlab projects in remotes, using the first one: '%s'", len(found), found[0])
+	}
+	return found[0], nil
+}
+
+// project extracts the project path from a remote url if the remote points to host.
+// Supported forms:
+//
+//	git@gitlab.com:group/project.git
+//	ssh://git@gitlab.com:2222/group/project.git
+//	https://gitlab.com/group/subgroup/project.git
+func project(remote string, host string) (string, bool) {
+	address := strings.TrimSpace(remote)
+	if i := strings.Index(address, "://"); i >= 0 {
+		address = address[i+3:]
+	}
+	if i := strings.Index(address, "@"); i >= 0 {
+		address = address[i+1:]
+	}
+	sep := strings.IndexAny(address, ":/")
+	if sep < 0 || address[:sep] != host {
+		return "", false
+	}
+	path := address[sep+1:]
+	if slash := strings.Index(path, "/"); slash > 0 {
+		if _, err := strconv.Atoi(path[:slash]); err == nil {
+			path = path[slash+1:]
+		}
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if !strings.Contains(path, "/") {
+		return "", false
+	}
+	return path, true
+}
+
+func (r *real) api(project string) string {
+	return fmt.Sprintf("%s/api/v4/projects/%s", r.url, neturl.PathEscape(project))
+}
+
+func (r *real) get(url string, out any) error {
+	return r.send("GET", url, nil, out)
+}
+
+// send performs a request to the GitLab API and decodes the JSON response into out.
+func (r *real) send(method string, url string, payload any, out any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("error marshaling request json: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	r.log.Debug("sending %s request to gitlab: %s", method, url)
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return fmt.Errorf("cannot create a new %s request: %w", method, err)
+	}
+	if r.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", r.token)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request to '%s': %w", url, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			r.log.Error("error closing response body: %v", err)
+		}
+	}()
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("unexpected response from '%s': '%s', %s", url, resp.Status, strings.TrimSpace(string(content)))
+	}
+	if err := json.Unmarshal(content, out); err != nil {
+		return fmt.Errorf("error unmarshaling response json: %w", err)
+	}
+	return nil
+}
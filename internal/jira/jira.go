@@ -0,0 +1,5 @@
+package jira
+
+type Jira interface {
+	Description(key string) (string, error)
+}
@@ -0,0 +1,15 @@
+package jira
+
+import "fmt"
+
+type MockJira struct {
+	Error error
+}
+
+func NewMock() *MockJira {
+	return &MockJira{Error: nil}
+}
+
+func (m *MockJira) Description(key string) (string, error) {
+	return fmt.Sprintf("mock description for issue '%s'", key), m.Error
+}
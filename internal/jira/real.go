@@ -0,0 +1,88 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/volodya-lombrozo/aidy/internal/log"
+)
+
+type real struct {
+	client *http.Client
+	url    string
+	user   string
+	token  string
+	log    log.Logger
+}
+
+type issue struct {
+	Key    string `json:"key"`
+	Fields fields `json:"fields"`
+}
+
+type fields struct {
+	Summary     string `json:"summary"`
+	Description string `json:"description"`
+}
+
+// NewJira creates a client for the Jira REST API at url.
+// If user is set, the token is used as an API token with basic authentication (Jira Cloud),
+// otherwise it is sent as a personal access token (Jira Server and Data Center).
+func NewJira(url string, user string, token string) *real {
+	return &real{
+		client: &http.Client{},
+		url:    strings.TrimRight(url, "/"),
+		user:   user,
+		token:  token,
+		log:    log.Default(),
+	}
+}
+
+func (r *real) Description(key string) (string, error) {
+	if !regexp.MustCompile(`^[A-Z][A-Z0-9]+-\d+$`).MatchString(key) {
+		return fmt.Sprintf("invalid issue key: '%s'", key), nil
+	}
+	if r.url == "" {
+		return "", fmt.Errorf("cannot retrieve issue '%s', jira url is not configured", key)
+	}
+	url := fmt.Sprintf("%s/rest/api/2/issue/%s?fields=summary,description", r.url, key)
+	r.log.Debug("trying to get an issue description using the following url: %s", url)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("cannot create a new GET request to retrieve issue description: %w", err)
+	}
+	if r.user != "" {
+		req.SetBasicAuth(r.user, r.token)
+	} else if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error fetching issue description: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			r.log.Error("error closing response body: %v", err)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("cannot retrieve issue using the following url: '%s'. response: '%s'", url, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading response body: %w", err)
+	}
+	var task issue
+	if err := json.Unmarshal(body, &task); err != nil {
+		return "", fmt.Errorf("error unmarshaling issue json: %w", err)
+	}
+	r.log.Debug("retrieved issue description for %s", key)
+	r.log.Debug("issue title: '%s'", task.Fields.Summary)
+	r.log.Debug("issue body: '%s'", task.Fields.Description)
+	return fmt.Sprintf("Title: '%s'\nBody: '%s'", task.Fields.Summary, task.Fields.Description), nil
+}
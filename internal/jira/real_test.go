@@ -0,0 +1,104 @@
+package jira
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const JsonIssue = `{
+  "key": "PROJ-42",
+  "fields": {
+    "summary": "Title",
+    "description": "Body"
+  }
+}`
+
+func TestRealJira_Description(t *testing.T) {
+	var path string
+	var user, password string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		user, password, _ = r.BasicAuth()
+		if _, err := w.Write([]byte(JsonIssue)); err != nil {
+			t.Errorf("Error writing response: %v", err)
+		}
+	}))
+	defer ts.Close()
+	jira := NewJira(ts.URL+"/", "me@example.com", "api-token")
+
+	description, err := jira.Description("PROJ-42")
+
+	require.NoError(t, err, "Description should not return an error")
+	assert.Equal(t, "Title: 'Title'\nBody: 'Body'", description)
+	assert.Equal(t, "/rest/api/2/issue/PROJ-42", path)
+	assert.Equal(t, "me@example.com", user, "Jira Cloud expects basic authentication")
+	assert.Equal(t, "api-token", password)
+}
+
+func TestRealJira_Description_PersonalToken(t *testing.T) {
+	var auth string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if _, err := w.Write([]byte(JsonIssue)); err != nil {
+			t.Errorf("Error writing response: %v", err)
+		}
+	}))
+	defer ts.Close()
+	jira := NewJira(ts.URL, "", "pat")
+
+	_, err := jira.Description("PROJ-42")
+
+	require.NoError(t, err, "Description should not return an error")
+	assert.Equal(t, "Bearer pat", auth)
+}
+
+func TestRealJira_Description_InvalidKey(t *testing.T) {
+	jira := NewJira("http://example.com", "", "")
+
+	description, err := jira.Description("42")
+
+	require.NoError(t, err, "Description should not return an error for an invalid key")
+	assert.Equal(t, "invalid issue key: '42'", description)
+}
+
+func TestRealJira_Description_NoUrl(t *testing.T) {
+	jira := NewJira("", "", "")
+
+	_, err := jira.Description("PROJ-42")
+
+	require.Error(t, err, "Description should return an error when jira url is missing")
+	assert.Contains(t, err.Error(), "jira url is not configured")
+}
+
+func TestRealJira_Description_NotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+	jira := NewJira(ts.URL, "", "")
+
+	description, err := jira.Description("PROJ-42")
+
+	require.Error(t, err, "Description should return an error for non-200 response")
+	assert.Contains(t, err.Error(), "response: '404 Not Found'")
+	assert.Empty(t, description)
+}
+
+func TestRealJira_Description_InvalidJson(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := w.Write([]byte("not-json")); err != nil {
+			t.Errorf("Error writing response: %v", err)
+		}
+	}))
+	defer ts.Close()
+	jira := NewJira(ts.URL, "", "")
+
+	_, err := jira.Description("PROJ-42")
+
+	require.Error(t, err, "Description should return an error for invalid json")
+	assert.Contains(t, err.Error(), "error unmarshaling issue json")
+}
@@ -32,21 +32,41 @@ func NewEditor(shell executor.Executor) *editor {
 }
 
 func (e *editor) Print(command string) error {
+	return e.review(command, nil)
+}
+
+func (e *editor) Submit(command string, send func() (string, error)) error {
+	return e.review(command, send)
+}
+
+func (e *editor) review(command string, send func() (string, error)) error {
 	cmd := prettyCommand(command)
 	fmt.Printf("\ngenerated command:\n%s\n", cmd)
 	reader := bufio.NewReader(e.in)
 	for {
-		e.printf("%s", "[r]un, [e]dit, [c]ancel, [p]rint? ")
+		if send != nil {
+			e.printf("%s", "[s]ubmit, [r]un, [e]dit, [c]ancel, [p]rint? ")
+		} else {
+			e.printf("%s", "[r]un, [e]dit, [c]ancel, [p]rint? ")
+		}
 		line, err := reader.ReadString('\n')
 		if err != nil {
 			e.printfErr("%s: %v\n", "Error reading input", err)
 			return err
 		}
 		choice := strings.ToLower(strings.TrimSpace(line))
-		switch choice {
-		case "r":
+		switch {
+		case choice == "s" && send != nil:
+			e.printf("submitting...\n")
+			url, err := send()
+			if err != nil {
+				return fmt.Errorf("failed to submit: %w", err)
+			}
+			e.printf("created: %s\n", url)
+			return nil
+		case choice == "r":
 			return e.run(cmd)
-		case "e":
+		case choice == "e":
 			updated, err := e.edit(cmd)
 			if err != nil {
 				return fmt.Errorf("failed to edit command: %w", err)
@@ -56,12 +76,18 @@ func (e *editor) Print(command string) error {
 			}
 			cmd = updated
 			e.printf("\nupdated command:\n%s\n", cmd)
-		case "c":
+			if send != nil {
+				e.printf("%s\n", "the edited command can't be submitted, run it instead.")
+				send = nil
+			}
+		case choice == "c":
 			e.printf("%s\n", "canceled.")
 			return nil
-		case "p":
+		case choice == "p":
 			e.printf("%s\n", cmd)
 			return nil
+		case send != nil:
+			e.printfErr("%s\n", "please type s, r, e, c, or p and press enter.")
 		default:
 			e.printfErr("%s\n", "please type r, e, c, or p and press enter.")
 		}
@@ -70,13 +96,16 @@ func (e *editor) Print(command string) error {
 
 func (e *editor) run(command string) error {
 	e.printf("running...\n")
-	parts := cleanQoutes(splitCommand(command))
-	_, err := e.shell.RunInteractively(parts[0], parts[1:]...)
+	parts, err := splitCommand(command)
 	if err != nil {
 		return err
-	} else {
-		return nil
 	}
+	if len(parts) == 0 {
+		return fmt.Errorf("there is no command to run")
+	}
+	parts = cleanQoutes(parts)
+	_, err = e.shell.RunInteractively(parts[0], parts[1:]...)
+	return err
 }
 
 func cleanQoutes(all []string) []string {
@@ -131,8 +160,13 @@ func (e *editor) edit(input string) (string, error) {
 	return string(edited), nil
 }
 
+// prettyCommand puts every flag of the command on its own line,
+// the command is shown as it is if it can't be split.
 func prettyCommand(command string) string {
-	parts := splitCommand(command)
+	parts, err := splitCommand(command)
+	if err != nil {
+		return command
+	}
 	var res strings.Builder
 	for i, p := range parts {
 		if strings.HasPrefix(p, "--") {
@@ -162,7 +196,9 @@ func findEditor(runtime string) string {
 	}
 }
 
-func splitCommand(input string) []string {
+// splitCommand splits the command into arguments the way a shell does, keeping their double quotes.
+// A backslash escapes a quote, a backtick, a space or another backslash, and stays as it is before anything else.
+func splitCommand(input string) ([]string, error) {
 	var args []string
 	var current strings.Builder
 	quoted := false
@@ -170,6 +206,9 @@ func splitCommand(input string) []string {
 	for _, ch := range strings.TrimSpace(input) {
 		switch {
 		case escaped:
+			if !strings.ContainsRune("\"`\\ ", ch) {
+				current.WriteRune('\\')
+			}
 			current.WriteRune(ch)
 			escaped = false
 		case ch == '\\':
@@ -189,10 +228,13 @@ func splitCommand(input string) []string {
 		}
 	}
 	if quoted {
-		panic(fmt.Sprintf("unclosed quote in string '%s'", input))
+		return nil, fmt.Errorf("unclosed quote in command '%s'", input)
+	}
+	if escaped {
+		current.WriteRune('\\')
 	}
 	if current.Len() > 0 {
 		args = append(args, current.String())
 	}
-	return args
+	return args, nil
 }
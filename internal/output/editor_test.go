@@ -146,6 +146,7 @@ func TestEditor_PrettyCommand(t *testing.T) {
 		{"--version", "\n  --version"},
 		{"echo hello world", "echo hello world"},
 		{"", ""},
+		{`gh issue create --title "unclosed`, `gh issue create --title "unclosed`},
 	}
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
@@ -216,8 +217,11 @@ func TestEditor_SplitCommand(t *testing.T) {
 		{`echo "hello 'world'"`, []string{"echo", "\"hello 'world'\""}},
 		{`echo hello\ world`, []string{"echo", "hello world"}},
 		{"echo    hello", []string{"echo", "hello"}},
-		{`echo "hello`, nil}, // This should panic
+		{`echo "hello`, nil}, // This should fail
+		{`echo "say "hi"`, nil},
 		{`echo hello\\world`, []string{"echo", "hello\\world"}},
+		{`echo "C:\Users\me" \n`, []string{"echo", `"C:\Users\me"`, `\n`}},
+		{`echo trailing\`, []string{"echo", `trailing\`}},
 		{`echo ""`, []string{"echo", "\"\""}},
 		{`echo $PATH`, []string{"echo", "$PATH"}},
 		{"git commit\n  --amend\n  --no-edit", []string{"git", "commit", "--amend", "--no-edit"}},
@@ -227,17 +231,110 @@ func TestEditor_SplitCommand(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.input, func(t *testing.T) {
-			defer func() {
-				if r := recover(); r != nil {
-					require.Nil(t, test.expected, "Unexpected panic for input %q", test.input)
-				}
-			}()
-			result := splitCommand(test.input)
+			result, err := splitCommand(test.input)
 			if test.expected == nil {
-				t.Errorf("Expected panic for input %q", test.input)
+				require.Error(t, err, "Expected an error for input %q", test.input)
 			} else {
+				require.NoError(t, err, "Unexpected error for input %q", test.input)
 				require.Equal(t, test.expected, result, "For input %q, expected %v, got %v", test.input, test.expected, result)
 			}
 		})
 	}
 }
+
+func TestEditor_Submit_SubmitOption(t *testing.T) {
+	input_r, input_w, _ := os.Pipe()
+	output_r, output_w, _ := os.Pipe()
+	shell := executor.NewMock()
+	editor := NewEditor(shell)
+	editor.in = input_r
+	editor.out = output_w
+	_, err := io.WriteString(input_w, "s\n")
+	require.NoError(t, err, "failed to write to pipe")
+	err = input_w.Close()
+	require.NoError(t, err, "failed to close write pipe")
+	sent := false
+
+	err = editor.Submit("gh pr create --title \"a title\" --body \"a \"quoted\" body\"", func() (string, error) {
+		sent = true
+		return "https://github.com/mock/remote/pull/1", nil
+	})
+
+	require.NoError(t, err, "Submit should not return an error")
+	err = output_w.Close()
+	require.NoError(t, err, "failed to close output pipe")
+	output, err := io.ReadAll(output_r)
+	require.NoError(t, err, "failed to read from output")
+	assert.True(t, sent, "expected the request to be sent")
+	assert.Contains(t, string(output), "[s]ubmit", "expected the submit option to be offered")
+	assert.Contains(t, string(output), "created: https://github.com/mock/remote/pull/1", "expected the link to be printed")
+	assert.Len(t, shell.Commands, 0, "expected no command to be run")
+}
+
+func TestEditor_Submit_SubmitOption_Error(t *testing.T) {
+	r, w, _ := os.Pipe()
+	editor := NewEditor(executor.NewMock())
+	editor.in = r
+	_, err := io.WriteString(w, "s\n")
+	require.NoError(t, err, "failed to write to pipe")
+	err = w.Close()
+	require.NoError(t, err, "failed to close write pipe")
+
+	err = editor.Submit("gh issue create --title t", func() (string, error) {
+		return "", fmt.Errorf("simulated error")
+	})
+
+	require.Error(t, err, "expected an error when submission fails")
+	assert.Contains(t, err.Error(), "failed to submit: simulated error")
+}
+
+func TestEditor_Submit_EditedCommandIsNotSubmitted(t *testing.T) {
+	input_r, input_w, _ := os.Pipe()
+	output_r, output_w, _ := os.Pipe()
+	editor := NewEditor(executor.NewMock())
+	editor.in = input_r
+	editor.out = output_w
+	editor.err = output_w
+	_, err := io.WriteString(input_w, "e\ns\nc\n")
+	require.NoError(t, err, "failed to write to pipe")
+	err = input_w.Close()
+	require.NoError(t, err, "failed to close write pipe")
+	sent := false
+
+	err = editor.Submit("gh issue create --title t", func() (string, error) {
+		sent = true
+		return "https://github.com/mock/remote/issues/1", nil
+	})
+
+	require.NoError(t, err, "Submit should not return an error")
+	err = output_w.Close()
+	require.NoError(t, err, "failed to close output pipe")
+	output, err := io.ReadAll(output_r)
+	require.NoError(t, err, "failed to read from output")
+	assert.False(t, sent, "expected the edited command not to be submitted")
+	assert.Contains(t, string(output), "the edited command can't be submitted, run it instead.")
+	assert.Contains(t, string(output), "please type r, e, c, or p and press enter.", "expected 's' to be refused after editing")
+}
+
+func TestEditor_Print_DoesNotOfferSubmit(t *testing.T) {
+	input_r, input_w, _ := os.Pipe()
+	output_r, output_w, _ := os.Pipe()
+	shell := executor.NewMock()
+	editor := NewEditor(shell)
+	editor.in = input_r
+	editor.out = output_w
+	_, err := io.WriteString(input_w, "s\nc\n")
+	require.NoError(t, err, "failed to write to pipe")
+	err = input_w.Close()
+	require.NoError(t, err, "failed to close write pipe")
+
+	err = editor.Print("echo hello")
+
+	require.NoError(t, err, "Print should not return an error")
+	err = output_w.Close()
+	require.NoError(t, err, "failed to close output pipe")
+	output, err := io.ReadAll(output_r)
+	require.NoError(t, err, "failed to read from output")
+	assert.NotContains(t, string(output), "[s]ubmit", "expected no submit option without a sender")
+	assert.Contains(t, string(output), "canceled", "expected 's' to be ignored")
+}
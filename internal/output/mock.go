@@ -3,9 +3,12 @@ package output
 import "strings"
 
 type Mock struct {
-	captured []string
-	EditErr  error
-	EditText string
+	captured  []string
+	EditErr   error
+	EditText  string
+	Hints     []string
+	Submitted bool
+	Created   string
 }
 
 func NewMock() *Mock {
@@ -17,6 +20,21 @@ func (m *Mock) Print(command string) error {
 	return nil
 }
 
+// Submit captures the command like Print does. If Submitted is set, it also
+// calls send and remembers the returned link in Created.
+func (m *Mock) Submit(command string, send func() (string, error)) error {
+	m.captured = append(m.captured, command)
+	if !m.Submitted {
+		return nil
+	}
+	url, err := send()
+	if err != nil {
+		return err
+	}
+	m.Created = url
+	return nil
+}
+
 func (m *Mock) Edit(text string) (string, error) {
 	m.captured = append(m.captured, text)
 	if m.EditErr != nil {
@@ -28,6 +46,20 @@ func (m *Mock) Edit(text string) (string, error) {
 	return text, nil
 }
 
+// Review captures the text and regenerates it once for every hint in Hints,
+// then it's accepted or changed the same way Edit does.
+func (m *Mock) Review(text string, regenerate func(hint string) (string, error)) (string, error) {
+	for _, hint := range m.Hints {
+		m.captured = append(m.captured, text)
+		regenerated, err := regenerate(hint)
+		if err != nil {
+			return "", err
+		}
+		text = regenerated
+	}
+	return m.Edit(text)
+}
+
 func (m *Mock) Captured() string {
 	if len(m.captured) == 0 {
 		return ""
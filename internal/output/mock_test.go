@@ -1,8 +1,10 @@
 package output
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -67,3 +69,43 @@ func TestMock_Edit_ReturnsConfiguredError(t *testing.T) {
 	require.ErrorIs(t, err, ErrCanceled, "expected the configured error to be returned")
 	require.Equal(t, "", result, "expected no text to be returned on error")
 }
+
+func TestMock_Review_RegeneratesForEveryHint(t *testing.T) {
+	mock := NewMock()
+	mock.Hints = []string{"shorter", "mention tests"}
+	var hints []string
+
+	result, err := mock.Review("v0", func(hint string) (string, error) {
+		hints = append(hints, hint)
+		return fmt.Sprintf("v%d", len(hints)), nil
+	})
+
+	require.NoError(t, err, "expected no error")
+	assert.Equal(t, "v2", result, "expected the last regenerated text to be accepted")
+	assert.Equal(t, []string{"shorter", "mention tests"}, hints)
+}
+
+func TestMock_Submit_CapturesWithoutSending(t *testing.T) {
+	mock := NewMock()
+	sent := false
+	err := mock.Submit("gh issue create --title t", func() (string, error) {
+		sent = true
+		return "url", nil
+	})
+	require.NoError(t, err, "expected no error")
+	require.False(t, sent, "expected the command not to be sent by default")
+	require.Equal(t, "gh issue create --title t", mock.Last(), "expected the command to be captured")
+}
+
+func TestMock_Submit_Sends(t *testing.T) {
+	mock := NewMock()
+	mock.Submitted = true
+	sent := false
+	err := mock.Submit("gh issue create --title \"a title\"", func() (string, error) {
+		sent = true
+		return "https://github.com/mock/remote/issues/1", nil
+	})
+	require.NoError(t, err, "expected no error")
+	require.True(t, sent, "expected the request to be sent")
+	require.Equal(t, "https://github.com/mock/remote/issues/1", mock.Created, "expected the link to be remembered")
+}
@@ -3,3 +3,13 @@ package output
 type Output interface {
 	Print(command string) error
 }
+
+// Submitter reviews a generated command the same way Output does, but can also
+// call send, which performs the action the command describes directly (e.g. through an API)
+// and returns a link to the created resource.
+// The command is only shown and may be edited and run, send never reads it,
+// so an edited command can't be submitted.
+type Submitter interface {
+	Output
+	Submit(command string, send func() (string, error)) error
+}
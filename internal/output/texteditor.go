@@ -16,6 +16,9 @@ var ErrCanceled = errors.New("canceled")
 
 type TextEditor interface {
 	Edit(text string) (string, error)
+	// Review lets the user accept, edit or regenerate the text.
+	// To regenerate the text, regenerate is called with an optional hint typed by the user.
+	Review(text string, regenerate func(hint string) (string, error)) (string, error)
 }
 
 type textEditor struct {
@@ -73,6 +76,51 @@ func (e *textEditor) Edit(text string) (string, error) {
 	}
 }
 
+func (e *textEditor) Review(text string, regenerate func(hint string) (string, error)) (string, error) {
+	e.printf("\n%s\n", text)
+	reader := bufio.NewReader(e.in)
+	for {
+		e.printf("%s", "[a]ccept, [e]dit, [r]egenerate, [c]ancel? ")
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			e.printfErr("%s: %v\n", "Error reading input", err)
+			return "", err
+		}
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "a":
+			return text, nil
+		case "e":
+			updated, err := e.edit(text)
+			if err != nil {
+				return "", fmt.Errorf("failed to edit text: %w", err)
+			}
+			if updated == "" {
+				return "", ErrCanceled
+			}
+			text = updated
+			e.printf("\nupdated:\n%s\n", text)
+		case "r":
+			e.printf("%s", "hint (optional): ")
+			hint, err := reader.ReadString('\n')
+			if err != nil {
+				e.printfErr("%s: %v\n", "Error reading input", err)
+				return "", err
+			}
+			regenerated, err := regenerate(strings.TrimSpace(hint))
+			if err != nil {
+				return "", fmt.Errorf("failed to regenerate text: %w", err)
+			}
+			text = regenerated
+			e.printf("\nregenerated:\n%s\n", text)
+		case "c":
+			e.printf("%s\n", "canceled.")
+			return "", ErrCanceled
+		default:
+			e.printfErr("%s\n", "please type a, e, r, or c and press enter.")
+		}
+	}
+}
+
 func (e *textEditor) edit(input string) (string, error) {
 	tmp, err := os.CreateTemp("", "aidy-edittext-*.txt")
 	if err != nil {
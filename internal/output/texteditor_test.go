@@ -163,3 +163,62 @@ func TestTextEditor_Edit_ReadError(t *testing.T) {
 	require.NoError(t, rerr, "failed to read from error output")
 	assert.Contains(t, string(output), "Error reading input", "expected an input-reading error message")
 }
+
+func TestTextEditor_Review_RegenerateOption(t *testing.T) {
+	input_r, input_w, _ := os.Pipe()
+	output_r, output_w, _ := os.Pipe()
+	editor := NewTextEditor(executor.NewMock())
+	editor.in = input_r
+	editor.out = output_w
+	_, err := io.WriteString(input_w, "r\nmention the cache\na\n")
+	require.NoError(t, err, "failed to write to pipe")
+	require.NoError(t, input_w.Close(), "failed to close write pipe")
+	var hints []string
+
+	result, err := editor.Review("feat: old", func(hint string) (string, error) {
+		hints = append(hints, hint)
+		return "feat: new", nil
+	})
+
+	require.NoError(t, err, "Review should not return an error")
+	assert.Equal(t, "feat: new", result, "expected the regenerated text to be accepted")
+	assert.Equal(t, []string{"mention the cache"}, hints, "expected the hint to be passed")
+	require.NoError(t, output_w.Close(), "failed to close output pipe")
+	output, err := io.ReadAll(output_r)
+	require.NoError(t, err, "failed to read from output")
+	assert.Contains(t, string(output), "[a]ccept, [e]dit, [r]egenerate, [c]ancel?")
+	assert.Contains(t, string(output), "regenerated:\nfeat: new")
+}
+
+func TestTextEditor_Review_RegenerateFails(t *testing.T) {
+	r, w, _ := os.Pipe()
+	editor := NewTextEditor(executor.NewMock())
+	editor.in = r
+	editor.out, _ = os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	_, err := io.WriteString(w, "r\n\n")
+	require.NoError(t, err, "failed to write to pipe")
+	require.NoError(t, w.Close(), "failed to close write pipe")
+
+	_, err = editor.Review("feat: old", func(hint string) (string, error) {
+		return "", fmt.Errorf("model is down")
+	})
+
+	require.Error(t, err, "expected an error when regeneration fails")
+	assert.Contains(t, err.Error(), "model is down")
+}
+
+func TestTextEditor_Review_CancelOption(t *testing.T) {
+	r, w, _ := os.Pipe()
+	editor := NewTextEditor(executor.NewMock())
+	editor.in = r
+	editor.out, _ = os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	_, err := io.WriteString(w, "c\n")
+	require.NoError(t, err, "failed to write to pipe")
+	require.NoError(t, w.Close(), "failed to close write pipe")
+
+	_, err = editor.Review("feat: old", func(hint string) (string, error) {
+		return "feat: new", nil
+	})
+
+	assert.ErrorIs(t, err, ErrCanceled, "expected a canceled error")
+}
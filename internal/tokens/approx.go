@@ -0,0 +1,21 @@
+package tokens
+
+import "math"
+
+// approx approximates tokens by the average number of characters a token takes.
+type approx struct {
+	chars float64
+}
+
+func (a approx) Count(text string) int {
+	return int(math.Ceil(float64(len([]rune(text))) / a.chars))
+}
+
+func (a approx) Trim(text string, limit int) string {
+	runes := []rune(text)
+	keep := int(float64(max(limit, 0)) * a.chars)
+	if len(runes) <= keep {
+		return text
+	}
+	return string(runes[:keep])
+}
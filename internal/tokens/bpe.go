@@ -0,0 +1,69 @@
+package tokens
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pkoukk/tiktoken-go"
+	loader "github.com/pkoukk/tiktoken-go-loader"
+	"github.com/volodya-lombrozo/aidy/internal/log"
+)
+
+func init() {
+	tiktoken.SetBpeLoader(loader.NewOfflineLoader())
+}
+
+// bpe counts tokens with the tiktoken encoding of OpenAI models.
+// The encoding is loaded on first use, since it takes a while;
+// if it can't be loaded, tokens are approximated.
+type bpe struct {
+	name     string
+	once     sync.Once
+	encoding *tiktoken.Tiktoken
+	fallback approx
+}
+
+func newBpe(name string) *bpe {
+	return &bpe{name: name, fallback: approx{chars: 4}}
+}
+
+// encoding returns the name of the encoding of the OpenAI model.
+func encoding(model string) string {
+	id := strings.ToLower(model)
+	if strings.HasPrefix(id, "gpt-3.5") || (strings.HasPrefix(id, "gpt-4") && !strings.HasPrefix(id, "gpt-4o") && !strings.HasPrefix(id, "gpt-4.")) {
+		return tiktoken.MODEL_CL100K_BASE
+	}
+	return tiktoken.MODEL_O200K_BASE
+}
+
+func (b *bpe) load() *tiktoken.Tiktoken {
+	b.once.Do(func() {
+		enc, err := tiktoken.GetEncoding(b.name)
+		if err != nil {
+			log.Default().Warn("failed to load '%s' encoding, tokens are approximated: %v", b.name, err)
+			return
+		}
+		b.encoding = enc
+	})
+	return b.encoding
+}
+
+func (b *bpe) Count(text string) int {
+	enc := b.load()
+	if enc == nil {
+		return b.fallback.Count(text)
+	}
+	return len(enc.EncodeOrdinary(text))
+}
+
+func (b *bpe) Trim(text string, limit int) string {
+	enc := b.load()
+	if enc == nil {
+		return b.fallback.Trim(text, limit)
+	}
+	tokens := enc.EncodeOrdinary(text)
+	if len(tokens) <= limit {
+		return text
+	}
+	return enc.Decode(tokens[:max(limit, 0)])
+}
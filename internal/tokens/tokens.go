@@ -0,0 +1,89 @@
+package tokens
+
+import (
+	"strings"
+)
+
+// Tokenizer counts the tokens of a text the way a model sees them.
+type Tokenizer interface {
+	Count(text string) int
+	Trim(text string, limit int) string
+}
+
+// Limit is the context window of a model with its tokenizer.
+type Limit struct {
+	Window    int
+	Tokenizer Tokenizer
+}
+
+// answer is the largest part of the window left for the answer of a model.
+const answer = 4096
+
+// For returns the limit of the model of the provider.
+// A positive window, e.g. the 'context-window' of the model configuration,
+// overrides the known window of the model.
+func For(provider, model string, window int) Limit {
+	if window <= 0 {
+		window = Window(provider, model)
+	}
+	return Limit{Window: window, Tokenizer: ForModel(provider, model)}
+}
+
+// Prompt is the number of tokens a prompt may take, leaving room for the answer.
+func (l Limit) Prompt() int {
+	return l.Window - min(answer, l.Window/4)
+}
+
+// Diff is the number of tokens a diff may take, leaving room for the rest of the prompt,
+// like the instructions, the issue and the project summary.
+func (l Limit) Diff() int {
+	return l.Prompt() * 3 / 4
+}
+
+// Fit cuts the prompt so that it fits the window.
+func (l Limit) Fit(prompt string) string {
+	return l.Tokenizer.Trim(prompt, l.Prompt())
+}
+
+// Window returns the known context window of the model of the provider.
+func Window(provider, model string) int {
+	id := strings.ToLower(model)
+	switch provider {
+	case "openai":
+		switch {
+		case strings.HasPrefix(id, "gpt-4.1"):
+			return 1047576
+		case strings.HasPrefix(id, "gpt-5"):
+			return 400000
+		case strings.HasPrefix(id, "o1"), strings.HasPrefix(id, "o3"), strings.HasPrefix(id, "o4"):
+			return 200000
+		case strings.HasPrefix(id, "gpt-3.5"):
+			return 16385
+		case id == "gpt-4" || strings.HasPrefix(id, "gpt-4-0"):
+			return 8192
+		default:
+			return 128000
+		}
+	case "anthropic":
+		return 200000
+	case "deepseek":
+		return 64000
+	case "ollama":
+		return 4096
+	default:
+		return 16000
+	}
+}
+
+// ForModel returns the tokenizer of the model of the provider:
+// the BPE encoding for OpenAI models and an approximation for the others.
+func ForModel(provider, model string) Tokenizer {
+	switch provider {
+	case "openai":
+		return newBpe(encoding(model))
+	case "anthropic":
+		return approx{chars: 3.5}
+	default:
+		return approx{chars: 4}
+	}
+}
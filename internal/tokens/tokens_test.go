@@ -0,0 +1,70 @@
+package tokens
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFor_UsesKnownWindow(t *testing.T) {
+	tests := []struct {
+		provider string
+		model    string
+		window   int
+	}{
+		{"openai", "gpt-4o", 128000},
+		{"openai", "gpt-4.1-mini", 1047576},
+		{"openai", "gpt-4", 8192},
+		{"anthropic", "claude-sonnet-4-0", 200000},
+		{"deepseek", "", 64000},
+		{"ollama", "llama3", 4096},
+		{"unknown", "model", 16000},
+	}
+	for _, test := range tests {
+		t.Run(test.provider+"/"+test.model, func(t *testing.T) {
+			assert.Equal(t, test.window, For(test.provider, test.model, 0).Window)
+		})
+	}
+}
+
+func TestFor_PrefersConfiguredWindow(t *testing.T) {
+	limit := For("ollama", "llama3", 32000)
+
+	assert.Equal(t, 32000, limit.Window)
+	assert.Equal(t, 27904, limit.Prompt(), "Expected room for the answer")
+	assert.Equal(t, 20928, limit.Diff(), "Expected room for the rest of the prompt")
+}
+
+func TestLimit_FitsPrompt(t *testing.T) {
+	limit := Limit{Window: 400, Tokenizer: approx{chars: 4}}
+	prompt := strings.Repeat("a", 2000)
+
+	fitted := limit.Fit(prompt)
+
+	assert.Equal(t, 1200, len(fitted), "Expected 300 tokens of 4 characters to be kept")
+	assert.Equal(t, "short", limit.Fit("short"))
+}
+
+func TestBpe_CountsOpenAiTokens(t *testing.T) {
+	tokenizer := ForModel("openai", "gpt-4o")
+
+	assert.Equal(t, 2, tokenizer.Count("hello world"))
+	assert.Equal(t, "hello", tokenizer.Trim("hello world", 1))
+	assert.Equal(t, "hello world", tokenizer.Trim("hello world", 5))
+}
+
+func TestEncoding(t *testing.T) {
+	assert.Equal(t, "cl100k_base", encoding("gpt-4-turbo"))
+	assert.Equal(t, "cl100k_base", encoding("gpt-3.5-turbo"))
+	assert.Equal(t, "o200k_base", encoding("gpt-4o-mini"))
+	assert.Equal(t, "o200k_base", encoding("gpt-4.1"))
+	assert.Equal(t, "o200k_base", encoding("o3-mini"))
+}
+
+func TestApprox_CountsRunes(t *testing.T) {
+	tokenizer := ForModel("anthropic", "claude")
+
+	assert.Equal(t, 2, tokenizer.Count("привет"), "Expected runes, not bytes, to be counted")
+	assert.Equal(t, 0, tokenizer.Count(""))
+}
@@ -0,0 +1,8 @@
+package tracker
+
+// Tracker is an issue tracker that aidy can read issue descriptions from.
+// GitHub, GitLab and Jira clients implement it, and the one to use
+// is chosen per repository with the 'issue-tracker' configuration key.
+type Tracker interface {
+	Description(number string) (string, error)
+}